@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/rs/zerolog/log"
@@ -18,6 +19,7 @@ var AuthManager *CredentialManager
 type InitConfig struct {
 	Profile ProfileConfig `mapstructure:"profile"`
 	Logger  LogConfig     `mapstructure:"log"`
+	Pricing PricingConfig `mapstructure:"pricing"`
 }
 
 type ProfileConfig struct {
@@ -29,6 +31,18 @@ type LogConfig struct {
 	File       LumberConfig `mapstructure:",squash"`
 }
 
+// PricingConfig is the price table used to estimate the cost of transfers.
+type PricingConfig struct {
+	// Egress is the price per GB of data leaving each provider
+	Egress map[string]float64 `mapstructure:"egress"`
+}
+
+// EgressPrice returns the configured egress price per GB of provider.
+func EgressPrice(provider string) (float64, bool) {
+	price, ok := Settings.Pricing.Egress[strings.ToLower(provider)]
+	return price, ok
+}
+
 type ZeroConfig struct {
 	LogLevel  string `mapstructure:"level"`
 	LogWriter string `mapstructure:"writer"`
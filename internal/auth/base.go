@@ -21,6 +21,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
 	"time"
@@ -38,10 +40,12 @@ import (
 	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/s3fs"
 	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/tencentfs"
 	"github.com/cloud-barista/mc-data-manager/pkg/rdbms/mysql"
+	"github.com/cloud-barista/mc-data-manager/pkg/rdbms/postgres"
 	"github.com/cloud-barista/mc-data-manager/service/nrdbc"
 	"github.com/cloud-barista/mc-data-manager/service/osc"
 	"github.com/cloud-barista/mc-data-manager/service/rdbc"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/rs/zerolog/log"
 )
 
@@ -115,7 +119,7 @@ func PreRun(task string, datamoldParams *models.CommandTask, use string) {
 	log.Info().Msgf("successful pre-check %s into %s", use, task)
 }
 
-func GetOS(params *models.ProviderConfig) (*osc.OSController, error) {
+func GetOS(params *models.ProviderConfig, opts ...osc.Option) (*osc.OSController, error) {
 	var OSC *osc.OSController
 	log.Info().Int64("CredentialId", params.CredentialId).Msg("GetOS")
 	log.Info().Str("Provider", params.Provider).Msg("GetOS")
@@ -149,7 +153,7 @@ func GetOS(params *models.ProviderConfig) (*osc.OSController, error) {
 			return nil, fmt.Errorf("NewS3Client error : %v", err)
 		}
 
-		OSC, err = osc.New(s3fs.New(models.AWS, s3c, params.Bucket, params.Region))
+		OSC, err = osc.New(s3fs.New(models.AWS, s3c, params.Bucket, params.Region), opts...)
 		if err != nil {
 			return nil, fmt.Errorf("osc error : %v", err)
 		}
@@ -177,7 +181,7 @@ func GetOS(params *models.ProviderConfig) (*osc.OSController, error) {
 			return nil, fmt.Errorf("NewGCPClient error : %v", err)
 		}
 
-		OSC, err = osc.New(gcpfs.New(gc, gcpc.ProjectID, params.Bucket, params.Region))
+		OSC, err = osc.New(gcpfs.New(gc, gcpc.ProjectID, params.Bucket, params.Region), opts...)
 		if err != nil {
 			return nil, fmt.Errorf("osc error : %v", err)
 		}
@@ -200,7 +204,7 @@ func GetOS(params *models.ProviderConfig) (*osc.OSController, error) {
 			return nil, fmt.Errorf("NewS3ClientWithEndpint error : %v", err)
 		}
 
-		OSC, err = osc.New(s3fs.New(models.NCP, s3c, params.Bucket, params.Region))
+		OSC, err = osc.New(s3fs.New(models.NCP, s3c, params.Bucket, params.Region), opts...)
 		if err != nil {
 			return nil, fmt.Errorf("osc error : %v", err)
 		}
@@ -222,28 +226,28 @@ func GetOS(params *models.ProviderConfig) (*osc.OSController, error) {
 		if err != nil {
 			return nil, fmt.Errorf("NewAlibabaClient error : %v", err)
 		}
-		OSC, err = osc.New(alibabafs.New(models.ALIBABA, ossc, "https://oss-"+params.Region+".aliyuncs.com", params.Bucket, params.Region))
+		OSC, err = osc.New(alibabafs.New(models.ALIBABA, ossc, "https://oss-"+params.Region+".aliyuncs.com", params.Bucket, params.Region), opts...)
 		if err != nil {
 			return nil, fmt.Errorf("osc error : %v", err)
 		}
 	case "ibm":
 		log.Info().Str("Region", params.Region).Msg("IBM Region")
 		log.Info().Str("BucketName", params.Bucket).Msg("IBM BucketName")
-		OSC, err = osc.New(ibmfs.New(models.IBM, params.Bucket, params.Region))
+		OSC, err = osc.New(ibmfs.New(models.IBM, params.Bucket, params.Region), opts...)
 		if err != nil {
 			return nil, fmt.Errorf("osc error : %v", err)
 		}
 	case "kt":
 		log.Info().Str("Region", params.Region).Msg("KT Region")
 		log.Info().Str("BucketName", params.Bucket).Msg("KT BucketName")
-		OSC, err = osc.New(ktfs.New(models.KT, params.Bucket, params.Region))
+		OSC, err = osc.New(ktfs.New(models.KT, params.Bucket, params.Region), opts...)
 		if err != nil {
 			return nil, fmt.Errorf("osc error : %v", err)
 		}
 	case "tencent":
 		log.Info().Str("Region", params.Region).Msg("Tencent Region")
 		log.Info().Str("BucketName", params.Bucket).Msg("Tencent BucketName")
-		OSC, err = osc.New(tencentfs.New(models.TENCENT, params.Bucket, params.Region))
+		OSC, err = osc.New(tencentfs.New(models.TENCENT, params.Bucket, params.Region), opts...)
 		if err != nil {
 			return nil, fmt.Errorf("osc error : %v", err)
 		}
@@ -253,27 +257,61 @@ func GetOS(params *models.ProviderConfig) (*osc.OSController, error) {
 
 	return OSC, nil
 }
-func GetRDMS(params *models.ProviderConfig) (*rdbc.RDBController, error) {
+func GetRDMS(params *models.ProviderConfig, opts ...rdbc.Option) (*rdbc.RDBController, error) {
 	log.Info().Str("Provider", params.Provider).Msg("GetRDMS")
 	log.Info().Str("Username", params.User).Msg("GetRDMS")
 	log.Info().Str("Password", params.Password).Msg("GetRDMS")
 	log.Info().Str("Host", params.Host).Msg("GetRDMS")
 	log.Info().Str("Port", params.Port).Msg("GetRDMS")
-	dst, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/?multiStatements=true", params.User, params.Password, params.Host, params.Port))
-	if err != nil {
-		return nil, fmt.Errorf("failed to open DB: %w", err)
+	log.Info().Str("Engine", params.Engine).Msg("GetRDMS")
+
+	switch rdbc.EngineType(params.Engine) {
+	case rdbc.Postgres:
+		// a postgres connection belongs to one database, so each database gets its own pool
+		open := func(dbName string) (*sql.DB, error) {
+			dsn := url.URL{
+				Scheme:   "postgres",
+				User:     url.UserPassword(params.User, params.Password),
+				Host:     net.JoinHostPort(params.Host, params.Port),
+				Path:     "/" + dbName,
+				RawQuery: "timezone=UTC",
+			}
+			return sql.Open("pgx", dsn.String())
+		}
+		dst, err := open("postgres")
+		if err != nil {
+			return nil, fmt.Errorf("failed to open DB: %w", err)
+		}
+		if err := ping(dst, params); err != nil {
+			return nil, err
+		}
+		return rdbc.New(postgres.New(models.Provider(params.Provider), dst, "postgres", open), opts...)
+	case rdbc.Mysql, "":
+		// rows are read and written in UTC, so TIMESTAMP values keep their instant between servers
+		dst, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/?multiStatements=true&time_zone=%%27%%2B00%%3A00%%27", params.User, params.Password, params.Host, params.Port))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open DB: %w", err)
+		}
+		if err := ping(dst, params); err != nil {
+			return nil, err
+		}
+		return rdbc.New(mysql.New(models.Provider(params.Provider), dst), opts...)
+	default:
+		return nil, fmt.Errorf("unsupported engine: %s", params.Engine)
 	}
+}
 
+func ping(db *sql.DB, params *models.ProviderConfig) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	if err := dst.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("failed to connect to DB (%s:%s): %w", params.Host, params.Port, err)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to connect to DB (%s:%s): %w", params.Host, params.Port, err)
 	}
-
-	return rdbc.New(mysql.New(models.Provider(params.Provider), dst))
+	return nil
 }
 
-func GetNRDMS(params *models.ProviderConfig) (*nrdbc.NRDBController, error) {
+func GetNRDMS(params *models.ProviderConfig, opts ...nrdbc.Option) (*nrdbc.NRDBController, error) {
 	var NRDBC *nrdbc.NRDBController
 	log.Info().Int64("CredentialId", params.CredentialId).Msg("GetNRDMS")
 	log.Info().Str("Provider", params.Provider).Msg("GetNRDMS")
@@ -301,7 +339,7 @@ func GetNRDMS(params *models.ProviderConfig) (*nrdbc.NRDBController, error) {
 			return nil, err
 		}
 
-		NRDBC, err = nrdbc.New(awsdnmdb.New(awsnrdb, params.Region))
+		NRDBC, err = nrdbc.New(awsdnmdb.New(awsnrdb, params.Region), opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -324,7 +362,7 @@ func GetNRDMS(params *models.ProviderConfig) (*nrdbc.NRDBController, error) {
 			return nil, err
 		}
 
-		NRDBC, err = nrdbc.New(gcpfsdb.New(gcpnrdb, params.Region))
+		NRDBC, err = nrdbc.New(gcpfsdb.New(gcpnrdb, params.Region), opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -342,7 +380,7 @@ func GetNRDMS(params *models.ProviderConfig) (*nrdbc.NRDBController, error) {
 		if err != nil {
 			return nil, err
 		}
-		NRDBC, err = nrdbc.New(ncpmgdb.New(ncpnrdb, params.DatabaseName))
+		NRDBC, err = nrdbc.New(ncpmgdb.New(ncpnrdb, params.DatabaseName), opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -360,7 +398,7 @@ func GetNRDMS(params *models.ProviderConfig) (*nrdbc.NRDBController, error) {
 		if err != nil {
 			return nil, err
 		}
-		NRDBC, err = nrdbc.New(alibabamgdb.New(alibabanrdb, params.DatabaseName))
+		NRDBC, err = nrdbc.New(alibabamgdb.New(alibabanrdb, params.DatabaseName), opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -90,29 +90,26 @@ func ExportRDMFunc(datamoldParams *models.CommandTask) error {
 		return err
 	}
 
-	var sqlData string
 	for _, db := range dbList {
-		sqlData = ""
 		log.Info().Msgf("Export start: %s", db)
-		if err := RDBC.Get(db, &sqlData); err != nil {
-			log.Error().Msgf("Get error : %v", err)
-			return err
-		}
-
 		file, err := os.Create(filepath.Join(datamoldParams.Directory, fmt.Sprintf("%s.sql", db)))
 		if err != nil {
 			log.Error().Msgf("File create error : %v", err)
 			return err
 		}
-		defer file.Close()
 
-		_, err = file.WriteString(sqlData)
-		if err != nil {
+		if err := RDBC.Get(db, file); err != nil {
+			log.Error().Msgf("Get error : %v", err)
+			file.Close()
+			os.Remove(file.Name())
+			return err
+		}
+
+		if err := file.Close(); err != nil {
 			log.Error().Msgf("File write error : %v", err)
 			return err
 		}
 		log.Info().Msgf("successfully exported : %s", file.Name())
-		file.Close()
 	}
 	log.Info().Msgf("successfully exported : %s", datamoldParams.Directory)
 	return nil
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import "time"
+
+// BackupEntry describes one object of a backup generation.
+type BackupEntry struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified time.Time `json:"lastModified"`
+	// Checksum is the SHA-256 of the backed up file
+	Checksum string `json:"checksum,omitempty"`
+	// Generation is the generation whose directory holds the data of the object
+	Generation string `json:"generation"`
+}
+
+// BackupGeneration describes one recorded backup run.
+type BackupGeneration struct {
+	ID          string           `json:"id"`
+	TaskID      string           `json:"taskId,omitempty"`
+	ServiceType CloudServiceType `json:"serviceType,omitempty"`
+	Source      string           `json:"source,omitempty"`
+	Incremental bool             `json:"incremental,omitempty"`
+	// Base is the previous generation an incremental generation builds on
+	Base       string    `json:"base,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	// Watermark is the newest LastModified of the source objects seen by the run
+	Watermark time.Time `json:"watermark"`
+
+	Objects    int   `json:"objects"`
+	Bytes      int64 `json:"bytes"`
+	NewObjects int   `json:"newObjects"`
+	NewBytes   int64 `json:"newBytes"`
+}
+
+// BackupManifest is the listing snapshot of a generation.
+// It holds every object of the point-in-time view, not only the new ones.
+type BackupManifest struct {
+	Generation string        `json:"generation"`
+	Entries    []BackupEntry `json:"entries"`
+}
+
+// BackupCatalog lists the generations of a backup directory, oldest first.
+type BackupCatalog struct {
+	Generations []BackupGeneration `json:"generations"`
+}
+
+// Latest returns the newest generation of the catalog.
+func (c *BackupCatalog) Latest() (BackupGeneration, bool) {
+	if len(c.Generations) == 0 {
+		return BackupGeneration{}, false
+	}
+	return c.Generations[len(c.Generations)-1], true
+}
+
+// RetentionPolicy selects the generations kept after a backup run.
+// Generations kept by any rule survive, and the latest generation is always kept.
+// A policy without any rule keeps every generation.
+type RetentionPolicy struct {
+	KeepLast    int `json:"keepLast,omitempty"`
+	KeepDaily   int `json:"keepDaily,omitempty"`
+	KeepWeekly  int `json:"keepWeekly,omitempty"`
+	KeepMonthly int `json:"keepMonthly,omitempty"`
+}
+
+// IsEmpty reports whether the policy has no rule.
+func (p RetentionPolicy) IsEmpty() bool {
+	return p.KeepLast <= 0 && p.KeepDaily <= 0 && p.KeepWeekly <= 0 && p.KeepMonthly <= 0
+}
@@ -46,6 +46,8 @@ type MySQLParams struct {
 	User         string `json:"username" form:"username"`
 	Password     string `json:"password" form:"password"`
 	DatabaseName string `json:"databaseName" form:"databaseName"`
+	// Engine is the database engine, mysql (default) or postgres
+	Engine string `json:"engine,omitempty" form:"engine"`
 }
 
 type ObjectStorageParams struct {
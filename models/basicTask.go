@@ -96,6 +96,63 @@ type BasicDataTask struct {
 	SourcePoint  ProviderConfig      `json:"sourcePoint,omitempty"`
 	TargetPoint  ProviderConfig      `json:"targetPoint,omitempty"`
 	SourceFilter *ObjectFilterParams `json:"sourceFilter,omitempty"`
+	// TargetPoints are additional object storage targets a migration copies to, reading the source once
+	TargetPoints []ProviderConfig `json:"targetPoints,omitempty"`
+	// ContinueOnError lets a fan-out migration finish the other targets when one target fails
+	ContinueOnError bool `json:"continueOnError,omitempty"`
+	// Verify compares source and target after an object storage migration
+	Verify bool `json:"verify,omitempty"`
+	// Transforms convert the objects of a migration; the first rule matching a key applies
+	Transforms []TransformRule `json:"transforms,omitempty"`
+	// PIIScan scans text objects for personal data before an object storage migration
+	PIIScan *PIIScan `json:"piiScan,omitempty"`
+	// Quota limits what an object storage task may transfer
+	Quota *TransferQuota `json:"quota,omitempty"`
+	// Preflight checks free space and write permission before a local backup: enforce (default), warn or off
+	Preflight PreflightMode `json:"preflight,omitempty"`
+	// ObjectLock makes the objects of a backup into object storage immutable
+	ObjectLock *ObjectLock `json:"objectLock,omitempty"`
+	// Incremental backs up only new or changed objects into a new backup generation
+	Incremental bool `json:"incremental,omitempty"`
+	// Retention records backups as generations in a catalog and prunes old generations
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+	// Dedup keeps object storage backups as snapshots in a content-addressed repository
+	Dedup bool `json:"dedup,omitempty"`
+	// PreserveMetadata keeps the mode, owner and symbolic links of local files in object metadata
+	// and applies them again on download. AWS and NCP then upload with the S3 client instead of Tumblebug presigned URLs
+	PreserveMetadata bool `json:"preserveMetadata,omitempty"`
+	// Generation selects the backup generation to restore
+	Generation string `json:"generation,omitempty"`
+	// PointInTime restores the newest backup generation taken at or before it
+	PointInTime *time.Time `json:"pointInTime,omitempty"`
+	// TargetPrefix places restored objects under a prefix of the target bucket
+	TargetPrefix string `json:"targetPrefix,omitempty"`
+	// Overwrite decides what a restore does with objects that already exist: overwrite (default), skip or newer
+	Overwrite OverwritePolicy `json:"overwrite,omitempty"`
+	// Conflict decides how a sync resolves objects changed on both sides: newest (default), source or keep-both
+	Conflict ConflictPolicy `json:"conflict,omitempty"`
+	// MaxPacketSize caps the size in bytes of the multi-row INSERTs of an RDBMS export, 1 MiB by default
+	MaxPacketSize int `json:"maxPacketSize,omitempty"`
+	// BulkLoad loads the rows of an RDBMS migration with LOAD DATA LOCAL INFILE when the target allows it
+	BulkLoad bool `json:"bulkLoad,omitempty"`
+	// DisableChecks turns off unique and foreign key checks while an RDBMS migration or restore imports
+	DisableChecks bool `json:"disableChecks,omitempty"`
+	// Workers is the number of tables, or key ranges of large tables, an RDBMS backup or migration exports in parallel
+	Workers int `json:"workers,omitempty"`
+	// ChunkRows splits tables with more rows into primary key ranges exported in parallel
+	ChunkRows int64 `json:"chunkRows,omitempty"`
+	// Consistency decides how an RDBMS backup or migration reads a live source: snapshot (default), lock or none
+	Consistency ConsistencyMode `json:"consistency,omitempty"`
+	// Definer replaces the definer of the views, routines, triggers and events of an RDBMS export, as user@host or CURRENT_USER
+	Definer string `json:"definer,omitempty"`
+	// Users migrates database users and their grants after an RDBMS backup or migration
+	Users *UserMigration `json:"users,omitempty"`
+	// Charset decides how an RDBMS backup or migration converts character sets and collations, utf8mb4 by default
+	Charset *CharsetPolicy `json:"charset,omitempty"`
+	// TranslateOnly stops an RDBMS migration between engines after its translation report, before anything is written
+	TranslateOnly bool `json:"translateOnly,omitempty"`
+	// Tables selects the tables of an RDBMS backup or migration by name, filters their rows and samples them
+	Tables *TableSelection `json:"tables,omitempty"`
 }
 type DiagnosticTask struct {
 	SysbenchParams
@@ -108,10 +165,68 @@ type DataTask struct {
 }
 type MigrateTask struct {
 	BasicTask
-	Directory    string              `json:"Directory,omitempty" swaggerignore:"true"`
+	Directory   string         `json:"Directory,omitempty" swaggerignore:"true"`
+	SourcePoint ProviderConfig `json:"sourcePoint,omitempty"`
+	TargetPoint ProviderConfig `json:"targetPoint,omitempty"`
+	// TargetPoints are more object storage targets; every source object is read once and streamed to all of them,
+	// with the per-target results at /migrate/{id}/targets
+	TargetPoints []ProviderConfig `json:"targetPoints,omitempty"`
+	// ContinueOnError lets the other targets finish when one target fails
+	ContinueOnError bool                `json:"continueOnError,omitempty"`
+	SourceFilter    *ObjectFilterParams `json:"sourceFilter,omitempty"`
+	// Verify compares source and target after an object storage migration
+	Verify bool `json:"verify,omitempty"`
+	// Transforms apply gzip, gunzip, csv-to-jsonl, to-utf8 and split to the objects matching a key pattern,
+	// with the written objects at /migrate/{id}/transforms
+	Transforms []TransformRule `json:"transforms,omitempty"`
+	// PIIScan scans CSV, JSON, XML and text objects for personal data before copying,
+	// with the findings at /migrate/{id}/pii
+	PIIScan *PIIScan `json:"piiScan,omitempty"`
+	// Quota limits the objects, bytes and estimated egress cost and fails or pauses the task when exceeded,
+	// with the usage at /tasks/{id}/quota
+	Quota *TransferQuota `json:"quota,omitempty"`
+	// MaxPacketSize caps the size in bytes of the multi-row INSERTs of an RDBMS migration, 1 MiB by default
+	MaxPacketSize int `json:"maxPacketSize,omitempty"`
+	// BulkLoad streams the rows as CSV into LOAD DATA LOCAL INFILE when the target has local_infile enabled
+	BulkLoad bool `json:"bulkLoad,omitempty"`
+	// DisableChecks turns off unique and foreign key checks during the import
+	DisableChecks bool `json:"disableChecks,omitempty"`
+	// Workers copies the rows of that many tables in parallel after the schema is created; a table starts
+	// once the tables it references are copied, unless checks are disabled
+	Workers int `json:"workers,omitempty"`
+	// ChunkRows splits tables with more rows into primary key ranges copied in parallel
+	ChunkRows int64 `json:"chunkRows,omitempty"`
+	// Consistency is snapshot (default) to read every table from one consistent snapshot of the source,
+	// lock to also hold a global read lock until the copy ends, or none to read each table as it is
+	Consistency ConsistencyMode `json:"consistency,omitempty"`
+	// Definer replaces the definers of the functions, procedures, views, triggers and events, as user@host or CURRENT_USER
+	Definer string `json:"definer,omitempty"`
+	// Users migrates the named users, or those with grants on the database, with their grants;
+	// grants the target refuses are logged and counted as failed
+	Users *UserMigration `json:"users,omitempty"`
+	// Charset converts character sets and collations, to utf8mb4 by default; indexes whose keys would
+	// become too long stop the task, with the report at /tasks/{id}/charset
+	Charset *CharsetPolicy `json:"charset,omitempty"`
+	// TranslateOnly stops a migration between engines after its translation report at /migrate/{id}/translation
+	TranslateOnly bool `json:"translateOnly,omitempty"`
+	// Tables selects the tables by name, filters their rows and samples them
+	Tables *TableSelection `json:"tables,omitempty"`
+}
+
+type VerifyTask struct {
+	BasicTask
+	SourcePoint  ProviderConfig      `json:"sourcePoint,omitempty"`
+	TargetPoint  ProviderConfig      `json:"targetPoint,omitempty"`
+	SourceFilter *ObjectFilterParams `json:"sourceFilter,omitempty"`
+}
+
+type SyncTask struct {
+	BasicTask
 	SourcePoint  ProviderConfig      `json:"sourcePoint,omitempty"`
 	TargetPoint  ProviderConfig      `json:"targetPoint,omitempty"`
 	SourceFilter *ObjectFilterParams `json:"sourceFilter,omitempty"`
+	Conflict     ConflictPolicy      `json:"conflict,omitempty"`
+	Quota        *TransferQuota      `json:"quota,omitempty"`
 }
 
 type BasicBackupTask struct {
@@ -125,6 +240,44 @@ type BackupTask struct {
 	SourcePoint  ProviderConfig      `json:"sourcePoint,omitempty"`
 	TargetPoint  ProviderConfig      `json:"targetPoint,omitempty"`
 	SourceFilter *ObjectFilterParams `json:"sourceFilter,omitempty"`
+	// Incremental backs up only new or changed objects into a new backup generation
+	Incremental bool `json:"incremental,omitempty"`
+	// Retention records backups as generations in a catalog and prunes old generations
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+	// Dedup stores objects as chunks in a content-addressed repository at the target path or bucket,
+	// and each run records a snapshot
+	Dedup bool `json:"dedup,omitempty"`
+	// PreserveMetadata keeps the mode, owner and symbolic links of local files in object metadata
+	// and applies them again when the backup is restored. AWS and NCP then upload with the S3 client instead of Tumblebug presigned URLs
+	PreserveMetadata bool `json:"preserveMetadata,omitempty"`
+	// Quota limits the objects, bytes and estimated egress cost and fails or pauses the task when exceeded
+	Quota *TransferQuota `json:"quota,omitempty"`
+	// Preflight estimates the backup size and checks free space and write permission on the target path
+	// before a local backup: enforce (default) refuses to start, warn only logs, off skips it
+	Preflight PreflightMode `json:"preflight,omitempty"`
+	// ObjectLock gives the objects of each snapshot written into a bucket a retention and/or a legal hold;
+	// targets that cannot lock objects are refused
+	ObjectLock *ObjectLock `json:"objectLock,omitempty"`
+	// MaxPacketSize caps the size in bytes of the multi-row INSERTs of an RDBMS export, 1 MiB by default
+	MaxPacketSize int `json:"maxPacketSize,omitempty"`
+	// Workers exports that many tables in parallel through spool files in the target path;
+	// the dump keeps the tables in foreign key order
+	Workers int `json:"workers,omitempty"`
+	// ChunkRows splits tables with more rows into primary key ranges exported in parallel
+	ChunkRows int64 `json:"chunkRows,omitempty"`
+	// Consistency is snapshot (default) to read every table from one consistent snapshot and record its
+	// binlog position and GTID set at the head of the dump, lock to also hold a global read lock until
+	// the export ends, or none to read each table as it is
+	Consistency ConsistencyMode `json:"consistency,omitempty"`
+	// Definer replaces the definers of the functions, procedures, views, triggers and events, as user@host or CURRENT_USER
+	Definer string `json:"definer,omitempty"`
+	// Users adds the named users, or those with grants on the database, with their grants
+	Users *UserMigration `json:"users,omitempty"`
+	// Charset converts character sets and collations, to utf8mb4 by default; indexes whose keys would
+	// become too long stop the task, with the report at /tasks/{id}/charset
+	Charset *CharsetPolicy `json:"charset,omitempty"`
+	// Tables selects the tables by name, filters their rows and samples them
+	Tables *TableSelection `json:"tables,omitempty"`
 }
 
 type RestoreTask struct {
@@ -0,0 +1,25 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+// BinlogPosition is the position in the binary log of the source at which an RDBMS export was read.
+type BinlogPosition struct {
+	File     string `json:"file,omitempty"`
+	Position int64  `json:"position,omitempty"`
+	GTIDSet  string `json:"gtidSet,omitempty"`
+	// Exact is false when writes may have happened between the snapshot and reading the position
+	Exact bool `json:"exact"`
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import "time"
+
+// CharsetPolicy decides how the character sets and collations of an RDBMS export are converted.
+type CharsetPolicy struct {
+	// Mode is force (default), preserve or map
+	Mode CharsetMode `json:"mode,omitempty"`
+	// Charset and Collation are what force converts to, utf8mb4 and utf8mb4_general_ci by default
+	Charset   string `json:"charset,omitempty"`
+	Collation string `json:"collation,omitempty"`
+	// Collations maps source collations, or character sets, to target collations for map;
+	// what it does not list is preserved
+	Collations map[string]string `json:"collations,omitempty"`
+}
+
+// CharsetIssue is an index whose key would exceed the InnoDB key length limit
+// once its columns are converted to the target character set.
+type CharsetIssue struct {
+	Table   string   `json:"table"`
+	Index   string   `json:"index"`
+	Columns []string `json:"columns"`
+	// KeyBytes is the length of the string columns of the key after the conversion
+	KeyBytes int64 `json:"keyBytes"`
+	MaxBytes int64 `json:"maxBytes"`
+}
+
+// CharsetReport is the result of the charset check of an RDBMS export, made before anything is written.
+type CharsetReport struct {
+	TaskID   string        `json:"taskId"`
+	Database string        `json:"database"`
+	Policy   CharsetPolicy `json:"policy"`
+	// Passed is false when an index is too long for the policy and the task did not start
+	Passed    bool           `json:"passed"`
+	Issues    []CharsetIssue `json:"issues"`
+	CheckedAt time.Time      `json:"checkedAt"`
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import "time"
+
+// DedupEntry describes one object of a deduplicated backup snapshot.
+type DedupEntry struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified time.Time `json:"lastModified"`
+	// Chunks are the SHA-256 hashes of the chunks of the object, in order
+	Chunks []string `json:"chunks"`
+}
+
+// DedupSnapshot is the index of one backup run in a deduplicating repository.
+// Every snapshot lists the complete object set, so it can be restored on its own.
+type DedupSnapshot struct {
+	BackupGeneration
+	// Chunks is the number of chunk references of the snapshot
+	Chunks int `json:"chunks"`
+	// NewChunks is the number of chunks the run had to store
+	NewChunks int `json:"newChunks"`
+
+	Entries []DedupEntry `json:"entries"`
+}
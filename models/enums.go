@@ -46,8 +46,135 @@ const (
 	StatusActive    Status = "active"
 	StatusInactive  Status = "inactive"
 	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCancelled Status = "cancelled"
 	StatusCompleted Status = "completed"
 	StatusFailed    Status = "failed"
+	StatusPaused    Status = "paused"
+)
+
+// Overwrite policy of a restore for objects that already exist
+type OverwritePolicy string
+
+const (
+	OverwriteAlways OverwritePolicy = "overwrite"
+	OverwriteSkip   OverwritePolicy = "skip"
+	OverwriteNewer  OverwritePolicy = "newer"
+)
+
+// Valid reports whether p is a known policy, or empty for the default.
+func (p OverwritePolicy) Valid() bool {
+	switch p {
+	case "", OverwriteAlways, OverwriteSkip, OverwriteNewer:
+		return true
+	}
+	return false
+}
+
+// ConflictPolicy decides how a two-way sync resolves an object changed on both sides
+type ConflictPolicy string
+
+const (
+	ConflictNewest   ConflictPolicy = "newest"
+	ConflictSource   ConflictPolicy = "source"
+	ConflictKeepBoth ConflictPolicy = "keep-both"
+)
+
+// Valid reports whether p is a known policy, or empty for the default.
+func (p ConflictPolicy) Valid() bool {
+	switch p {
+	case "", ConflictNewest, ConflictSource, ConflictKeepBoth:
+		return true
+	}
+	return false
+}
+
+// PIIPolicy decides what a migration does with objects in which personal data was found
+type PIIPolicy string
+
+const (
+	PIIBlock      PIIPolicy = "block"
+	PIIQuarantine PIIPolicy = "quarantine"
+	PIIAllow      PIIPolicy = "allow"
+)
+
+// QuotaAction decides what a task does when it exceeds its transfer quota
+type QuotaAction string
+
+const (
+	QuotaFail  QuotaAction = "fail"
+	QuotaPause QuotaAction = "pause"
+)
+
+// PreflightMode decides what a local backup does when its pre-flight check fails
+type PreflightMode string
+
+const (
+	PreflightEnforce PreflightMode = "enforce"
+	PreflightWarn    PreflightMode = "warn"
+	PreflightOff     PreflightMode = "off"
+)
+
+// Valid reports whether m is a known mode, or empty for the default.
+func (m PreflightMode) Valid() bool {
+	switch m {
+	case "", PreflightEnforce, PreflightWarn, PreflightOff:
+		return true
+	}
+	return false
+}
+
+// ConsistencyMode decides how an RDBMS export keeps its tables consistent with each other
+type ConsistencyMode string
+
+const (
+	// ConsistencySnapshot reads every table from one consistent snapshot
+	ConsistencySnapshot ConsistencyMode = "snapshot"
+	// ConsistencyLock also blocks writes with a global read lock until the export ends
+	ConsistencyLock ConsistencyMode = "lock"
+	// ConsistencyNone reads each table as it is when it is read
+	ConsistencyNone ConsistencyMode = "none"
+)
+
+// Valid reports whether m is a known mode, or empty for the default.
+func (m ConsistencyMode) Valid() bool {
+	switch m {
+	case "", ConsistencySnapshot, ConsistencyLock, ConsistencyNone:
+		return true
+	}
+	return false
+}
+
+// CharsetMode decides what an RDBMS export does with the character sets and collations of the source
+type CharsetMode string
+
+const (
+	// CharsetForce converts every character set and collation to the ones of the policy
+	CharsetForce CharsetMode = "force"
+	// CharsetPreserve keeps the character sets and collations of the source
+	CharsetPreserve CharsetMode = "preserve"
+	// CharsetMap converts the collations and character sets listed by the policy
+	CharsetMap CharsetMode = "map"
+)
+
+// TranslationLevel is how much a construct changes in a migration between database engines
+type TranslationLevel string
+
+const (
+	// TranslationLossy constructs are translated into something that behaves differently
+	TranslationLossy TranslationLevel = "lossy"
+	// TranslationUnsupported constructs are not translated and left out of the target
+	TranslationUnsupported TranslationLevel = "unsupported"
+)
+
+// ObjectLockMode is the retention mode of an immutable object
+type ObjectLockMode string
+
+const (
+	// ObjectLockGovernance can be lifted by users with a special permission
+	ObjectLockGovernance ObjectLockMode = "governance"
+	// ObjectLockCompliance cannot be lifted by anyone until the retention ends
+	ObjectLockCompliance ObjectLockMode = "compliance"
 )
 
 // Task type
@@ -58,4 +185,6 @@ const (
 	Migrate  TaskType = "migrate"
 	Backup   TaskType = "backup"
 	Restore  TaskType = "restore"
+	Verify   TaskType = "verify"
+	Sync     TaskType = "sync"
 )
@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import "time"
+
+// TargetStatus is the result of one destination of a fan-out migration.
+type TargetStatus struct {
+	Target  string `json:"target"`
+	Status  Status `json:"status"`
+	Copied  int    `json:"copied"`
+	Skipped int    `json:"skipped"`
+	Failed  int    `json:"failed"`
+	Bytes   int64  `json:"bytes"`
+	Error   string `json:"error,omitempty"`
+	// Verify is the verification summary of the target when verify is enabled
+	Verify *VerifySummary `json:"verify,omitempty"`
+}
+
+// FanOutReport describes a migration from one source to several targets.
+type FanOutReport struct {
+	TaskID      string         `json:"taskId"`
+	SourcePoint string         `json:"sourcePoint"`
+	StartedAt   time.Time      `json:"startedAt"`
+	FinishedAt  time.Time      `json:"finishedAt"`
+	Targets     []TargetStatus `json:"targets"`
+}
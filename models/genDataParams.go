@@ -31,6 +31,8 @@ type GenDataParams struct {
 type GenFileParams struct {
 	Directory string `json:"Directory,omitempty" swaggerignore:"true"`
 	DummyPath string `json:"dummyPath,omitempty" swaggerignore:"true"`
+	// SQLEngine is the dialect of the SQL dummy, mysql (default) or postgres
+	SQLEngine string `json:"-"`
 	FileFormatParams
 	FileSizeParams
 }
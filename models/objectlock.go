@@ -0,0 +1,30 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import "time"
+
+// ObjectLock is the retention or legal hold applied to the objects of a backup.
+type ObjectLock struct {
+	// Mode is governance or compliance; it is required with a retention
+	Mode ObjectLockMode `json:"mode,omitempty"`
+	// RetainUntil is the end of the retention
+	RetainUntil *time.Time `json:"retainUntil,omitempty"`
+	// RetainDays sets the end of the retention relative to the run, when RetainUntil is not set
+	RetainDays int `json:"retainDays,omitempty"`
+	// LegalHold keeps the objects until the hold is removed, regardless of the retention
+	LegalHold bool `json:"legalHold,omitempty"`
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+// PIIType is a kind of personal data
+type PIIType string
+
+const (
+	PIIEmail      PIIType = "email"
+	PIIPhone      PIIType = "phone"
+	PIICard       PIIType = "card"
+	PIINationalID PIIType = "national-id"
+)
+
+// PIIScan configures the personal data scan of a migration.
+type PIIScan struct {
+	// Policy is block (default), quarantine or allow
+	Policy PIIPolicy `json:"policy,omitempty"`
+	// QuarantinePrefix is the target prefix of quarantined objects, quarantine/ by default
+	QuarantinePrefix string `json:"quarantinePrefix,omitempty"`
+	// SampleBytes scans only the first bytes of every object; 0 scans whole objects
+	SampleBytes int64 `json:"sampleBytes,omitempty"`
+	// Types limits the scan to some kinds of personal data; empty scans all
+	Types []PIIType `json:"types,omitempty"`
+}
+
+// PIIFinding is the personal data of one kind found in an object.
+type PIIFinding struct {
+	Type  PIIType `json:"type"`
+	Count int     `json:"count"`
+	// Samples are masked examples of the matches
+	Samples []string `json:"samples,omitempty"`
+}
+
+// PIIObject is an object in which personal data was found.
+type PIIObject struct {
+	Key      string       `json:"key"`
+	Scanned  int64        `json:"scanned"`
+	Findings []PIIFinding `json:"findings"`
+	// Action is what the policy did with the object: blocked, quarantined or allowed
+	Action string `json:"action"`
+	// TargetKey is the key the object was migrated to
+	TargetKey string `json:"targetKey,omitempty"`
+}
+
+// PIIReport records the personal data found before a migration.
+type PIIReport struct {
+	TaskID  string      `json:"taskId"`
+	Policy  PIIPolicy   `json:"policy"`
+	Scanned int         `json:"scanned"`
+	Skipped int         `json:"skipped"`
+	Objects []PIIObject `json:"objects"`
+	Blocked bool        `json:"blocked"`
+}
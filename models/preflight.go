@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import "time"
+
+// PreflightProblem is a finding of a pre-flight check.
+type PreflightProblem struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Fatal problems make the check fail; the others are warnings
+	Fatal bool `json:"fatal"`
+}
+
+// PreflightReport is the result of the pre-flight check of a local backup.
+type PreflightReport struct {
+	TaskID string `json:"taskId"`
+	Path   string `json:"path"`
+	// EstimatedBytes is the estimated size of the backup
+	EstimatedBytes int64 `json:"estimatedBytes"`
+	// RequiredBytes is the estimate with a safety margin
+	RequiredBytes int64 `json:"requiredBytes"`
+	// FreeBytes is the space available to the user, -1 when it is unknown
+	FreeBytes int64              `json:"freeBytes"`
+	Writable  bool               `json:"writable"`
+	Passed    bool               `json:"passed"`
+	Problems  []PreflightProblem `json:"problems"`
+	CheckedAt time.Time          `json:"checkedAt"`
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import "time"
+
+// ProgressError is an error raised while a task is running.
+type ProgressError struct {
+	Name  string    `json:"name"`
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
+}
+
+// TaskProgress is a snapshot of the progress counters of a running task.
+type TaskProgress struct {
+	TaskID         string          `json:"taskId"`
+	Status         Status          `json:"status"`
+	Current        string          `json:"current,omitempty"`
+	ObjectsDone    int64           `json:"objectsDone"`
+	ObjectsTotal   int64           `json:"objectsTotal"`
+	ObjectsSkipped int64           `json:"objectsSkipped"`
+	ObjectsFailed  int64           `json:"objectsFailed"`
+	BytesDone      int64           `json:"bytesDone"`
+	BytesTotal     int64           `json:"bytesTotal"`
+	Throughput     float64         `json:"throughput"` // bytes per second
+	ETASeconds     int64           `json:"etaSeconds"` // -1 if unknown
+	StartedAt      time.Time       `json:"startedAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	FinishedAt     *time.Time      `json:"finishedAt,omitempty"`
+	Errors         []ProgressError `json:"errors"`
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+// TransferQuota limits what a task may transfer. A zero limit is not enforced.
+type TransferQuota struct {
+	MaxObjects int64 `json:"maxObjects,omitempty"`
+	MaxBytes   int64 `json:"maxBytes,omitempty"`
+	// MaxCost is the maximum estimated egress cost, in the currency of the configured price table
+	MaxCost float64 `json:"maxCost,omitempty"`
+	// OnExceed is fail (default) or pause; a paused task is not run again until it is resumed
+	OnExceed QuotaAction `json:"onExceed,omitempty"`
+}
+
+// ResumeParams resumes a task paused by its quota.
+type ResumeParams struct {
+	// Quota replaces the quota of the task when set
+	Quota *TransferQuota `json:"quota,omitempty"`
+}
+
+// QuotaUsage is what a task transferred or planned to transfer.
+type QuotaUsage struct {
+	Objects int64   `json:"objects"`
+	Bytes   int64   `json:"bytes"`
+	Cost    float64 `json:"cost"`
+}
+
+// QuotaReport records why a task exceeded its quota.
+type QuotaReport struct {
+	TaskID string        `json:"taskId"`
+	Quota  TransferQuota `json:"quota"`
+	// PricePerGB is the egress price of the source provider
+	PricePerGB float64    `json:"pricePerGB"`
+	Planned    QuotaUsage `json:"planned"`
+	Used       QuotaUsage `json:"used"`
+	// Phase is planning when the plan exceeded the quota, execution when the transfer did
+	Phase  string `json:"phase"`
+	Reason string `json:"reason"`
+	Status Status `json:"status"`
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+// SchemaObjectType is the kind of a database object other than a table
+type SchemaObjectType string
+
+const (
+	SchemaFunction  SchemaObjectType = "function"
+	SchemaProcedure SchemaObjectType = "procedure"
+	SchemaView      SchemaObjectType = "view"
+	SchemaTrigger   SchemaObjectType = "trigger"
+	SchemaEvent     SchemaObjectType = "event"
+	// SchemaSequence sets the value of a sequence after the rows are copied
+	SchemaSequence SchemaObjectType = "sequence"
+)
+
+// SchemaObject is a view, routine, trigger or event of a database.
+type SchemaObject struct {
+	Type SchemaObjectType `json:"type"`
+	Name string           `json:"name"`
+	// Drop removes the object from the target before it is created
+	Drop   string `json:"drop"`
+	Create string `json:"create"`
+}
+
+// UserMigration selects the database users whose accounts and grants are migrated.
+type UserMigration struct {
+	// Users are the user names to migrate; empty migrates the users with grants on the database
+	Users []string `json:"users,omitempty"`
+	// HostMap maps the hosts of the source accounts to hosts of the target, "*" maps every other host
+	HostMap map[string]string `json:"hostMap,omitempty"`
+}
+
+// Column is a column of a table with the data type reported by its database.
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+// TableSelection selects the tables and rows of an RDBMS backup or migration.
+type TableSelection struct {
+	// Include lists the tables to take, with * and ? wildcards; empty takes every table
+	Include []string `json:"include,omitempty"`
+	// Exclude lists the tables to leave out, with * and ? wildcards
+	Exclude []string `json:"exclude,omitempty"`
+	// Filters restrict the rows of tables; the first filter matching a table applies
+	Filters []TableFilter `json:"filters,omitempty"`
+	// Sample takes part of the rows of every table, after the filters
+	Sample *RowSample `json:"sample,omitempty"`
+}
+
+// TableFilter restricts the rows of the tables matching a pattern.
+type TableFilter struct {
+	// Table is a table name with * and ? wildcards
+	Table string `json:"table"`
+	// Where is the condition the rows must match, in the SQL of the source, e.g. created_at >= NOW() - INTERVAL 90 DAY.
+	// It is one expression: semicolons and comments outside quotes, and backslashes, are refused
+	Where string `json:"where"`
+}
+
+// RowSample takes a random part of the rows of a table. Exactly one of Rows and Percent is set.
+type RowSample struct {
+	// Rows takes at most this many rows of every table
+	Rows int64 `json:"rows,omitempty"`
+	// Percent takes about this percentage of the rows of every table, above 0 and up to 100
+	Percent float64 `json:"percent,omitempty"`
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import "time"
+
+// SyncVersion identifies the version of an object seen on one side of a sync.
+type SyncVersion struct {
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// SyncEntry is an object that was in sync on both sides after the last run.
+type SyncEntry struct {
+	Source SyncVersion `json:"source"`
+	Target SyncVersion `json:"target"`
+}
+
+// SyncState is kept between the runs of a sync task to tell changes from deletions.
+// The state only applies to the source and target it was recorded for.
+type SyncState struct {
+	TaskID      string               `json:"taskId"`
+	SourcePoint string               `json:"sourcePoint"`
+	TargetPoint string               `json:"targetPoint"`
+	LastSync    time.Time            `json:"lastSync"`
+	Objects     map[string]SyncEntry `json:"objects"`
+}
+
+// SyncAction is what a sync did with an object.
+type SyncAction string
+
+const (
+	SyncCopyToTarget   SyncAction = "copy-to-target"
+	SyncCopyToSource   SyncAction = "copy-to-source"
+	SyncDeleteOnTarget SyncAction = "delete-on-target"
+	SyncDeleteOnSource SyncAction = "delete-on-source"
+	SyncKeepBoth       SyncAction = "keep-both"
+)
+
+// SyncItem is an object changed by a sync.
+type SyncItem struct {
+	Key      string     `json:"key"`
+	Action   SyncAction `json:"action"`
+	Conflict bool       `json:"conflict,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// SyncSummary counts the changes of a sync run.
+type SyncSummary struct {
+	CopiedToTarget  int `json:"copiedToTarget"`
+	CopiedToSource  int `json:"copiedToSource"`
+	DeletedOnTarget int `json:"deletedOnTarget"`
+	DeletedOnSource int `json:"deletedOnSource"`
+	Conflicts       int `json:"conflicts"`
+	Unchanged       int `json:"unchanged"`
+	Failed          int `json:"failed"`
+}
+
+// SyncReport is the result of a sync run.
+type SyncReport struct {
+	TaskID      string         `json:"taskId"`
+	SourcePoint string         `json:"sourcePoint"`
+	TargetPoint string         `json:"targetPoint"`
+	Conflict    ConflictPolicy `json:"conflict"`
+	StartedAt   time.Time      `json:"startedAt"`
+	FinishedAt  time.Time      `json:"finishedAt"`
+	Summary     SyncSummary    `json:"summary"`
+	Items       []SyncItem     `json:"items"`
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+// TransformType is a step of a transform pipeline
+type TransformType string
+
+const (
+	TransformGzip       TransformType = "gzip"
+	TransformGunzip     TransformType = "gunzip"
+	TransformCSVToJSONL TransformType = "csv-to-jsonl"
+	TransformToUTF8     TransformType = "to-utf8"
+	TransformSplit      TransformType = "split"
+)
+
+// TransformStep is one step of a transform pipeline.
+type TransformStep struct {
+	Type TransformType `json:"type"`
+	// Charset is the source encoding of to-utf8, e.g. euc-kr, shift_jis, iso-8859-1 or utf-16le
+	Charset string `json:"charset,omitempty"`
+	// PartSize is the maximum size in bytes of the parts written by split
+	PartSize int64 `json:"partSize,omitempty"`
+	// Lines makes split cut parts at line ends only
+	Lines bool `json:"lines,omitempty"`
+}
+
+// TransformRule applies a transform pipeline to the objects whose key matches Match.
+// Match is a glob pattern; a pattern without a slash is matched against the base name of the key.
+type TransformRule struct {
+	Match string          `json:"match"`
+	Steps []TransformStep `json:"steps"`
+}
+
+// TransformOutput is an object written by a transform pipeline.
+type TransformOutput struct {
+	Key    string `json:"key"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// TransformItem records how a source object was transformed.
+type TransformItem struct {
+	SourceKey  string            `json:"sourceKey"`
+	SourceSize int64             `json:"sourceSize"`
+	Rule       string            `json:"rule"`
+	Outputs    []TransformOutput `json:"outputs"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// TransformReport lists the objects a migration transformed.
+type TransformReport struct {
+	TaskID string          `json:"taskId"`
+	Items  []TransformItem `json:"items"`
+}
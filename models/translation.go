@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+// TableSchema describes a table for its translation to another database engine.
+type TableSchema struct {
+	Name    string         `json:"name"`
+	Columns []ColumnSchema `json:"columns"`
+	Indexes []IndexSchema  `json:"indexes,omitempty"`
+	// ForeignKeys reference tables of the same database unless RefDatabase is set
+	ForeignKeys []ForeignKeySchema `json:"foreignKeys,omitempty"`
+	// Checks are the names of the CHECK constraints
+	Checks []string `json:"checks,omitempty"`
+	// AutoIncrement is the next value of the auto-increment column, 0 when there is none
+	AutoIncrement int64 `json:"autoIncrement,omitempty"`
+}
+
+// ColumnSchema describes a column of a table.
+type ColumnSchema struct {
+	Name string `json:"name"`
+	// DataType is the type name, ColumnType the full type with its length and attributes
+	DataType   string `json:"dataType"`
+	ColumnType string `json:"columnType"`
+	Nullable   bool   `json:"nullable"`
+	// Default is the default value or expression, nil when there is none
+	Default *string `json:"default,omitempty"`
+	// Extra holds auto_increment, on update and generated column attributes
+	Extra     string `json:"extra,omitempty"`
+	Collation string `json:"collation,omitempty"`
+}
+
+// IndexSchema describes an index of a table.
+type IndexSchema struct {
+	Name    string        `json:"name"`
+	Primary bool          `json:"primary,omitempty"`
+	Unique  bool          `json:"unique,omitempty"`
+	Type    string        `json:"type"`
+	Columns []IndexColumn `json:"columns"`
+}
+
+// IndexColumn is a column of an index.
+type IndexColumn struct {
+	// Name is empty for an expression
+	Name string `json:"name"`
+	// SubPart is the length of a prefix index, 0 for the whole column
+	SubPart int `json:"subPart,omitempty"`
+}
+
+// ForeignKeySchema describes a foreign key of a table.
+type ForeignKeySchema struct {
+	Name        string   `json:"name"`
+	Columns     []string `json:"columns"`
+	RefDatabase string   `json:"refDatabase,omitempty"`
+	RefTable    string   `json:"refTable"`
+	RefColumns  []string `json:"refColumns"`
+	OnDelete    string   `json:"onDelete"`
+	OnUpdate    string   `json:"onUpdate"`
+}
+
+// ValueConversion is how the values of a column change on their way to another engine.
+type ValueConversion string
+
+const (
+	// ConvertBoolean turns 0 into false and other numbers into true
+	ConvertBoolean ValueConversion = "boolean"
+	// ConvertBinary turns hex into binary data
+	ConvertBinary ValueConversion = "binary"
+	// ConvertGeometry turns SRID:WKB hex into the WKB as binary data
+	ConvertGeometry ValueConversion = "geometry"
+	// ConvertDate turns zero dates into NULL
+	ConvertDate ValueConversion = "date"
+	// ConvertTimestamp turns zero dates into NULL and marks the others as UTC
+	ConvertTimestamp ValueConversion = "timestamp"
+)
+
+// ColumnTranslation is a column copied to another engine.
+type ColumnTranslation struct {
+	Column
+	// TargetType is the type of the column in the target
+	TargetType string `json:"targetType"`
+	// Conversion is empty when the values are copied as they are
+	Conversion ValueConversion `json:"conversion,omitempty"`
+}
+
+// TableTranslation is a table copied to another engine.
+type TableTranslation struct {
+	Table   string              `json:"table"`
+	Target  string              `json:"target"`
+	Columns []ColumnTranslation `json:"columns"`
+}
+
+// TranslationIssue is a construct that a translation changes or leaves out.
+type TranslationIssue struct {
+	Level TranslationLevel `json:"level"`
+	// Object is the table, column, index or other object of the construct
+	Object    string `json:"object"`
+	Construct string `json:"construct"`
+	Detail    string `json:"detail"`
+}
+
+// TranslationReport is the plan of a migration between database engines,
+// made before anything is written to the target.
+type TranslationReport struct {
+	TaskID   string             `json:"taskId,omitempty"`
+	Source   string             `json:"source"`
+	Target   string             `json:"target"`
+	Database string             `json:"database"`
+	Tables   []TableTranslation `json:"tables"`
+	Issues   []TranslationIssue `json:"issues"`
+	// Schema creates the database and the tables before the rows are copied
+	Schema []string `json:"schema"`
+	// Finish creates the indexes and foreign keys and moves the identity sequences after the rows are copied
+	Finish []string `json:"finish"`
+	// Validate checks the copied rows against the foreign keys, unless the rows are filtered or sampled
+	Validate []string `json:"validate,omitempty"`
+}
+
+// Count returns the number of issues of a level.
+func (r *TranslationReport) Count(level TranslationLevel) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Level == level {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import "time"
+
+// Verify result type
+type VerifyResult string
+
+const (
+	VerifyMissingOnSource  VerifyResult = "missingOnSource"
+	VerifyMissingOnTarget  VerifyResult = "missingOnTarget"
+	VerifySizeMismatch     VerifyResult = "sizeMismatch"
+	VerifyChecksumMismatch VerifyResult = "checksumMismatch"
+)
+
+// VerifyItem describes a single object that differs between source and target.
+type VerifyItem struct {
+	Key        string       `json:"key"`
+	Result     VerifyResult `json:"result"`
+	SourceSize int64        `json:"sourceSize"`
+	TargetSize int64        `json:"targetSize"`
+	SourceETag string       `json:"sourceETag,omitempty"`
+	TargetETag string       `json:"targetETag,omitempty"`
+}
+
+// VerifySummary holds the counters of a verification run.
+type VerifySummary struct {
+	SourceObjects    int   `json:"sourceObjects"`
+	TargetObjects    int   `json:"targetObjects"`
+	SourceBytes      int64 `json:"sourceBytes"`
+	TargetBytes      int64 `json:"targetBytes"`
+	Matched          int   `json:"matched"`
+	MissingOnSource  int   `json:"missingOnSource"`
+	MissingOnTarget  int   `json:"missingOnTarget"`
+	SizeMismatch     int   `json:"sizeMismatch"`
+	ChecksumMismatch int   `json:"checksumMismatch"`
+	// ChecksumSkipped counts matched objects whose hashes were not comparable
+	ChecksumSkipped int `json:"checksumSkipped"`
+}
+
+// VerifyReport is the result of comparing two object storages.
+type VerifyReport struct {
+	TaskID      string        `json:"taskId,omitempty"`
+	SourcePoint string        `json:"sourcePoint"`
+	TargetPoint string        `json:"targetPoint"`
+	StartedAt   time.Time     `json:"startedAt"`
+	FinishedAt  time.Time     `json:"finishedAt"`
+	Summary     VerifySummary `json:"summary"`
+	Items       []VerifyItem  `json:"items"`
+}
+
+// Passed reports whether the target matches the source.
+func (r *VerifyReport) Passed() bool {
+	return r.Summary.MissingOnTarget == 0 &&
+		r.Summary.MissingOnSource == 0 &&
+		r.Summary.SizeMismatch == 0 &&
+		r.Summary.ChecksumMismatch == 0
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+// MetaDir holds the catalog and the manifests of a backup directory
+const MetaDir = ".backup"
+
+// HasCatalog reports whether dirPath holds a backup catalog.
+func HasCatalog(dirPath string) bool {
+	_, err := os.Stat(catalogPath(dirPath))
+	return err == nil
+}
+
+// LoadCatalog reads the catalog of a backup directory.
+// A directory without a catalog has no generation yet.
+func LoadCatalog(dirPath string) (*models.BackupCatalog, error) {
+	catalog := &models.BackupCatalog{Generations: []models.BackupGeneration{}}
+	data, err := os.ReadFile(catalogPath(dirPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return catalog, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, catalog); err != nil {
+		return nil, fmt.Errorf("invalid backup catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// SaveCatalog writes the catalog of a backup directory.
+func SaveCatalog(dirPath string, catalog *models.BackupCatalog) error {
+	return writeJSON(catalogPath(dirPath), catalog)
+}
+
+// LoadManifest reads the manifest of a generation.
+func LoadManifest(dirPath, genID string) (*models.BackupManifest, error) {
+	data, err := os.ReadFile(manifestPath(dirPath, genID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("backup generation %s not found", genID)
+		}
+		return nil, err
+	}
+	manifest := &models.BackupManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest %s: %w", genID, err)
+	}
+	return manifest, nil
+}
+
+// SaveManifest writes the manifest of a generation.
+func SaveManifest(dirPath string, manifest *models.BackupManifest) error {
+	return writeJSON(manifestPath(dirPath, manifest.Generation), manifest)
+}
+
+// Record saves the manifest of gen and appends gen to the catalog of dirPath.
+func Record(dirPath string, gen models.BackupGeneration, manifest *models.BackupManifest) error {
+	catalog, err := LoadCatalog(dirPath)
+	if err != nil {
+		return err
+	}
+	manifest.Generation = gen.ID
+	if err := SaveManifest(dirPath, manifest); err != nil {
+		return err
+	}
+	catalog.Generations = append(catalog.Generations, gen)
+	return SaveCatalog(dirPath, catalog)
+}
+
+// NewGenerationID returns a sortable generation id that is unique in catalog.
+func NewGenerationID(catalog *models.BackupCatalog, now time.Time) string {
+	base := now.UTC().Format("20060102T150405Z")
+	id := base
+	for n := 2; ; n++ {
+		if _, ok := Find(catalog, id); !ok {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// Find returns the generation with the given id.
+func Find(catalog *models.BackupCatalog, genID string) (models.BackupGeneration, bool) {
+	for _, gen := range catalog.Generations {
+		if gen.ID == genID {
+			return gen, true
+		}
+	}
+	return models.BackupGeneration{}, false
+}
+
+// Select picks the generation to restore.
+// A generation id wins over a point in time; without both the latest generation is used.
+// For a point in time the newest generation finished at or before it is used.
+func Select(catalog *models.BackupCatalog, genID string, at *time.Time) (models.BackupGeneration, error) {
+	if genID != "" {
+		gen, ok := Find(catalog, genID)
+		if !ok {
+			return models.BackupGeneration{}, fmt.Errorf("backup generation %s not found", genID)
+		}
+		return gen, nil
+	}
+
+	if at == nil {
+		gen, ok := catalog.Latest()
+		if !ok {
+			return models.BackupGeneration{}, errors.New("backup has no generation")
+		}
+		return gen, nil
+	}
+
+	var selected *models.BackupGeneration
+	for i := range catalog.Generations {
+		gen := &catalog.Generations[i]
+		if gen.FinishedAt.After(*at) {
+			continue
+		}
+		if selected == nil || gen.FinishedAt.After(selected.FinishedAt) {
+			selected = gen
+		}
+	}
+	if selected == nil {
+		return models.BackupGeneration{}, fmt.Errorf("no backup generation at or before %s", at.Format(time.RFC3339))
+	}
+	return *selected, nil
+}
+
+// FileEntries lists the files under genDir as manifest entries of genID with their checksums.
+func FileEntries(genDir, genID string) ([]models.BackupEntry, error) {
+	entries := []models.BackupEntry{}
+	err := filepath.WalkDir(genDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(genDir, path)
+		if err != nil {
+			return err
+		}
+		sum, err := Checksum(path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, models.BackupEntry{
+			Key:          filepath.ToSlash(rel),
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+			Checksum:     sum,
+			Generation:   genID,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+// Checksum returns the hex SHA-256 of a file.
+func Checksum(fileName string) (string, error) {
+	if info, err := os.Lstat(fileName); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		// a symbolic link is backed up as its target, not as the file it points to
+		target, err := os.Readlink(fileName)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256([]byte(target))
+		return hex.EncodeToString(sum[:]), nil
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func catalogPath(dirPath string) string {
+	return filepath.Join(dirPath, MetaDir, "catalog.json")
+}
+
+func manifestPath(dirPath, genID string) string {
+	return filepath.Join(dirPath, MetaDir, genID+".json")
+}
+
+// writeJSON writes v through a temporary file so a crash never leaves a truncated catalog.
+func writeJSON(fileName string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := fileName + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fileName)
+}
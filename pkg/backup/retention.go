@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+// Keep returns the ids of the generations kept by policy.
+//
+// KeepLast keeps the newest generations. KeepDaily, KeepWeekly and KeepMonthly
+// keep the newest generation of each of the most recent days, ISO weeks and
+// months that have a generation. The latest generation is always kept.
+func Keep(gens []models.BackupGeneration, policy models.RetentionPolicy) map[string]bool {
+	keep := map[string]bool{}
+	if len(gens) == 0 {
+		return keep
+	}
+
+	sorted := append([]models.BackupGeneration{}, gens...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].FinishedAt.After(sorted[j].FinishedAt)
+	})
+
+	if policy.IsEmpty() {
+		for _, gen := range sorted {
+			keep[gen.ID] = true
+		}
+		return keep
+	}
+
+	keep[sorted[0].ID] = true
+	for i := 0; i < policy.KeepLast && i < len(sorted); i++ {
+		keep[sorted[i].ID] = true
+	}
+
+	bucket := func(count int, key func(models.BackupGeneration) string) {
+		seen := map[string]bool{}
+		for _, gen := range sorted {
+			if len(seen) >= count {
+				return
+			}
+			k := key(gen)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			keep[gen.ID] = true
+		}
+	}
+	bucket(policy.KeepDaily, func(g models.BackupGeneration) string {
+		return g.FinishedAt.UTC().Format("2006-01-02")
+	})
+	bucket(policy.KeepWeekly, func(g models.BackupGeneration) string {
+		year, week := g.FinishedAt.UTC().ISOWeek()
+		return fmt.Sprintf("%d-%02d", year, week)
+	})
+	bucket(policy.KeepMonthly, func(g models.BackupGeneration) string {
+		return g.FinishedAt.UTC().Format("2006-01")
+	})
+	return keep
+}
+
+// Prune removes the generations of dirPath that are not kept by policy and returns their ids.
+//
+// Incremental generations share data, so the directory of a pruned generation is
+// only deleted when no kept manifest still refers to it.
+func Prune(dirPath string, policy models.RetentionPolicy) ([]string, error) {
+	catalog, err := LoadCatalog(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	keep := Keep(catalog.Generations, policy)
+
+	referenced := map[string]bool{}
+	kept := []models.BackupGeneration{}
+	pruned := []string{}
+	for _, gen := range catalog.Generations {
+		if !keep[gen.ID] {
+			pruned = append(pruned, gen.ID)
+			continue
+		}
+		kept = append(kept, gen)
+		manifest, err := LoadManifest(dirPath, gen.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range manifest.Entries {
+			referenced[entry.Generation] = true
+		}
+	}
+	if len(pruned) == 0 {
+		return pruned, nil
+	}
+
+	catalog.Generations = kept
+	if err := SaveCatalog(dirPath, catalog); err != nil {
+		return nil, err
+	}
+
+	for _, id := range pruned {
+		if err := os.Remove(manifestPath(dirPath, id)); err != nil && !os.IsNotExist(err) {
+			return pruned, err
+		}
+		if referenced[id] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dirPath, id)); err != nil {
+			return pruned, err
+		}
+	}
+	return pruned, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backup_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/backup"
+)
+
+func dailyGenerations(days int) []models.BackupGeneration {
+	start := time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)
+	gens := []models.BackupGeneration{}
+	for i := 0; i < days; i++ {
+		at := start.AddDate(0, 0, i)
+		gens = append(gens, models.BackupGeneration{ID: fmt.Sprintf("gen-%03d", i), FinishedAt: at})
+	}
+	return gens
+}
+
+func TestKeep(t *testing.T) {
+	gens := dailyGenerations(90)
+
+	tests := []struct {
+		name   string
+		policy models.RetentionPolicy
+		want   int
+	}{
+		{"empty policy keeps all", models.RetentionPolicy{}, 90},
+		{"keep last", models.RetentionPolicy{KeepLast: 5}, 5},
+		{"keep daily", models.RetentionPolicy{KeepDaily: 7}, 7},
+		// 2024-03-30, 2024-03-24 and 2024-03-17, the last days of their ISO weeks
+		{"keep weekly", models.RetentionPolicy{KeepWeekly: 3}, 3},
+		// 2024-03-30, 2024-02-29 and 2024-01-31
+		{"keep monthly", models.RetentionPolicy{KeepMonthly: 3}, 3},
+		// the weeks and months of the last 7 days overlap with the daily generations
+		{"gfs", models.RetentionPolicy{KeepDaily: 7, KeepWeekly: 4, KeepMonthly: 3}, 7 + 2 + 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep := backup.Keep(gens, tt.policy)
+			if len(keep) != tt.want {
+				t.Fatalf("kept %d generations, want %d: %v", len(keep), tt.want, keep)
+			}
+			if !keep["gen-089"] {
+				t.Fatal("latest generation must be kept")
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	catalog := &models.BackupCatalog{Generations: dailyGenerations(3)}
+
+	at := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	gen, err := backup.Select(catalog, "", &at)
+	if err != nil || gen.ID != "gen-001" {
+		t.Fatalf("Select(%v) = %v, %v", at, gen.ID, err)
+	}
+
+	gen, err = backup.Select(catalog, "", nil)
+	if err != nil || gen.ID != "gen-002" {
+		t.Fatalf("Select(latest) = %v, %v", gen.ID, err)
+	}
+
+	before := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+	if _, err := backup.Select(catalog, "", &before); err == nil {
+		t.Fatal("Select before the first generation must fail")
+	}
+}
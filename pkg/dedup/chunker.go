@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dedup
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// Default chunk sizes
+const (
+	DefaultMinChunkSize = 512 << 10
+	DefaultAvgChunkSize = 1 << 20
+	DefaultMaxChunkSize = 4 << 20
+)
+
+// gear maps every byte to a pseudo random value for the rolling hash.
+// The table must never change, or existing repositories stop deduplicating.
+var gear = func() [256]uint64 {
+	var table [256]uint64
+	seed := uint64(0x6d63646d64656475)
+	for i := range table {
+		// splitmix64
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+// Chunker splits a stream into content-defined chunks.
+//
+// Cut points are chosen by a gear rolling hash over the data, so an insert or
+// delete only changes the chunks around it and the rest of the stream still
+// produces the same chunks.
+type Chunker struct {
+	r    io.Reader
+	min  int
+	max  int
+	mask uint64
+
+	buf []byte
+	n   int
+	eof bool
+}
+
+// NewChunker returns a chunker that cuts chunks of min to max bytes,
+// avg bytes on average. avg is rounded down to a power of two.
+func NewChunker(r io.Reader, min, avg, max int) (*Chunker, error) {
+	if min <= 0 || avg < min || max < avg {
+		return nil, errors.New("invalid chunk sizes: want 0 < min <= avg <= max")
+	}
+	return &Chunker{
+		r:    r,
+		min:  min,
+		max:  max,
+		mask: uint64(1)<<(bits.Len(uint(avg))-1) - 1,
+		buf:  make([]byte, max),
+	}, nil
+}
+
+// Next returns the next chunk.
+// It returns io.EOF after the last chunk.
+func (c *Chunker) Next() ([]byte, error) {
+	if err := c.fill(); err != nil {
+		return nil, err
+	}
+	if c.n == 0 {
+		return nil, io.EOF
+	}
+
+	cut := c.cut()
+	chunk := make([]byte, cut)
+	copy(chunk, c.buf[:cut])
+	c.n = copy(c.buf, c.buf[cut:c.n])
+	return chunk, nil
+}
+
+// fill reads until the buffer is full or the stream ends.
+func (c *Chunker) fill() error {
+	for !c.eof && c.n < len(c.buf) {
+		n, err := c.r.Read(c.buf[c.n:])
+		c.n += n
+		if err == io.EOF {
+			c.eof = true
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cut returns the length of the next chunk in the buffer.
+func (c *Chunker) cut() int {
+	if c.n <= c.min {
+		return c.n
+	}
+	var h uint64
+	for i := c.min; i < c.n; i++ {
+		h = (h << 1) + gear[c.buf[i]]
+		if h&c.mask == 0 {
+			return i + 1
+		}
+	}
+	return c.n
+}
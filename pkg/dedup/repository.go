@@ -0,0 +1,341 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package dedup implements a content-addressed backup repository.
+//
+// Objects are split into content-defined chunks that are stored once by their
+// SHA-256 hash, and every backup run writes a snapshot index listing the
+// chunks of all of its objects. Unchanged data is shared between snapshots,
+// while every snapshot can still be restored on its own.
+package dedup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+const (
+	configName  = "config.json"
+	chunkDir    = "chunks"
+	snapshotDir = "snapshots"
+)
+
+// Config is stored with the repository.
+// The chunk sizes are fixed when the repository is created, so every snapshot cuts the same chunks.
+type Config struct {
+	Version      int       `json:"version"`
+	MinChunkSize int       `json:"minChunkSize"`
+	AvgChunkSize int       `json:"avgChunkSize"`
+	MaxChunkSize int       `json:"maxChunkSize"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// WriteResult describes an object written to the repository.
+type WriteResult struct {
+	Chunks    []string
+	Size      int64
+	NewChunks int
+	NewBytes  int64
+}
+
+type Option func(*Repository)
+
+// Repository is a deduplicating backup repository in a Store.
+type Repository struct {
+	store  Store
+	config Config
+
+	mu        sync.Mutex
+	chunks    map[string]bool
+	snapshots map[string]bool
+}
+
+// WithChunkSizes sets the chunk sizes of a new repository.
+// It has no effect on an existing repository.
+func WithChunkSizes(min, avg, max int) Option {
+	return func(r *Repository) {
+		r.config.MinChunkSize = min
+		r.config.AvgChunkSize = avg
+		r.config.MaxChunkSize = max
+	}
+}
+
+// Open opens the repository in store and initializes it when store is empty.
+func Open(store Store, opts ...Option) (*Repository, error) {
+	r := &Repository{
+		store: store,
+		config: Config{
+			Version:      1,
+			MinChunkSize: DefaultMinChunkSize,
+			AvgChunkSize: DefaultAvgChunkSize,
+			MaxChunkSize: DefaultMaxChunkSize,
+		},
+		chunks:    map[string]bool{},
+		snapshots: map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	objList, err := store.ObjectList()
+	if err != nil {
+		return nil, err
+	}
+	hasConfig := false
+	for _, obj := range objList {
+		switch {
+		case obj.Key == configName:
+			hasConfig = true
+		case strings.HasPrefix(obj.Key, chunkDir+"/"):
+			r.chunks[path.Base(obj.Key)] = true
+		case strings.HasPrefix(obj.Key, snapshotDir+"/") && strings.HasSuffix(obj.Key, ".json"):
+			r.snapshots[strings.TrimSuffix(path.Base(obj.Key), ".json")] = true
+		}
+	}
+
+	if hasConfig {
+		if err := r.readJSON(configName, &r.config); err != nil {
+			return nil, fmt.Errorf("repository config: %w", err)
+		}
+		return r, nil
+	}
+
+	if _, err := NewChunker(nil, r.config.MinChunkSize, r.config.AvgChunkSize, r.config.MaxChunkSize); err != nil {
+		return nil, err
+	}
+	r.config.CreatedAt = time.Now().UTC()
+	if err := r.writeJSON(configName, r.config); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Config returns the configuration of the repository.
+func (r *Repository) Config() Config {
+	return r.config
+}
+
+// WriteObject splits src into chunks and stores the chunks the repository does not hold yet.
+func (r *Repository) WriteObject(src io.Reader) (WriteResult, error) {
+	result := WriteResult{Chunks: []string{}}
+	chunker, err := NewChunker(src, r.config.MinChunkSize, r.config.AvgChunkSize, r.config.MaxChunkSize)
+	if err != nil {
+		return result, err
+	}
+
+	for {
+		chunk, err := chunker.Next()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+
+		sum := sha256.Sum256(chunk)
+		hash := hex.EncodeToString(sum[:])
+		result.Chunks = append(result.Chunks, hash)
+		result.Size += int64(len(chunk))
+
+		if r.hasChunk(hash) {
+			continue
+		}
+		if err := r.put(chunkName(hash), chunk); err != nil {
+			return result, err
+		}
+		r.mu.Lock()
+		r.chunks[hash] = true
+		r.mu.Unlock()
+		result.NewChunks++
+		result.NewBytes += int64(len(chunk))
+	}
+}
+
+// OpenObject returns a reader of the data of an entry.
+// Every chunk is checked against its hash while it is read.
+func (r *Repository) OpenObject(entry models.DedupEntry) io.ReadCloser {
+	return &objectReader{repo: r, chunks: entry.Chunks}
+}
+
+// SaveSnapshot writes the index of a snapshot.
+func (r *Repository) SaveSnapshot(snap *models.DedupSnapshot) error {
+	if snap.ID == "" {
+		return errors.New("snapshot has no id")
+	}
+	sort.Slice(snap.Entries, func(i, j int) bool {
+		return snap.Entries[i].Key < snap.Entries[j].Key
+	})
+	if err := r.writeJSON(snapshotName(snap.ID), snap); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.snapshots[snap.ID] = true
+	r.mu.Unlock()
+	return nil
+}
+
+// SnapshotObjects returns the store names of everything a snapshot needs to be
+// restored: the repository config, the snapshot index and every chunk it references.
+func (r *Repository) SnapshotObjects(snap *models.DedupSnapshot) []string {
+	names := []string{configName, snapshotName(snap.ID)}
+	seen := map[string]bool{}
+	for _, entry := range snap.Entries {
+		for _, hash := range entry.Chunks {
+			if !seen[hash] {
+				seen[hash] = true
+				names = append(names, chunkName(hash))
+			}
+		}
+	}
+	return names
+}
+
+// LoadSnapshot reads the index of a snapshot.
+func (r *Repository) LoadSnapshot(id string) (*models.DedupSnapshot, error) {
+	r.mu.Lock()
+	ok := r.snapshots[id]
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("snapshot %s not found", id)
+	}
+
+	snap := &models.DedupSnapshot{}
+	if err := r.readJSON(snapshotName(id), snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// Catalog lists the snapshots of the repository as backup generations, oldest first.
+func (r *Repository) Catalog() (*models.BackupCatalog, error) {
+	r.mu.Lock()
+	ids := make([]string, 0, len(r.snapshots))
+	for id := range r.snapshots {
+		ids = append(ids, id)
+	}
+	r.mu.Unlock()
+
+	catalog := &models.BackupCatalog{Generations: []models.BackupGeneration{}}
+	for _, id := range ids {
+		snap, err := r.LoadSnapshot(id)
+		if err != nil {
+			return nil, err
+		}
+		catalog.Generations = append(catalog.Generations, snap.BackupGeneration)
+	}
+	sort.Slice(catalog.Generations, func(i, j int) bool {
+		return catalog.Generations[i].StartedAt.Before(catalog.Generations[j].StartedAt)
+	})
+	return catalog, nil
+}
+
+func (r *Repository) hasChunk(hash string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.chunks[hash]
+}
+
+func (r *Repository) put(name string, data []byte) error {
+	w, err := r.store.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, bytes.NewReader(data)); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (r *Repository) get(name string) ([]byte, error) {
+	rc, err := r.store.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func (r *Repository) writeJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return r.put(name, data)
+}
+
+func (r *Repository) readJSON(name string, v any) error {
+	data, err := r.get(name)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func chunkName(hash string) string {
+	return path.Join(chunkDir, hash[:2], hash)
+}
+
+func snapshotName(id string) string {
+	return path.Join(snapshotDir, id+".json")
+}
+
+// objectReader reads the chunks of an object one after another.
+type objectReader struct {
+	repo   *Repository
+	chunks []string
+	buf    []byte
+}
+
+func (o *objectReader) Read(p []byte) (int, error) {
+	for len(o.buf) == 0 {
+		if len(o.chunks) == 0 {
+			return 0, io.EOF
+		}
+		hash := o.chunks[0]
+		o.chunks = o.chunks[1:]
+
+		data, err := o.repo.get(chunkName(hash))
+		if err != nil {
+			return 0, fmt.Errorf("chunk %s: %w", hash, err)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != hash {
+			return 0, fmt.Errorf("chunk %s is corrupted", hash)
+		}
+		o.buf = data
+	}
+	n := copy(p, o.buf)
+	o.buf = o.buf[n:]
+	return n, nil
+}
+
+func (o *objectReader) Close() error {
+	o.chunks = nil
+	o.buf = nil
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dedup_test
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/dedup"
+)
+
+func TestRepositoryDedup(t *testing.T) {
+	data := make([]byte, 256<<10)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	dir := t.TempDir()
+	repo, err := dedup.Open(dedup.NewDirStore(dir), dedup.WithChunkSizes(2<<10, 8<<10, 32<<10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := repo.WriteObject(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Size != int64(len(data)) || first.NewChunks != len(first.Chunks) {
+		t.Fatalf("first write = %d bytes, %d of %d new chunks", first.Size, first.NewChunks, len(first.Chunks))
+	}
+
+	// inserting bytes near the start must only change the chunks around the insert
+	edited := append(append(append([]byte{}, data[:1000]...), []byte("inserted")...), data[1000:]...)
+	second, err := repo.WriteObject(bytes.NewReader(edited))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.NewChunks > 2 {
+		t.Errorf("edit stored %d of %d chunks again", second.NewChunks, len(second.Chunks))
+	}
+
+	snap := &models.DedupSnapshot{Entries: []models.DedupEntry{{Key: "a.bin", Size: second.Size, Chunks: second.Chunks}}}
+	snap.ID = "s1"
+	if err := repo.SaveSnapshot(snap); err != nil {
+		t.Fatal(err)
+	}
+
+	// reopen with other chunk sizes, the stored config wins
+	repo, err = dedup.Open(dedup.NewDirStore(dir), dedup.WithChunkSizes(1<<10, 1<<10, 1<<10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Config().AvgChunkSize != 8<<10 {
+		t.Errorf("config = %+v", repo.Config())
+	}
+	loaded, err := repo.LoadSnapshot("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(repo.OpenObject(loaded.Entries[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, edited) {
+		t.Error("restored object differs from the written object")
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dedup
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+// Store keeps the chunks and snapshot indexes of a repository.
+// Every object storage file system satisfies it, and DirStore keeps a repository on local disk.
+type Store interface {
+	Open(name string) (io.ReadCloser, error)
+	Create(name string) (io.WriteCloser, error)
+	ObjectList() ([]*models.Object, error)
+}
+
+// DirStore is a Store in a local directory.
+type DirStore struct {
+	root string
+}
+
+// NewDirStore returns a store rooted at dirPath.
+func NewDirStore(dirPath string) *DirStore {
+	return &DirStore{root: dirPath}
+}
+
+// Open opens a file of the store.
+func (s *DirStore) Open(name string) (io.ReadCloser, error) {
+	return os.Open(s.path(name))
+}
+
+// Create opens a file of the store for writing.
+// The file only appears under its name when the writer is closed.
+func (s *DirStore) Create(name string) (io.WriteCloser, error) {
+	fileName := s.path(name)
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &dirWriter{File: file, name: fileName}, nil
+}
+
+// ObjectList lists the files of the store.
+func (s *DirStore) ObjectList() ([]*models.Object, error) {
+	objList := []*models.Object{}
+	if _, err := os.Stat(s.root); os.IsNotExist(err) {
+		return objList, nil
+	}
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		objList = append(objList, &models.Object{
+			Key:          filepath.ToSlash(rel),
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objList, nil
+}
+
+func (s *DirStore) path(name string) string {
+	return filepath.Join(s.root, filepath.FromSlash(name))
+}
+
+type dirWriter struct {
+	*os.File
+	name string
+}
+
+func (w *dirWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		_ = os.Remove(w.File.Name())
+		return err
+	}
+	return os.Rename(w.File.Name(), w.name)
+}
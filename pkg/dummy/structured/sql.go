@@ -121,60 +121,103 @@ INSERT INTO BorrowedBooks (MemberID, BookID, BorrowedDate, DueDate, ReturnedDate
 {{end}}
 `
 
-// SQL generation function using gofakeit
-//
-// CapacitySize is in GB and generates sql files
-// within the entered dummyDir path.
-func GenerateRandomSQL(dummyDir string, capacitySize int) error {
-	dummyDir = filepath.Join(dummyDir, "sql")
-	if err := utils.IsDir(dummyDir); err != nil {
-		log.Error().Msgf("IsDir function error : %v", err)
-		return err
-	}
+// createPostgresSql is createSql in the PostgreSQL dialect, connecting to the
+// database with \connect as psql does.
+const createPostgresSql string = `
+CREATE DATABASE {{ .DBName }};
 
-	size := capacitySize * 1000
+\connect {{ .DBName }}
 
-	countNum := make(chan int, size)
-	resultChan := make(chan error, size)
+DROP TABLE IF EXISTS Books;
 
-	var wg sync.WaitGroup
-	for i := 0; i < 10; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			randomSQLWorker(countNum, dummyDir, resultChan)
-		}()
-	}
+CREATE TABLE Books (
+	BookID SERIAL,
+	Title VARCHAR(255),
+	Author VARCHAR(255),
+	PublicationYear INT,
+	Publisher VARCHAR(255),
+	Quantity INT,
+	PRIMARY KEY (BookID)
+);
+{{range .Books}}
+INSERT INTO Books (Title, Author, PublicationYear, Publisher, Quantity) VALUES ('{{.Title}}', '{{.Author}}', {{.PublicationYear}}, '{{.Publisher}}', {{.Quantity}});
+{{end}}
 
-	for i := 0; i < size; i++ {
-		countNum <- i
-	}
-	close(countNum)
+DROP TABLE IF EXISTS Members;
 
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
+CREATE TABLE Members (
+	MemberID SERIAL,
+	Name VARCHAR(255),
+	Address VARCHAR(255),
+	PhoneNo VARCHAR(20),
+	Email VARCHAR(50),
+	JoinedDate DATE,
+	ExpiryDate DATE,
+	IsActive BOOLEAN DEFAULT TRUE,
+	PRIMARY KEY (MemberID)
+);
+{{range .Members}}
+INSERT INTO Members (Name, Address, PhoneNo ,Email ,JoinedDate ,ExpiryDate ,IsActive ) VALUES ('{{.Name}}', '{{.Address}}', '{{.PhoneNo}}' ,'{{.Email}}' ,'{{formatTime .JoinedDate}}' ,'{{formatTime .ExpiryDate}}' , {{if .IsActive }}TRUE {{else}}FALSE {{end}});
+{{end}}
 
-	for ret := range resultChan {
-		if ret != nil {
-			log.Error().Msgf("result error : %v", ret)
-			return ret
-		}
-	}
+DROP TABLE IF EXISTS BorrowedBooks;
 
-	return nil
+CREATE TABLE BorrowedBooks (
+	BorrowID SERIAL,
+	MemberID INT,
+	BookID INT,
+	BorrowedDate DATE,
+	DueDate DATE,
+	ReturnedDate DATE NULL DEFAULT NULL,
+	FinePaid DECIMAL(5,2) DEFAULT 0.00,
+	PRIMARY KEY (BorrowID)
+);
+{{range .BorrowedBooks}}
+INSERT INTO BorrowedBooks (MemberID, BookID, BorrowedDate, DueDate, ReturnedDate, FinePaid) VALUES ({{.MemberID}}, {{.BookID}}, '{{formatTime .BorrowedDate}}', '{{formatTime .DueDate}}', '{{formatTime .ReturnedDate}}', {{.FinePaid}});
+{{end}}
+`
+
+// sqlDialect is the template and database name format of the SQL dummy of an engine.
+type sqlDialect struct {
+	tmpl   string
+	dbName string
+}
+
+var (
+	mysqlDialect    = sqlDialect{tmpl: createSql, dbName: "LibraryManagement_%d"}
+	postgresDialect = sqlDialect{tmpl: createPostgresSql, dbName: "librarymanagement_%d"}
+)
+
+// SQL generation function using gofakeit
+//
+// CapacitySize is in GB and generates sql files
+// within the entered dummyDir path.
+func GenerateRandomSQL(dummyDir string, capacitySize int) error {
+	return generateRandomSQL(dummyDir, capacitySize*1000, mysqlDialect)
+}
+
+// GenerateRandomPostgresSQL is GenerateRandomSQL for PostgreSQL.
+func GenerateRandomPostgresSQL(dummyDir string, capacitySize int) error {
+	return generateRandomSQL(dummyDir, capacitySize*1000, postgresDialect)
 }
 
 func GenerateRandomSQLWithServer(dummyDir string, capacitySize int) error {
+	return generateRandomSQL(dummyDir, capacitySize, mysqlDialect)
+}
+
+// GenerateRandomPostgresSQLWithServer is GenerateRandomSQLWithServer for PostgreSQL.
+func GenerateRandomPostgresSQLWithServer(dummyDir string, capacitySize int) error {
+	return generateRandomSQL(dummyDir, capacitySize, postgresDialect)
+}
+
+// generateRandomSQL generates size sql files of a dialect within dummyDir.
+func generateRandomSQL(dummyDir string, size int, dialect sqlDialect) error {
 	dummyDir = filepath.Join(dummyDir, "sql")
 	if err := utils.IsDir(dummyDir); err != nil {
 		log.Error().Msgf("IsDir function error : %v", err)
 		return err
 	}
 
-	size := capacitySize
-
 	countNum := make(chan int, size)
 	resultChan := make(chan error, size)
 
@@ -183,7 +226,7 @@ func GenerateRandomSQLWithServer(dummyDir string, capacitySize int) error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			randomSQLWorker(countNum, dummyDir, resultChan)
+			randomSQLWorker(countNum, dummyDir, dialect, resultChan)
 		}()
 	}
 
@@ -208,14 +251,14 @@ func GenerateRandomSQLWithServer(dummyDir string, capacitySize int) error {
 }
 
 // sql worker
-func randomSQLWorker(countNum chan int, dirPath string, resultChan chan<- error) {
+func randomSQLWorker(countNum chan int, dirPath string, dialect sqlDialect, resultChan chan<- error) {
 	funcMap := template.FuncMap{
 		"formatTime": func(t time.Time) string {
 			return t.Format("2006-01-02")
 		},
 	}
 
-	tmpl, err := template.New("mysqlData").Funcs(funcMap).Parse(createSql)
+	tmpl, err := template.New("mysqlData").Funcs(funcMap).Parse(dialect.tmpl)
 	if err != nil {
 		resultChan <- err
 	}
@@ -223,7 +266,7 @@ func randomSQLWorker(countNum chan int, dirPath string, resultChan chan<- error)
 	for num := range countNum {
 
 		data := sqlData{}
-		data.DBName = fmt.Sprintf("LibraryManagement_%d", num)
+		data.DBName = fmt.Sprintf(dialect.dbName, num)
 
 		for i := 0; i < 2350; i++ {
 			book := books{}
@@ -245,7 +288,7 @@ func randomSQLWorker(countNum chan int, dirPath string, resultChan chan<- error)
 			continue
 		}
 
-		file, err := os.Create(filepath.Join(dirPath, fmt.Sprintf("LibraryManagement_%d.sql", num)))
+		file, err := os.Create(filepath.Join(dirPath, fmt.Sprintf(dialect.dbName+".sql", num)))
 		if err != nil {
 			resultChan <- err
 			continue
@@ -51,6 +51,11 @@ func New(client *mongo.Client, databaseName string, opts ...AlibabaMongoDBOption
 	return dms
 }
 
+// SetContext sets the context used by the requests of the database.
+func (a *AlibabaMongoDBMS) SetContext(ctx context.Context) {
+	a.ctx = ctx
+}
+
 // list table
 func (a *AlibabaMongoDBMS) ListTables() ([]string, error) {
 	return a.db.ListCollectionNames(a.ctx, bson.D{})
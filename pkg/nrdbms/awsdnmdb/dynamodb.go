@@ -76,6 +76,11 @@ func New(client *dynamodb.Client, region string, opts ...DynamoDBOption) *Dynamo
 	return dms
 }
 
+// SetContext sets the context used by the requests of the database.
+func (d *DynamoDBMS) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
 // Get table list
 func (d *DynamoDBMS) ListTables() ([]string, error) {
 	tables, err := d.client.ListTables(d.ctx, &dynamodb.ListTablesInput{})
@@ -112,7 +117,7 @@ func (d *DynamoDBMS) CreateTable(tableName string) error {
 	)
 
 	for {
-		describeResp, err := d.client.DescribeTable(context.TODO(), &dynamodb.DescribeTableInput{
+		describeResp, err := d.client.DescribeTable(d.ctx, &dynamodb.DescribeTableInput{
 			TableName: aws.String(tableName),
 		})
 		if err != nil {
@@ -201,7 +206,7 @@ func (d *DynamoDBMS) ExportTable(tableName string, dstData *[]map[string]interfa
 		TableName: aws.String(tableName),
 	}
 
-	scan, err := d.client.Scan(context.TODO(), param)
+	scan, err := d.client.Scan(d.ctx, param)
 	if err != nil {
 		return err
 	}
@@ -48,6 +48,11 @@ func New(client *firestore.Client, region string, opts ...FirestoreDBOption) *Fi
 	return dms
 }
 
+// SetContext sets the context used by the requests of the database.
+func (f *FirestoreDBMS) SetContext(ctx context.Context) {
+	f.ctx = ctx
+}
+
 // list table
 func (f *FirestoreDBMS) ListTables() ([]string, error) {
 	tableList := []string{}
@@ -51,6 +51,11 @@ func New(client *mongo.Client, databaseName string, opts ...NCPMongoDBOption) *N
 	return dms
 }
 
+// SetContext sets the context used by the requests of the database.
+func (n *NCPMongoDBMS) SetContext(ctx context.Context) {
+	n.ctx = ctx
+}
+
 // list table
 func (n *NCPMongoDBMS) ListTables() ([]string, error) {
 	return n.db.ListCollectionNames(n.ctx, bson.D{})
@@ -66,20 +66,30 @@ func (w *ossWriter) Close() error {
 	return <-w.ch
 }
 
+// CloseWithError aborts the upload; the object is not written.
+func (w *ossWriter) CloseWithError(err error) error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	_ = w.w.CloseWithError(err)
+	return <-w.ch
+}
+
 // CreateBucket will provision a bucket if it is not already present.
 func (f *AlibabaFS) CreateBucket() error {
 	nsId := utils.GetNsId()
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
 	headPath := "/tumblebug/ns/" + nsId + "/resources/objectStorage/" + f.bucketName
-	_, err := utils.RequestTumblebug(headPath, http.MethodHead, connName, nil)
+	_, err := utils.RequestTumblebugWithContext(f.ctx, headPath, http.MethodHead, connName, nil)
 	if err == nil {
 		return nil
 	}
 
 	createBody := []byte(fmt.Sprintf(`{"bucketName":"%s","connectionName":"%s"}`, f.bucketName, connName))
 	createPath := "/tumblebug/ns/" + nsId + "/resources/objectStorage"
-	_, err = utils.RequestTumblebug(createPath, http.MethodPut, connName, createBody)
+	_, err = utils.RequestTumblebugWithContext(f.ctx, createPath, http.MethodPut, connName, createBody)
 	if err != nil {
 		fmt.Println("create error: ", err.Error())
 		return err
@@ -126,7 +136,7 @@ func (f *AlibabaFS) DeleteBucket() error {
 	method := http.MethodDelete
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	_, err = utils.RequestTumblebug(path, method, connName, nil)
+	_, err = utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return err
 	}
@@ -134,6 +144,19 @@ func (f *AlibabaFS) DeleteBucket() error {
 	return nil
 }
 
+// DeleteObjects deletes objects of the bucket in batches
+func (f *AlibabaFS) DeleteObjects(keys []string) error {
+	const batchSize = 1000
+	for len(keys) > 0 {
+		n := min(batchSize, len(keys))
+		if err := f.deleteObjectBatch(keys[:n]); err != nil {
+			return err
+		}
+		keys = keys[n:]
+	}
+	return nil
+}
+
 // deleteObjectBatch deletes objects in manageable chunks.
 func (f *AlibabaFS) deleteObjectBatch(keys []string) error {
 	nsId := utils.GetNsId()
@@ -154,9 +177,9 @@ func (f *AlibabaFS) deleteObjectBatch(keys []string) error {
 	}
 
 	// XML 헤더 추가
-	_, rerr := utils.RequestTumblebug(path, method, connName, []byte(xml.Header+string(output)))
+	_, rerr := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, []byte(xml.Header+string(output)))
 	if rerr != nil {
-		return err
+		return rerr
 	}
 
 	return nil
@@ -183,7 +206,7 @@ func (f *AlibabaFS) ObjectListWithFilter(flt *filtering.ObjectFilter) ([]*models
 	method := http.MethodGet
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	result, err := utils.RequestTumblebug(path, method, connName, nil)
+	result, err := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +256,7 @@ func (f *AlibabaFS) BucketList(filterKey, filterVal string) ([]models.ObjectStor
 	method := http.MethodGet
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	body, err := utils.RequestTumblebug(path, method, connName, nil)
+	body, err := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return []models.ObjectStorage{}, fmt.Errorf("failed to get buckets: %w", err)
 	}
@@ -266,6 +289,11 @@ func (f *AlibabaFS) Open(name string) (io.ReadCloser, error) {
 
 // Create opens a writer that uploads an object to the configured bucket.
 func (f *AlibabaFS) Create(name string) (io.WriteCloser, error) {
+	return f.CreateWithMetadata(name, nil)
+}
+
+// CreateWithMetadata opens a writer that uploads an object with user metadata.
+func (f *AlibabaFS) CreateWithMetadata(name string, meta map[string]string) (io.WriteCloser, error) {
 	ctx := f.ctx
 	if ctx == nil {
 		ctx = context.Background()
@@ -276,9 +304,10 @@ func (f *AlibabaFS) Create(name string) (io.WriteCloser, error) {
 
 	go func() {
 		_, err := f.client.PutObject(ctx, &oss.PutObjectRequest{
-			Bucket: oss.Ptr(f.bucketName),
-			Key:    oss.Ptr(name),
-			Body:   pr,
+			Bucket:   oss.Ptr(f.bucketName),
+			Key:      oss.Ptr(name),
+			Body:     pr,
+			Metadata: meta,
 		})
 		if cerr := pr.Close(); cerr != nil && err == nil {
 			err = cerr
@@ -289,6 +318,28 @@ func (f *AlibabaFS) Create(name string) (io.WriteCloser, error) {
 	return &ossWriter{w: pw, ch: ch}, nil
 }
 
+// ObjectMetadata returns the user metadata of an object.
+func (f *AlibabaFS) ObjectMetadata(name string) (map[string]string, error) {
+	ctx := f.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	result, err := f.client.HeadObject(ctx, &oss.HeadObjectRequest{
+		Bucket: oss.Ptr(f.bucketName),
+		Key:    oss.Ptr(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Metadata, nil
+}
+
+// SetContext sets the context used by the requests of the file system.
+func (f *AlibabaFS) SetContext(ctx context.Context) {
+	f.ctx = ctx
+}
+
 // New builds a controller-compatible filesystem instance for Alibaba Cloud.
 func New(provider models.Provider, client *oss.Client, endpoint, bucketName, region string) *AlibabaFS {
 	alibabafs := &AlibabaFS{
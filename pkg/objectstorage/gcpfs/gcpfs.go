@@ -48,14 +48,14 @@ func (f *GCPfs) CreateBucket() error {
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
 	headPath := "/tumblebug/ns/" + nsId + "/resources/objectStorage/" + f.bucketName
-	_, err := utils.RequestTumblebug(headPath, http.MethodHead, connName, nil)
+	_, err := utils.RequestTumblebugWithContext(f.ctx, headPath, http.MethodHead, connName, nil)
 	if err == nil {
 		return nil
 	}
 
 	createBody := []byte(fmt.Sprintf(`{"bucketName":"%s","connectionName":"%s"}`, f.bucketName, connName))
 	createPath := "/tumblebug/ns/" + nsId + "/resources/objectStorage"
-	_, err = utils.RequestTumblebug(createPath, http.MethodPut, connName, createBody)
+	_, err = utils.RequestTumblebugWithContext(f.ctx, createPath, http.MethodPut, connName, createBody)
 	if err != nil {
 		fmt.Println("create error: ", err.Error())
 		return err
@@ -104,7 +104,7 @@ func (f *GCPfs) DeleteBucket() error {
 	method := http.MethodDelete
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	_, err = utils.RequestTumblebug(path, method, connName, nil)
+	_, err = utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return err
 	}
@@ -112,6 +112,19 @@ func (f *GCPfs) DeleteBucket() error {
 	return nil
 }
 
+// DeleteObjects deletes objects of the bucket in batches
+func (f *GCPfs) DeleteObjects(keys []string) error {
+	const batchSize = 1000
+	for len(keys) > 0 {
+		n := min(batchSize, len(keys))
+		if err := f.deleteObjectBatch(keys[:n]); err != nil {
+			return err
+		}
+		keys = keys[n:]
+	}
+	return nil
+}
+
 // deleteObjectBatch deletes a batch of objects
 func (f *GCPfs) deleteObjectBatch(keys []string) error {
 	nsId := utils.GetNsId()
@@ -132,9 +145,9 @@ func (f *GCPfs) deleteObjectBatch(keys []string) error {
 	}
 
 	// XML 헤더 추가
-	_, rerr := utils.RequestTumblebug(path, method, connName, []byte(xml.Header+string(output)))
+	_, rerr := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, []byte(xml.Header+string(output)))
 	if rerr != nil {
-		return err
+		return rerr
 	}
 
 	return nil
@@ -154,6 +167,22 @@ func (f *GCPfs) Create(name string) (io.WriteCloser, error) {
 	return f.bktclient.Object(name).NewWriter(f.ctx), nil
 }
 
+// CreateWithMetadata function
+func (f *GCPfs) CreateWithMetadata(name string, meta map[string]string) (io.WriteCloser, error) {
+	w := f.bktclient.Object(name).NewWriter(f.ctx)
+	w.Metadata = meta
+	return w, nil
+}
+
+// ObjectMetadata function
+func (f *GCPfs) ObjectMetadata(name string) (map[string]string, error) {
+	attrs, err := f.bktclient.Object(name).Attrs(f.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return attrs.Metadata, nil
+}
+
 // Look up the list of objects in your bucket
 func (f *GCPfs) ObjectList() ([]*models.Object, error) {
 	return f.ObjectListWithFilter(nil)
@@ -174,7 +203,7 @@ func (f *GCPfs) ObjectListWithFilter(flt *filtering.ObjectFilter) ([]*models.Obj
 	method := http.MethodGet
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	result, err := utils.RequestTumblebug(path, method, connName, nil)
+	result, err := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -214,6 +243,11 @@ func (f *GCPfs) ObjectListWithFilter(flt *filtering.ObjectFilter) ([]*models.Obj
 	return objList, nil
 }
 
+// SetContext sets the context used by the requests of the file system.
+func (f *GCPfs) SetContext(ctx context.Context) {
+	f.ctx = ctx
+}
+
 func New(client *storage.Client, projectID, bucketName string, region string) *GCPfs {
 	gfs := &GCPfs{
 		ctx:        context.TODO(),
@@ -237,7 +271,7 @@ func (f *GCPfs) BucketList(filterKey, filterVal string) ([]models.ObjectStorage,
 	method := http.MethodGet
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	body, err := utils.RequestTumblebug(path, method, connName, nil)
+	body, err := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return []models.ObjectStorage{}, fmt.Errorf("failed to get buckets: %w", err)
 	}
@@ -249,3 +283,56 @@ func (f *GCPfs) BucketList(filterKey, filterVal string) ([]models.ObjectStorage,
 
 	return res.ObjectStorage, nil
 }
+
+// CheckObjectLock reports whether the objects of the bucket can be locked.
+// A retention needs a bucket created with object retention enabled; holds work on every bucket.
+func (f *GCPfs) CheckObjectLock(lock models.ObjectLock) error {
+	if lock.RetainUntil == nil {
+		return nil
+	}
+	attrs, err := f.bktclient.Attrs(f.ctx)
+	if err != nil {
+		return fmt.Errorf("bucket %s: %w", f.bucketName, err)
+	}
+	if attrs.ObjectRetentionMode != "Enabled" {
+		return fmt.Errorf("object retention is not enabled on bucket %s", f.bucketName)
+	}
+	return nil
+}
+
+// LockObject applies the retention of lock to name and, for a legal hold, a temporary hold.
+// Governance maps to an Unlocked retention and compliance to a Locked one.
+// A retention that already ends later is kept, as a Locked retention cannot be shortened.
+func (f *GCPfs) LockObject(name string, lock models.ObjectLock) error {
+	obj := f.bktclient.Object(name)
+	attrs, err := obj.Attrs(f.ctx)
+	if err != nil {
+		return fmt.Errorf("lock %s: %w", name, err)
+	}
+
+	update := storage.ObjectAttrsToUpdate{}
+	changed := false
+	if lock.RetainUntil != nil && (attrs.Retention == nil || attrs.Retention.RetainUntil.Before(*lock.RetainUntil)) {
+		mode := "Unlocked"
+		if lock.Mode == models.ObjectLockCompliance || (attrs.Retention != nil && attrs.Retention.Mode == "Locked") {
+			mode = "Locked"
+		}
+		if attrs.Retention != nil && attrs.Retention.Mode == "Unlocked" && mode == "Locked" {
+			obj = obj.OverrideUnlockedRetention(true)
+		}
+		update.Retention = &storage.ObjectRetention{Mode: mode, RetainUntil: *lock.RetainUntil}
+		changed = true
+	}
+	if lock.LegalHold && !attrs.TemporaryHold {
+		update.TemporaryHold = true
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if _, err := obj.Update(f.ctx, update); err != nil {
+		return fmt.Errorf("lock %s: %w", name, err)
+	}
+	return nil
+}
@@ -58,6 +58,16 @@ func (p *writer) Close() error {
 	return nil
 }
 
+// CloseWithError aborts the upload; the object is not written.
+func (p *writer) CloseWithError(err error) error {
+	if !p.chkClose {
+		p.chkClose = true
+		_ = p.w.CloseWithError(err)
+		return <-p.ch
+	}
+	return nil
+}
+
 type fakeWriteAt struct {
 	W io.Writer
 }
@@ -76,6 +86,11 @@ type IBMFS struct {
 	downloader manager.Downloader
 }
 
+// SetContext sets the context used by the requests of the file system.
+func (f *IBMFS) SetContext(ctx context.Context) {
+	f.ctx = ctx
+}
+
 func New(provider models.Provider, bucketName, region string) *IBMFS {
 	return &IBMFS{
 		provider:   provider,
@@ -93,14 +108,14 @@ func (f *IBMFS) CreateBucket() error {
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
 	headPath := "/tumblebug/ns/" + nsId + "/resources/objectStorage/" + f.bucketName
-	_, err := utils.RequestTumblebug(headPath, http.MethodHead, connName, nil)
+	_, err := utils.RequestTumblebugWithContext(f.ctx, headPath, http.MethodHead, connName, nil)
 	if err == nil {
 		return nil
 	}
 
 	createBody := []byte(fmt.Sprintf(`{"bucketName":"%s","connectionName":"%s"}`, f.bucketName, connName))
 	createPath := "/tumblebug/ns/" + nsId + "/resources/objectStorage"
-	_, err = utils.RequestTumblebug(createPath, http.MethodPut, connName, createBody)
+	_, err = utils.RequestTumblebugWithContext(f.ctx, createPath, http.MethodPut, connName, createBody)
 	if err != nil {
 		fmt.Println("create error: ", err.Error())
 		return err
@@ -148,7 +163,7 @@ func (f *IBMFS) DeleteBucket() error {
 	method := http.MethodDelete
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	_, err = utils.RequestTumblebug(path, method, connName, nil)
+	_, err = utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return err
 	}
@@ -156,6 +171,19 @@ func (f *IBMFS) DeleteBucket() error {
 	return nil
 }
 
+// DeleteObjects deletes objects of the bucket in batches
+func (f *IBMFS) DeleteObjects(keys []string) error {
+	const batchSize = 1000
+	for len(keys) > 0 {
+		n := min(batchSize, len(keys))
+		if err := f.deleteObjectBatch(keys[:n]); err != nil {
+			return err
+		}
+		keys = keys[n:]
+	}
+	return nil
+}
+
 // deleteObjectBatch deletes a batch of objects
 func (f *IBMFS) deleteObjectBatch(keys []string) error {
 	nsId := utils.GetNsId()
@@ -176,9 +204,9 @@ func (f *IBMFS) deleteObjectBatch(keys []string) error {
 	}
 
 	// XML 헤더 추가
-	_, rerr := utils.RequestTumblebug(path, method, connName, []byte(xml.Header+string(output)))
+	_, rerr := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, []byte(xml.Header+string(output)))
 	if rerr != nil {
-		return err
+		return rerr
 	}
 
 	return nil
@@ -210,7 +238,7 @@ func (f *IBMFS) Open(name string) (io.ReadCloser, error) {
 	path := fmt.Sprintf("/tumblebug/ns/%s/resources/objectStorage/%s/object/%s/presignedUrl?operation=download&expires=3600",
 		nsId, f.bucketName, encodedKey)
 
-	body, err := utils.RequestTumblebug(path, http.MethodPost, connName, nil)
+	body, err := utils.RequestTumblebugWithContext(f.ctx, path, http.MethodPost, connName, nil)
 	if err != nil {
 		return nil, fmt.Errorf("openWithTumblebug: failed to generate presigned URL for %q: %w", name, err)
 	}
@@ -226,7 +254,11 @@ func (f *IBMFS) Open(name string) (io.ReadCloser, error) {
 	log.Debug().Str("key", name).Str("presignedURL", resp.PresignedURL).
 		Msg("[IBMFS] openWithTumblebug: downloading via presigned URL")
 
-	httpResp, err := http.Get(resp.PresignedURL) //nolint:noctx
+	req, err := http.NewRequestWithContext(f.ctx, http.MethodGet, resp.PresignedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("openWithTumblebug: failed to create GET request: %w", err)
+	}
+	httpResp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("openWithTumblebug: HTTP GET failed: %w", err)
 	}
@@ -255,6 +287,13 @@ func (w *tumblebugWriter) Write(b []byte) (int, error) {
 	return w.buf.Write(b)
 }
 
+// CloseWithError drops the buffered data without uploading it.
+func (w *tumblebugWriter) CloseWithError(err error) error {
+	w.chkClose = true
+	w.buf.Reset()
+	return nil
+}
+
 func (w *tumblebugWriter) Close() error {
 	if w.chkClose {
 		return nil
@@ -315,7 +354,7 @@ func (f *IBMFS) Create(name string) (io.WriteCloser, error) {
 	path := fmt.Sprintf("/tumblebug/ns/%s/resources/objectStorage/%s/object/%s/presignedUrl?operation=upload&expires=3600",
 		nsId, f.bucketName, encodedKey)
 
-	body, err := utils.RequestTumblebug(path, http.MethodPost, connName, nil)
+	body, err := utils.RequestTumblebugWithContext(f.ctx, path, http.MethodPost, connName, nil)
 	if err != nil {
 		return nil, fmt.Errorf("createWithTumblebug: failed to generate presigned URL for %q: %w", name, err)
 	}
@@ -355,7 +394,7 @@ func (f *IBMFS) ObjectListWithFilter(flt *filtering.ObjectFilter) ([]*models.Obj
 		method := http.MethodGet
 		connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-		result, err := utils.RequestTumblebug(path, method, connName, nil)
+		result, err := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -418,7 +457,7 @@ func (f *IBMFS) BucketList(filterKey, filterVal string) ([]models.ObjectStorage,
 	method := http.MethodGet
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	body, err := utils.RequestTumblebug(path, method, connName, nil)
+	body, err := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return []models.ObjectStorage{}, fmt.Errorf("failed to get buckets: %w", err)
 	}
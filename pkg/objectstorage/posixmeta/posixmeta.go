@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package posixmeta stores POSIX file attributes as object metadata
+// so that local files survive a round trip through object storage.
+package posixmeta
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Object metadata keys
+const (
+	KeyMode    = "mcdm-mode"
+	KeyUID     = "mcdm-uid"
+	KeyGID     = "mcdm-gid"
+	KeyMtime   = "mcdm-mtime"
+	KeySymlink = "mcdm-symlink"
+)
+
+// Capture returns the POSIX attributes of a local file as object metadata.
+// Symbolic links are not followed; their target is stored instead.
+func Capture(fileName string) (map[string]string, error) {
+	info, err := os.Lstat(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := map[string]string{
+		KeyMode:  strconv.FormatUint(uint64(info.Mode().Perm()), 8),
+		KeyMtime: info.ModTime().UTC().Format(time.RFC3339Nano),
+	}
+	if uid, gid, ok := owner(info); ok {
+		meta[KeyUID] = strconv.Itoa(uid)
+		meta[KeyGID] = strconv.Itoa(gid)
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(fileName)
+		if err != nil {
+			return nil, err
+		}
+		meta[KeySymlink] = target
+	}
+	return meta, nil
+}
+
+// IsSymlink reports whether fileName is a symbolic link.
+func IsSymlink(fileName string) bool {
+	info, err := os.Lstat(fileName)
+	return err == nil && info.Mode()&fs.ModeSymlink != 0
+}
+
+// Symlink returns the link target stored in meta.
+func Symlink(meta map[string]string) (string, bool) {
+	target, ok := meta[KeySymlink]
+	return target, ok && target != ""
+}
+
+// Apply re-applies metadata captured by Capture to fileName.
+//
+// The mtime falls back to lastModified when meta has none, so objects that were
+// not uploaded from a local disk get their LastModified as mtime. Ownership is
+// only changed when the process is allowed to; a permission error is ignored.
+// For a symbolic link only the owner is applied; the link itself is created by CreateSymlink.
+func Apply(fileName string, meta map[string]string, lastModified time.Time) error {
+	if _, ok := Symlink(meta); ok {
+		return applyOwner(fileName, meta)
+	}
+
+	if v, ok := meta[KeyMode]; ok {
+		mode, err := strconv.ParseUint(v, 8, 32)
+		if err != nil {
+			return err
+		}
+		if err := os.Chmod(fileName, fs.FileMode(mode).Perm()); err != nil {
+			return err
+		}
+	}
+
+	if err := applyOwner(fileName, meta); err != nil {
+		return err
+	}
+
+	mtime := lastModified
+	if v, ok := meta[KeyMtime]; ok {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return err
+		}
+		mtime = t
+	}
+	if mtime.IsZero() {
+		return nil
+	}
+	return os.Chtimes(fileName, mtime, mtime)
+}
+
+func applyOwner(fileName string, meta map[string]string) error {
+	uidStr, uidOk := meta[KeyUID]
+	gidStr, gidOk := meta[KeyGID]
+	if !uidOk || !gidOk {
+		return nil
+	}
+	uid, err := strconv.Atoi(uidStr)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.Atoi(gidStr)
+	if err != nil {
+		return err
+	}
+	if err := os.Lchown(fileName, uid, gid); err != nil && !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	return nil
+}
+
+// Within reports an error when name is not inside root once the symbolic links
+// of its existing part are resolved.
+func Within(root, name string) error {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+	resolved, err := resolve(name)
+	if err != nil {
+		return err
+	}
+	if !inside(realRoot, resolved) {
+		return fmt.Errorf("%s is outside %s", name, root)
+	}
+	return nil
+}
+
+// MkdirAll creates the directory dirName and its parents inside root.
+func MkdirAll(root, dirName string) error {
+	if err := Within(root, dirName); err != nil {
+		return err
+	}
+	return os.MkdirAll(dirName, 0o755)
+}
+
+// CreateFile creates or truncates fileName inside root, creating its parent
+// directories. It fails when a parent resolves outside root or when fileName
+// is itself a symbolic link, so a downloaded link cannot redirect the write.
+func CreateFile(root, fileName string) (*os.File, error) {
+	if err := MkdirAll(root, filepath.Dir(fileName)); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC|noFollow, 0o666)
+}
+
+// CreateSymlink creates fileName as a symbolic link to target inside root.
+//
+// The target must be relative and may only climb with leading ".." elements,
+// and not above root, so that the link cannot point outside root even when
+// the directories it passes through are links themselves.
+func CreateSymlink(root, fileName, target string) error {
+	if filepath.IsAbs(target) || strings.HasPrefix(target, "/") {
+		return fmt.Errorf("symlink %s: absolute target %s", fileName, target)
+	}
+	dir := filepath.Dir(fileName)
+	if err := MkdirAll(root, dir); err != nil {
+		return err
+	}
+
+	parts := strings.Split(filepath.ToSlash(target), "/")
+	up := 0
+	for up < len(parts) && parts[up] == ".." {
+		up++
+	}
+	for _, part := range parts[up:] {
+		if part == ".." {
+			return fmt.Errorf("symlink %s: target %s climbs after descending", fileName, target)
+		}
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if !inside(realRoot, filepath.Join(realDir, filepath.FromSlash(target))) {
+		return fmt.Errorf("symlink %s: target %s is outside %s", fileName, target, root)
+	}
+
+	if info, err := os.Lstat(fileName); err == nil && !info.IsDir() {
+		if err := os.Remove(fileName); err != nil {
+			return err
+		}
+	}
+	return os.Symlink(target, fileName)
+}
+
+// resolve evaluates the symbolic links of the longest existing prefix of name.
+func resolve(name string) (string, error) {
+	name = filepath.Clean(name)
+	rest := ""
+	for {
+		real, err := filepath.EvalSymlinks(name)
+		if err == nil {
+			return filepath.Join(real, rest), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(name)
+		if parent == name {
+			return "", err
+		}
+		rest = filepath.Join(filepath.Base(name), rest)
+		name = parent
+	}
+}
+
+// inside reports whether name is root or below it; both must be clean.
+func inside(root, name string) bool {
+	rel, err := filepath.Rel(root, name)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package posixmeta_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/posixmeta"
+)
+
+func TestCaptureApply(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "file")
+	if err := os.WriteFile(fileName, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chmod(fileName, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(fileName, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := posixmeta.Capture(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta[posixmeta.KeyMode] != "600" {
+		t.Errorf("mode = %q", meta[posixmeta.KeyMode])
+	}
+
+	restored := filepath.Join(dir, "restored")
+	if err := os.WriteFile(restored, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := posixmeta.Apply(restored, meta, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 || !info.ModTime().Equal(mtime) {
+		t.Errorf("restored mode %v mtime %v", info.Mode().Perm(), info.ModTime())
+	}
+
+	lastModified := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := posixmeta.Apply(restored, nil, lastModified); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(restored); !info.ModTime().Equal(lastModified) {
+		t.Errorf("mtime without metadata = %v, want %v", info.ModTime(), lastModified)
+	}
+}
+
+func TestCreateSymlink(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		link   string
+		target string
+		ok     bool
+	}{
+		{"sibling", "link", "file", true},
+		{"parent", "sub/link", "../file", true},
+		{"descend", "link2", "sub/file", true},
+		{"absolute", "abs", "/etc", false},
+		{"above root", "up", "../outside", false},
+		{"above root from sub", "sub/up", "../../outside", false},
+		{"climb after descend", "mixed", "sub/../../outside", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := posixmeta.CreateSymlink(root, filepath.Join(root, tt.link), tt.target)
+			if (err == nil) != tt.ok {
+				t.Fatalf("CreateSymlink(%s -> %s) err = %v", tt.link, tt.target, err)
+			}
+			if !tt.ok && posixmeta.IsSymlink(filepath.Join(root, tt.link)) {
+				t.Error("a rejected link must not be created")
+			}
+		})
+	}
+}
+
+func TestCreateFileThroughLink(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := posixmeta.CreateFile(root, filepath.Join(root, "a", "passwd")); err == nil {
+		t.Fatal("a file behind a link to outside root must be refused")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "passwd")); !os.IsNotExist(err) {
+		t.Error("nothing may be written outside root")
+	}
+
+	if err := os.Symlink(filepath.Join(outside, "victim"), filepath.Join(root, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := posixmeta.CreateFile(root, filepath.Join(root, "dangling")); err == nil {
+		t.Fatal("a link must not be opened for writing")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "victim")); !os.IsNotExist(err) {
+		t.Error("the link target must not be created")
+	}
+
+	f, err := posixmeta.CreateFile(root, filepath.Join(root, "dir", "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/cloud-barista/mc-data-manager/models"
 	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
 	"github.com/cloud-barista/mc-data-manager/pkg/utils"
@@ -74,6 +75,16 @@ func (p *writer) Close() error {
 	return nil
 }
 
+// CloseWithError aborts the upload; the object is not written.
+func (p *writer) CloseWithError(err error) error {
+	if !p.chkClose {
+		p.chkClose = true
+		_ = p.w.CloseWithError(err)
+		return <-p.ch
+	}
+	return nil
+}
+
 type fakeWriteAt struct {
 	W io.Writer
 }
@@ -101,14 +112,14 @@ func (f *S3FS) CreateBucket() error {
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
 	headPath := "/tumblebug/ns/" + nsId + "/resources/objectStorage/" + f.bucketName
-	_, err := utils.RequestTumblebug(headPath, http.MethodHead, connName, nil)
+	_, err := utils.RequestTumblebugWithContext(f.ctx, headPath, http.MethodHead, connName, nil)
 	if err == nil {
 		return nil
 	}
 
 	createBody := []byte(fmt.Sprintf(`{"bucketName":"%s","connectionName":"%s"}`, f.bucketName, connName))
 	createPath := "/tumblebug/ns/" + nsId + "/resources/objectStorage"
-	_, err = utils.RequestTumblebug(createPath, http.MethodPut, connName, createBody)
+	_, err = utils.RequestTumblebugWithContext(f.ctx, createPath, http.MethodPut, connName, createBody)
 	if err != nil {
 		fmt.Println("create error: ", err.Error())
 		return err
@@ -156,7 +167,7 @@ func (f *S3FS) DeleteBucket() error {
 	method := http.MethodDelete
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	_, err = utils.RequestTumblebug(path, method, connName, nil)
+	_, err = utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return err
 	}
@@ -164,6 +175,19 @@ func (f *S3FS) DeleteBucket() error {
 	return nil
 }
 
+// DeleteObjects deletes objects of the bucket in batches
+func (f *S3FS) DeleteObjects(keys []string) error {
+	const batchSize = 1000
+	for len(keys) > 0 {
+		n := min(batchSize, len(keys))
+		if err := f.deleteObjectBatch(keys[:n]); err != nil {
+			return err
+		}
+		keys = keys[n:]
+	}
+	return nil
+}
+
 // deleteObjectBatch deletes a batch of objects
 func (f *S3FS) deleteObjectBatch(keys []string) error {
 	nsId := utils.GetNsId()
@@ -184,9 +208,9 @@ func (f *S3FS) deleteObjectBatch(keys []string) error {
 	}
 
 	// XML 헤더 추가
-	_, rerr := utils.RequestTumblebug(path, method, connName, []byte(xml.Header+string(output)))
+	_, rerr := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, []byte(xml.Header+string(output)))
 	if rerr != nil {
-		return err
+		return rerr
 	}
 
 	return nil
@@ -218,7 +242,7 @@ func (f *S3FS) Open(name string) (io.ReadCloser, error) {
 	path := fmt.Sprintf("/tumblebug/ns/%s/resources/objectStorage/%s/object/%s/presignedUrl?operation=download&expires=3600",
 		nsId, f.bucketName, encodedKey)
 
-	body, err := utils.RequestTumblebug(path, http.MethodPost, connName, nil)
+	body, err := utils.RequestTumblebugWithContext(f.ctx, path, http.MethodPost, connName, nil)
 	if err != nil {
 		return nil, fmt.Errorf("openWithTumblebug: failed to generate presigned URL for %q: %w", name, err)
 	}
@@ -234,7 +258,11 @@ func (f *S3FS) Open(name string) (io.ReadCloser, error) {
 	log.Debug().Str("key", name).Str("presignedURL", resp.PresignedURL).
 		Msg("[S3FS] openWithTumblebug: downloading via presigned URL")
 
-	httpResp, err := http.Get(resp.PresignedURL) //nolint:noctx
+	req, err := http.NewRequestWithContext(f.ctx, http.MethodGet, resp.PresignedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("openWithTumblebug: failed to create GET request: %w", err)
+	}
+	httpResp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("openWithTumblebug: HTTP GET failed: %w", err)
 	}
@@ -263,6 +291,13 @@ func (w *tumblebugWriter) Write(b []byte) (int, error) {
 	return w.buf.Write(b)
 }
 
+// CloseWithError drops the buffered data without uploading it.
+func (w *tumblebugWriter) CloseWithError(err error) error {
+	w.chkClose = true
+	w.buf.Reset()
+	return nil
+}
+
 func (w *tumblebugWriter) Close() error {
 	if w.chkClose {
 		return nil
@@ -323,7 +358,7 @@ func (f *S3FS) Create(name string) (io.WriteCloser, error) {
 	path := fmt.Sprintf("/tumblebug/ns/%s/resources/objectStorage/%s/object/%s/presignedUrl?operation=upload&expires=3600",
 		nsId, f.bucketName, encodedKey)
 
-	body, err := utils.RequestTumblebug(path, http.MethodPost, connName, nil)
+	body, err := utils.RequestTumblebugWithContext(f.ctx, path, http.MethodPost, connName, nil)
 	if err != nil {
 		return nil, fmt.Errorf("createWithTumblebug: failed to generate presigned URL for %q: %w", name, err)
 	}
@@ -346,6 +381,43 @@ func (f *S3FS) Create(name string) (io.WriteCloser, error) {
 	}, nil
 }
 
+// CreateWithMetadata opens a writer that uploads an object with user metadata.
+//
+// Presigned URLs issued by Tumblebug do not sign metadata headers, so the
+// object is uploaded with the S3 client instead. It is only used when a task
+// asks for its metadata to be kept; Create stays the default upload.
+func (f *S3FS) CreateWithMetadata(name string, meta map[string]string) (io.WriteCloser, error) {
+	pr, pw := io.Pipe()
+	ch := make(chan error)
+	ctx, cancel := context.WithCancel(f.ctx)
+	go func() {
+		defer cancel()
+		_, err := f.uploader.Upload(ctx, &s3.PutObjectInput{
+			Bucket:   aws.String(f.bucketName),
+			Key:      aws.String(name),
+			Body:     pr,
+			Metadata: meta,
+		})
+		// unblock the writer when the upload stops early
+		_ = pr.CloseWithError(err)
+		ch <- err
+	}()
+
+	return &writer{w: pw, ch: ch, cancel: cancel, chkClose: false}, nil
+}
+
+// ObjectMetadata returns the user metadata of an object.
+func (f *S3FS) ObjectMetadata(name string) (map[string]string, error) {
+	out, err := f.client.HeadObject(f.ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(f.bucketName),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Metadata, nil
+}
+
 // Open function using pipeline
 func (f *S3FS) OpenDeprecated(name string) (io.ReadCloser, error) {
 	pr, pw := io.Pipe()
@@ -425,6 +497,11 @@ func (f *S3FS) CreateDeprecated(name string) (io.WriteCloser, error) {
 // 	return objlist, nil
 // }
 
+// SetContext sets the context used by the requests of the file system.
+func (f *S3FS) SetContext(ctx context.Context) {
+	f.ctx = ctx
+}
+
 func New(provider models.Provider, client *s3.Client, bucketName, region string) *S3FS {
 	sfs := &S3FS{
 		ctx:        context.TODO(),
@@ -457,7 +534,7 @@ func (f *S3FS) ObjectListWithFilter(flt *filtering.ObjectFilter) ([]*models.Obje
 		method := http.MethodGet
 		connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-		result, err := utils.RequestTumblebug(path, method, connName, nil)
+		result, err := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -520,7 +597,7 @@ func (f *S3FS) BucketList(filterKey, filterVal string) ([]models.ObjectStorage,
 	method := http.MethodGet
 	connName := fmt.Sprintf("%s-%s", f.provider, f.region)
 
-	body, err := utils.RequestTumblebug(path, method, connName, nil)
+	body, err := utils.RequestTumblebugWithContext(f.ctx, path, method, connName, nil)
 	if err != nil {
 		return []models.ObjectStorage{}, fmt.Errorf("failed to get buckets: %w", err)
 	}
@@ -532,3 +609,65 @@ func (f *S3FS) BucketList(filterKey, filterVal string) ([]models.ObjectStorage,
 
 	return res.ObjectStorage, nil
 }
+
+// CheckObjectLock reports whether the objects of the bucket can be locked.
+// Only AWS buckets created with object lock enabled support it.
+func (f *S3FS) CheckObjectLock(lock models.ObjectLock) error {
+	if f.provider != models.AWS {
+		return fmt.Errorf("%s object storage does not support object lock", f.provider)
+	}
+	out, err := f.client.GetObjectLockConfiguration(f.ctx, &s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(f.bucketName),
+	})
+	if err != nil {
+		return fmt.Errorf("bucket %s has no object lock configuration: %w", f.bucketName, err)
+	}
+	if out.ObjectLockConfiguration == nil || out.ObjectLockConfiguration.ObjectLockEnabled != types.ObjectLockEnabledEnabled {
+		return fmt.Errorf("object lock is not enabled on bucket %s", f.bucketName)
+	}
+	return nil
+}
+
+// LockObject applies the retention and legal hold of lock to the current version of name.
+// A retention that already ends later is kept, as it cannot be shortened.
+func (f *S3FS) LockObject(name string, lock models.ObjectLock) error {
+	if lock.RetainUntil != nil {
+		cur, err := f.client.GetObjectRetention(f.ctx, &s3.GetObjectRetentionInput{
+			Bucket: aws.String(f.bucketName),
+			Key:    aws.String(name),
+		})
+		// an object without retention returns an error
+		if err != nil || cur.Retention == nil || cur.Retention.RetainUntilDate == nil || cur.Retention.RetainUntilDate.Before(*lock.RetainUntil) {
+			mode := types.ObjectLockRetentionModeGovernance
+			if lock.Mode == models.ObjectLockCompliance {
+				mode = types.ObjectLockRetentionModeCompliance
+			}
+			if err == nil && cur.Retention != nil && cur.Retention.Mode == types.ObjectLockRetentionModeCompliance {
+				mode = types.ObjectLockRetentionModeCompliance
+			}
+			_, err := f.client.PutObjectRetention(f.ctx, &s3.PutObjectRetentionInput{
+				Bucket: aws.String(f.bucketName),
+				Key:    aws.String(name),
+				Retention: &types.ObjectLockRetention{
+					Mode:            mode,
+					RetainUntilDate: lock.RetainUntil,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("retention of %s: %w", name, err)
+			}
+		}
+	}
+
+	if lock.LegalHold {
+		_, err := f.client.PutObjectLegalHold(f.ctx, &s3.PutObjectLegalHoldInput{
+			Bucket:    aws.String(f.bucketName),
+			Key:       aws.String(name),
+			LegalHold: &types.ObjectLockLegalHold{Status: types.ObjectLockLegalHoldStatusOn},
+		})
+		if err != nil {
+			return fmt.Errorf("legal hold of %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package pii finds personal data in text objects.
+package pii
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+// maxSamples is the number of masked examples kept per kind of personal data.
+const maxSamples = 3
+
+// textExts are the extensions of the objects that are scanned.
+var textExts = map[string]bool{
+	".csv": true, ".tsv": true, ".json": true, ".jsonl": true, ".ndjson": true,
+	".xml": true, ".txt": true, ".log": true,
+}
+
+// IsText reports whether the object key is a text object that can be scanned.
+func IsText(key string) bool {
+	return textExts[strings.ToLower(path.Ext(key))]
+}
+
+type detector struct {
+	typ models.PIIType
+	re  *regexp.Regexp
+	// group is the submatch holding the personal data, 0 for the whole match
+	group int
+	valid func(string) bool
+}
+
+// detectors run in this order; the text matched by one is hidden from the next ones,
+// so a card number is not counted as a phone number as well.
+var detectors = []detector{
+	{
+		// labeled values such as "ssn": "123456789" or rrn=900101-1234567
+		typ:   models.PIINationalID,
+		re:    regexp.MustCompile(`(?i)\b(?:ssn|social[_ ]?security(?:[_ ]?(?:number|no))?|rrn)\W{0,4}(\d{3}-?\d{2}-?\d{4}|\d{6}-?[1-4]\d{6})\b`),
+		group: 1,
+	},
+	{
+		// US social security numbers and Korean resident registration numbers
+		typ: models.PIINationalID,
+		re:  regexp.MustCompile(`\b(?:\d{3}-\d{2}-\d{4}|\d{6}-[1-4]\d{6})\b`),
+	},
+	{
+		typ:   models.PIICard,
+		re:    regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid: luhn,
+	},
+	{
+		// labeled unformatted numbers such as "phone": "6136459948"
+		typ:   models.PIIPhone,
+		re:    regexp.MustCompile(`(?i)\b(?:phone|tel|mobile|cell)\w*\W{0,4}(\+?\d{9,15})\b`),
+		group: 1,
+	},
+	{
+		typ: models.PIIPhone,
+		re:  regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{2,4}\)|\b\d{2,4})[ .-]?\d{3,4}[ .-]\d{4}\b`),
+	},
+	{
+		typ: models.PIIEmail,
+		re:  regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+}
+
+// Scanner finds personal data.
+type Scanner struct {
+	types     []models.PIIType
+	detectors []detector
+}
+
+// New returns a scanner for types, or for every kind of personal data when types is empty.
+func New(types []models.PIIType) (*Scanner, error) {
+	all := []models.PIIType{models.PIINationalID, models.PIICard, models.PIIPhone, models.PIIEmail}
+	if len(types) == 0 {
+		types = all
+	}
+
+	want := map[models.PIIType]bool{}
+	for _, t := range types {
+		known := false
+		for _, a := range all {
+			known = known || a == t
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown pii type %q", t)
+		}
+		want[t] = true
+	}
+
+	s := &Scanner{}
+	for _, t := range all {
+		if want[t] {
+			s.types = append(s.types, t)
+		}
+	}
+	for _, d := range detectors {
+		if want[d.typ] {
+			s.detectors = append(s.detectors, d)
+		}
+	}
+	return s, nil
+}
+
+// Result is the personal data found in one object.
+type Result struct {
+	// Scanned is the number of bytes read
+	Scanned  int64
+	Findings []models.PIIFinding
+}
+
+// Found reports whether any personal data was found.
+func (r *Result) Found() bool {
+	return len(r.Findings) > 0
+}
+
+// Scan reads r line by line, at most limit bytes when limit is positive.
+func (s *Scanner) Scan(r io.Reader, limit int64) (*Result, error) {
+	if limit > 0 {
+		r = io.LimitReader(r, limit)
+	}
+
+	counts := map[models.PIIType]int{}
+	samples := map[models.PIIType][]string{}
+	br := bufio.NewReaderSize(r, 64<<10)
+	var scanned int64
+	for {
+		// a line longer than the buffer is scanned in pieces
+		line, err := br.ReadSlice('\n')
+		scanned += int64(len(line))
+		if len(line) > 0 {
+			s.scanLine(line, counts, samples)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil && !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, err
+		}
+	}
+
+	res := &Result{Scanned: scanned}
+	for _, t := range s.types {
+		if counts[t] > 0 {
+			res.Findings = append(res.Findings, models.PIIFinding{Type: t, Count: counts[t], Samples: samples[t]})
+		}
+	}
+	return res, nil
+}
+
+func (s *Scanner) scanLine(line []byte, counts map[models.PIIType]int, samples map[models.PIIType][]string) {
+	work := append([]byte(nil), line...)
+	for _, d := range s.detectors {
+		for _, loc := range d.re.FindAllSubmatchIndex(work, -1) {
+			start, end := loc[2*d.group], loc[2*d.group+1]
+			value := string(work[start:end])
+			if d.valid != nil && !d.valid(value) {
+				continue
+			}
+
+			counts[d.typ]++
+			if len(samples[d.typ]) < maxSamples {
+				samples[d.typ] = append(samples[d.typ], mask(d.typ, value))
+			}
+			for i := start; i < end; i++ {
+				work[i] = ' '
+			}
+		}
+	}
+}
+
+// luhn reports whether the digits of s have a valid Luhn check digit.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// mask hides personal data in report samples.
+func mask(typ models.PIIType, value string) string {
+	if typ == models.PIIEmail {
+		local, domain, _ := strings.Cut(value, "@")
+		return local[:1] + "***@" + domain
+	}
+
+	// keep the last four digits
+	b := []byte(value)
+	keep := 4
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '0' || b[i] > '9' {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		b[i] = '*'
+	}
+	return string(b)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package pii_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/pii"
+)
+
+// person has the personal data fields of the pkg/dummy person generator.
+type person struct {
+	Name       string                   `json:"name" fake:"{name}"`
+	SSN        string                   `json:"ssn" fake:"{ssn}"`
+	Contact    *gofakeit.ContactInfo    `json:"contact"`
+	CreditCard *gofakeit.CreditCardInfo `json:"credit_card"`
+}
+
+func TestScanDummyData(t *testing.T) {
+	gofakeit.Seed(11)
+	people := make([]person, 5)
+	for i := range people {
+		if err := gofakeit.Struct(&people[i]); err != nil {
+			t.Fatal(err)
+		}
+		people[i].Contact = gofakeit.Contact()
+		people[i].CreditCard = gofakeit.CreditCard()
+	}
+	data, _ := json.MarshalIndent(people, "", "    ")
+
+	scanner, _ := pii.New(nil)
+	res, err := scanner.Scan(strings.NewReader(string(data)), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[models.PIIType]int{}
+	for _, f := range res.Findings {
+		counts[f.Type] = f.Count
+		for _, s := range f.Samples {
+			if strings.Contains(string(data), s) {
+				t.Errorf("sample %q is not masked", s)
+			}
+		}
+	}
+	for _, typ := range []models.PIIType{models.PIINationalID, models.PIICard, models.PIIPhone, models.PIIEmail} {
+		if counts[typ] != len(people) {
+			t.Errorf("%s found %d times, want %d", typ, counts[typ], len(people))
+		}
+	}
+}
+
+func TestScanFormatted(t *testing.T) {
+	text := "id,note\n" +
+		"1,call 010-1234-5678 or (613) 645-9948\n" +
+		"2,card 4111 1111 1111 1111 ssn 123-45-6789 rrn 900101-1234567\n" +
+		"3,order 12345 shipped 2024-01-02 10:30 total 4111111111111112\n"
+
+	scanner, _ := pii.New(nil)
+	res, err := scanner.Scan(strings.NewReader(text), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.PIIFinding{
+		{Type: models.PIINationalID, Count: 2},
+		{Type: models.PIICard, Count: 1},
+		{Type: models.PIIPhone, Count: 2},
+	}
+	if len(res.Findings) != len(want) {
+		t.Fatalf("findings = %+v", res.Findings)
+	}
+	for i, f := range res.Findings {
+		if f.Type != want[i].Type || f.Count != want[i].Count {
+			t.Errorf("finding %d = %s x%d, want %s x%d", i, f.Type, f.Count, want[i].Type, want[i].Count)
+		}
+	}
+	if got := res.Findings[1].Samples[0]; got != "**** **** **** 1111" {
+		t.Errorf("card sample = %q", got)
+	}
+
+	limited, _ := scanner.Scan(strings.NewReader(text), 8)
+	if limited.Found() || limited.Scanned != 8 {
+		t.Errorf("sampled scan = %+v", limited)
+	}
+}
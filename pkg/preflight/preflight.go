@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package preflight checks a local directory before a backup writes to it.
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+// Margin is the share added to the estimated size of a backup for files it writes besides the data.
+const Margin = 0.1
+
+const (
+	CodeNotDirectory      = "not-directory"
+	CodeNotWritable       = "not-writable"
+	CodeInsufficientSpace = "insufficient-space"
+	CodeFreeSpaceUnknown  = "free-space-unknown"
+	CodeEstimateFailed    = "estimate-failed"
+)
+
+// Error is returned when a check finds a fatal problem.
+type Error struct {
+	Report *models.PreflightReport
+}
+
+func (e *Error) Error() string {
+	msgs := []string{}
+	for _, p := range e.Report.Problems {
+		if p.Fatal {
+			msgs = append(msgs, p.Message)
+		}
+	}
+	return fmt.Sprintf("preflight check of %s failed: %s", e.Report.Path, strings.Join(msgs, "; "))
+}
+
+// Check checks that dir, or its nearest existing parent when dir does not exist yet,
+// is a writable directory with room for estimated bytes plus Margin.
+// estimateErr is the error of the size estimate; it is recorded as a warning.
+func Check(dir string, estimated int64, estimateErr error) (*models.PreflightReport, error) {
+	report := &models.PreflightReport{
+		Path:           dir,
+		EstimatedBytes: estimated,
+		RequiredBytes:  estimated + int64(float64(estimated)*Margin),
+		FreeBytes:      -1,
+		Problems:       []models.PreflightProblem{},
+		CheckedAt:      time.Now(),
+	}
+	if estimateErr != nil {
+		addProblem(report, CodeEstimateFailed, fmt.Sprintf("backup size could not be estimated: %v", estimateErr), false)
+	}
+
+	base, err := existingParent(dir)
+	if err != nil {
+		addProblem(report, CodeNotDirectory, err.Error(), true)
+		return finish(report)
+	}
+
+	report.Writable = writable(base)
+	if !report.Writable {
+		addProblem(report, CodeNotWritable, fmt.Sprintf("%s is not writable", base), true)
+	}
+
+	if free, ok := freeSpace(base); ok {
+		report.FreeBytes = free
+		if free < report.RequiredBytes {
+			addProblem(report, CodeInsufficientSpace, fmt.Sprintf("%s has %d bytes free, the backup needs about %d", base, free, report.RequiredBytes), true)
+		}
+	} else {
+		addProblem(report, CodeFreeSpaceUnknown, fmt.Sprintf("free space of %s is unknown on this platform", base), false)
+	}
+	return finish(report)
+}
+
+// existingParent returns dir or its nearest existing parent, which must be a directory.
+func existingParent(dir string) (string, error) {
+	path, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		info, err := os.Stat(path)
+		if err == nil {
+			if !info.IsDir() {
+				return "", fmt.Errorf("%s is not a directory", path)
+			}
+			return path, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", fmt.Errorf("no existing parent of %s", dir)
+		}
+		path = parent
+	}
+}
+
+// writable creates and removes a file in dir.
+func writable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".mcdm-preflight-*")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
+func addProblem(report *models.PreflightReport, code, msg string, fatal bool) {
+	report.Problems = append(report.Problems, models.PreflightProblem{Code: code, Message: msg, Fatal: fatal})
+}
+
+// finish sets Passed and returns an *Error when a problem is fatal.
+func finish(report *models.PreflightReport) (*models.PreflightReport, error) {
+	report.Passed = true
+	for _, p := range report.Problems {
+		report.Passed = report.Passed && !p.Fatal
+	}
+	if !report.Passed {
+		return report, &Error{Report: report}
+	}
+	return report, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package preflight_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloud-barista/mc-data-manager/pkg/preflight"
+)
+
+func TestCheck(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "backup", "today")
+
+	report, err := preflight.Check(target, 1000, nil)
+	if err != nil {
+		t.Fatalf("small backup: %v", err)
+	}
+	if !report.Writable || report.RequiredBytes != 1100 {
+		t.Errorf("report = %+v", report)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("the check must not create the target")
+	}
+
+	var perr *preflight.Error
+	_, err = preflight.Check(target, 1<<60, errors.New("listing incomplete"))
+	if !errors.As(err, &perr) {
+		t.Fatalf("huge backup: err = %v", err)
+	}
+	codes := map[string]bool{}
+	for _, p := range perr.Report.Problems {
+		codes[p.Code] = p.Fatal
+	}
+	if fatal, ok := codes[preflight.CodeInsufficientSpace]; !ok || !fatal {
+		t.Errorf("problems = %+v", perr.Report.Problems)
+	}
+	if fatal, ok := codes[preflight.CodeEstimateFailed]; !ok || fatal {
+		t.Errorf("a failed estimate must be a warning: %+v", perr.Report.Problems)
+	}
+
+	file := filepath.Join(root, "file")
+	os.WriteFile(file, nil, 0644)
+	if _, err := preflight.Check(filepath.Join(file, "sub"), 0, nil); !errors.As(err, &perr) || perr.Report.Problems[0].Code != preflight.CodeNotDirectory {
+		t.Errorf("file as target: err = %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package progress
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+const (
+	// maxErrors is the number of recent errors kept by a tracker
+	maxErrors = 20
+	// retention is how long the tracker of a finished task stays registered
+	retention = time.Hour
+)
+
+// Tracker collects the progress counters of a single task.
+//
+// All methods are safe for concurrent use and a nil *Tracker is a no-op,
+// so controllers can call it without checking whether progress is enabled.
+type Tracker struct {
+	taskID string
+
+	objectsDone    atomic.Int64
+	objectsTotal   atomic.Int64
+	objectsSkipped atomic.Int64
+	objectsFailed  atomic.Int64
+	bytesDone      atomic.Int64
+	bytesTotal     atomic.Int64
+
+	mu         sync.Mutex
+	status     models.Status
+	current    string
+	startedAt  time.Time
+	updatedAt  time.Time
+	finishedAt *time.Time
+	errors     []models.ProgressError
+}
+
+var (
+	registry   = map[string]*Tracker{}
+	registryMu sync.Mutex
+)
+
+// Register creates a tracker for taskID, replacing the tracker of a previous run.
+func Register(taskID string) *Tracker {
+	now := time.Now()
+	t := &Tracker{
+		taskID:    taskID,
+		status:    models.StatusPending,
+		startedAt: now,
+		updatedAt: now,
+		errors:    []models.ProgressError{},
+	}
+
+	registryMu.Lock()
+	expire(now)
+	registry[taskID] = t
+	registryMu.Unlock()
+	return t
+}
+
+// Get returns the tracker registered for taskID.
+func Get(taskID string) (*Tracker, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	t, ok := registry[taskID]
+	if ok && t.expired(time.Now()) {
+		delete(registry, taskID)
+		return nil, false
+	}
+	return t, ok
+}
+
+// Remove unregisters the tracker of taskID, when the task is deleted.
+func Remove(taskID string) {
+	registryMu.Lock()
+	delete(registry, taskID)
+	registryMu.Unlock()
+}
+
+// expire removes the trackers of the tasks that finished more than retention before now.
+// The caller holds registryMu.
+func expire(now time.Time) {
+	for taskID, t := range registry {
+		if t.expired(now) {
+			delete(registry, taskID)
+		}
+	}
+}
+
+func (t *Tracker) expired(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.finishedAt != nil && now.Sub(*t.finishedAt) > retention
+}
+
+// Lookup returns the tracker registered for taskID or nil.
+func Lookup(taskID string) *Tracker {
+	t, _ := Get(taskID)
+	return t
+}
+
+// AddTotal adds planned objects and bytes.
+func (t *Tracker) AddTotal(objects, bytes int64) {
+	if t == nil {
+		return
+	}
+	t.objectsTotal.Add(objects)
+	t.bytesTotal.Add(bytes)
+	t.touch()
+}
+
+// AddBytes adds transferred bytes.
+func (t *Tracker) AddBytes(n int64) {
+	if t == nil {
+		return
+	}
+	t.bytesDone.Add(n)
+	t.touch()
+}
+
+// ObjectDone marks one object as transferred.
+func (t *Tracker) ObjectDone(name string) {
+	if t == nil {
+		return
+	}
+	t.objectsDone.Add(1)
+	t.SetCurrent(name)
+}
+
+// Skip marks objects that did not need to be transferred.
+func (t *Tracker) Skip(objects int64) {
+	if t == nil {
+		return
+	}
+	t.objectsSkipped.Add(objects)
+	t.touch()
+}
+
+// Fail records a failed object.
+func (t *Tracker) Fail(name string, err error) {
+	if t == nil || err == nil {
+		return
+	}
+	t.objectsFailed.Add(1)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.updatedAt = time.Now()
+	t.errors = append(t.errors, models.ProgressError{Name: name, Error: err.Error(), Time: t.updatedAt})
+	if len(t.errors) > maxErrors {
+		t.errors = t.errors[len(t.errors)-maxErrors:]
+	}
+}
+
+// SetCurrent sets the name of the object or table being processed.
+func (t *Tracker) SetCurrent(name string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current = name
+	t.updatedAt = time.Now()
+}
+
+// SetStatus sets the task status.
+func (t *Tracker) SetStatus(status models.Status) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status = status
+	t.updatedAt = time.Now()
+}
+
+// Finish sets the final status of the task.
+func (t *Tracker) Finish(status models.Status) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.status = status
+	t.current = ""
+	t.updatedAt = now
+	t.finishedAt = &now
+}
+
+// Reader wraps r so that every read is counted as transferred bytes.
+func (t *Tracker) Reader(r io.Reader) io.Reader {
+	if t == nil {
+		return r
+	}
+	return &countingReader{r: r, t: t}
+}
+
+// Snapshot returns the current counters with throughput and ETA.
+func (t *Tracker) Snapshot() models.TaskProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := models.TaskProgress{
+		TaskID:         t.taskID,
+		Status:         t.status,
+		Current:        t.current,
+		ObjectsDone:    t.objectsDone.Load(),
+		ObjectsTotal:   t.objectsTotal.Load(),
+		ObjectsSkipped: t.objectsSkipped.Load(),
+		ObjectsFailed:  t.objectsFailed.Load(),
+		BytesDone:      t.bytesDone.Load(),
+		BytesTotal:     t.bytesTotal.Load(),
+		ETASeconds:     -1,
+		StartedAt:      t.startedAt,
+		UpdatedAt:      t.updatedAt,
+		FinishedAt:     t.finishedAt,
+		Errors:         append([]models.ProgressError{}, t.errors...),
+	}
+
+	end := time.Now()
+	if t.finishedAt != nil {
+		end = *t.finishedAt
+	}
+	elapsed := end.Sub(t.startedAt).Seconds()
+	if elapsed > 0 {
+		p.Throughput = float64(p.BytesDone) / elapsed
+	}
+
+	if t.finishedAt != nil {
+		p.ETASeconds = 0
+		return p
+	}
+	switch {
+	case p.BytesTotal > 0 && p.Throughput > 0:
+		remain := p.BytesTotal - p.BytesDone
+		if remain < 0 {
+			remain = 0
+		}
+		p.ETASeconds = int64(float64(remain) / p.Throughput)
+	case p.ObjectsTotal > 0 && p.ObjectsDone > 0 && elapsed > 0:
+		remain := p.ObjectsTotal - p.ObjectsDone - p.ObjectsFailed
+		if remain < 0 {
+			remain = 0
+		}
+		p.ETASeconds = int64(float64(remain) * elapsed / float64(p.ObjectsDone))
+	}
+	return p
+}
+
+func (t *Tracker) touch() {
+	t.mu.Lock()
+	t.updatedAt = time.Now()
+	t.mu.Unlock()
+}
+
+type countingReader struct {
+	r io.Reader
+	t *Tracker
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	if n > 0 {
+		c.t.bytesDone.Add(int64(n))
+	}
+	return n, err
+}
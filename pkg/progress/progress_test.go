@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package progress
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+func TestNilTracker(t *testing.T) {
+	var tr *Tracker
+	tr.AddTotal(1, 1)
+	tr.AddBytes(1)
+	tr.ObjectDone("a")
+	tr.Skip(1)
+	tr.Fail("a", errors.New("failed"))
+	tr.SetCurrent("a")
+	tr.SetStatus(models.StatusRunning)
+	tr.Finish(models.StatusCompleted)
+	if r := strings.NewReader("abc"); tr.Reader(r) != r {
+		t.Error("a nil tracker must not wrap the reader")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	tr := Register("progress-snapshot")
+	tr.SetStatus(models.StatusRunning)
+	tr.AddTotal(4, 100)
+	if _, err := io.Copy(io.Discard, tr.Reader(strings.NewReader(strings.Repeat("x", 40)))); err != nil {
+		t.Fatal(err)
+	}
+	tr.AddBytes(10)
+	tr.ObjectDone("a")
+	tr.Skip(1)
+	for i := 0; i < maxErrors+5; i++ {
+		tr.Fail(fmt.Sprintf("f%d", i), errors.New("failed"))
+	}
+	tr.Fail("ignored", nil)
+
+	p := tr.Snapshot()
+	if p.TaskID != "progress-snapshot" || p.Status != models.StatusRunning || p.Current != "a" {
+		t.Errorf("snapshot = %+v", p)
+	}
+	if p.ObjectsDone != 1 || p.ObjectsTotal != 4 || p.ObjectsSkipped != 1 || p.ObjectsFailed != maxErrors+5 {
+		t.Errorf("objects = %d/%d, skipped %d, failed %d", p.ObjectsDone, p.ObjectsTotal, p.ObjectsSkipped, p.ObjectsFailed)
+	}
+	if p.BytesDone != 50 || p.BytesTotal != 100 {
+		t.Errorf("bytes = %d/%d, want 50/100", p.BytesDone, p.BytesTotal)
+	}
+	if len(p.Errors) != maxErrors || p.Errors[0].Name != "f5" {
+		t.Errorf("kept %d errors from %v, want the last %d", len(p.Errors), p.Errors[0].Name, maxErrors)
+	}
+	if p.FinishedAt != nil {
+		t.Error("a running task has no finish time")
+	}
+
+	tr.Finish(models.StatusCompleted)
+	p = tr.Snapshot()
+	if p.Status != models.StatusCompleted || p.FinishedAt == nil || p.ETASeconds != 0 || p.Current != "" {
+		t.Errorf("finished snapshot = %+v", p)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	first := Register("progress-registry")
+	second := Register("progress-registry")
+	if first == second || Lookup("progress-registry") != second {
+		t.Error("Register must replace the tracker of a previous run")
+	}
+	if Lookup("progress-missing") != nil {
+		t.Error("an unknown task has no tracker")
+	}
+
+	Remove("progress-registry")
+	if _, ok := Get("progress-registry"); ok {
+		t.Error("Remove must unregister the tracker")
+	}
+}
+
+func TestExpire(t *testing.T) {
+	running := Register("progress-running")
+	finished := Register("progress-finished")
+	finished.Finish(models.StatusCompleted)
+
+	registryMu.Lock()
+	expire(time.Now().Add(retention / 2))
+	registryMu.Unlock()
+	if Lookup("progress-finished") != finished {
+		t.Fatal("a recently finished tracker must stay registered")
+	}
+
+	registryMu.Lock()
+	expire(time.Now().Add(2 * retention))
+	registryMu.Unlock()
+	if Lookup("progress-finished") != nil {
+		t.Error("a finished tracker must expire")
+	}
+	if Lookup("progress-running") != running {
+		t.Error("a running tracker must not expire")
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package quota enforces the transfer limits of tasks.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+const (
+	PhasePlanning  = "planning"
+	PhaseExecution = "execution"
+)
+
+// bytesPerGB is the unit of egress prices.
+const bytesPerGB = 1 << 30
+
+var (
+	registry   = map[string]*Guard{}
+	registryMu sync.Mutex
+)
+
+// ExceededError reports the limit a task exceeded.
+type ExceededError struct {
+	Phase  string
+	Reason string
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("transfer quota exceeded during %s: %s", e.Phase, e.Reason)
+}
+
+// Guard counts the transfers of a task against its quota.
+// All methods of a nil *Guard do nothing, so tasks without a quota need no checks.
+type Guard struct {
+	limits     models.TransferQuota
+	pricePerGB float64
+	cancel     context.CancelCauseFunc
+
+	mu      sync.Mutex
+	planned models.QuotaUsage
+	used    models.QuotaUsage
+	err     *ExceededError
+}
+
+// Register creates the guard of taskID. cancel is called with the *ExceededError
+// once the quota is exceeded. It returns nil and removes the guard of a previous
+// run when limits is nil.
+func Register(taskID string, limits *models.TransferQuota, pricePerGB float64, cancel context.CancelCauseFunc) *Guard {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if limits == nil {
+		delete(registry, taskID)
+		return nil
+	}
+	g := &Guard{limits: *limits, pricePerGB: pricePerGB, cancel: cancel}
+	registry[taskID] = g
+	return g
+}
+
+// Lookup returns the guard registered for taskID or nil.
+func Lookup(taskID string) *Guard {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return registry[taskID]
+}
+
+// ExceededStatus returns the status a task ends in when it exceeds limits:
+// paused when limits say so, failed otherwise.
+func ExceededStatus(limits *models.TransferQuota) models.Status {
+	if limits != nil && limits.OnExceed == models.QuotaPause {
+		return models.StatusPaused
+	}
+	return models.StatusFailed
+}
+
+// Cost returns the estimated egress cost of bytes.
+func (g *Guard) Cost(bytes int64) float64 {
+	if g == nil {
+		return 0
+	}
+	return float64(bytes) / bytesPerGB * g.pricePerGB
+}
+
+// Plan checks the objects and bytes a transfer is about to copy before it starts.
+func (g *Guard) Plan(objects, bytes int64) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err != nil {
+		return g.err
+	}
+	g.planned.Objects += objects
+	g.planned.Bytes += bytes
+	g.planned.Cost = g.Cost(g.planned.Bytes)
+	return g.check(PhasePlanning, g.planned)
+}
+
+// Reader counts one object read through r and the bytes read from it.
+// Reads fail once the quota is exceeded.
+func (g *Guard) Reader(r io.Reader) io.Reader {
+	if g == nil {
+		return r
+	}
+	return &quotaReader{g: g, r: r, err: g.add(1, 0)}
+}
+
+// Err returns the *ExceededError once the quota is exceeded.
+func (g *Guard) Err() error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err == nil {
+		return nil
+	}
+	return g.err
+}
+
+// Report returns the planned and used transfers of the task.
+func (g *Guard) Report() models.QuotaReport {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	report := models.QuotaReport{
+		Quota:      g.limits,
+		PricePerGB: g.pricePerGB,
+		Planned:    g.planned,
+		Used:       g.used,
+	}
+	if g.err != nil {
+		report.Phase = g.err.Phase
+		report.Reason = g.err.Reason
+	}
+	return report
+}
+
+func (g *Guard) add(objects, bytes int64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err != nil {
+		return g.err
+	}
+	g.used.Objects += objects
+	g.used.Bytes += bytes
+	g.used.Cost = g.Cost(g.used.Bytes)
+	return g.check(PhaseExecution, g.used)
+}
+
+// check records and returns the first limit usage exceeds. g.mu must be held.
+func (g *Guard) check(phase string, usage models.QuotaUsage) error {
+	var reason string
+	switch {
+	case g.limits.MaxObjects > 0 && usage.Objects > g.limits.MaxObjects:
+		reason = fmt.Sprintf("%d objects exceed maxObjects %d", usage.Objects, g.limits.MaxObjects)
+	case g.limits.MaxBytes > 0 && usage.Bytes > g.limits.MaxBytes:
+		reason = fmt.Sprintf("%d bytes exceed maxBytes %d", usage.Bytes, g.limits.MaxBytes)
+	case g.limits.MaxCost > 0 && usage.Cost > g.limits.MaxCost:
+		reason = fmt.Sprintf("estimated egress cost %.2f exceeds maxCost %.2f", usage.Cost, g.limits.MaxCost)
+	default:
+		return nil
+	}
+
+	g.err = &ExceededError{Phase: phase, Reason: reason}
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
+
+type quotaReader struct {
+	g   *Guard
+	r   io.Reader
+	err error
+}
+
+func (q *quotaReader) Read(b []byte) (int, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+	n, err := q.r.Read(b)
+	if n > 0 {
+		if qerr := q.g.add(0, int64(n)); qerr != nil {
+			q.err = qerr
+			return n, qerr
+		}
+	}
+	return n, err
+}
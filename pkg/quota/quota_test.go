@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package quota_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/quota"
+)
+
+const gb = 1 << 30
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  models.TransferQuota
+		price   float64
+		plans   [][2]int64
+		wantErr bool
+		reason  string
+	}{
+		{"within", models.TransferQuota{MaxObjects: 10, MaxBytes: 100}, 0, [][2]int64{{5, 50}, {5, 50}}, false, ""},
+		{"objects", models.TransferQuota{MaxObjects: 10}, 0, [][2]int64{{6, 0}, {5, 0}}, true, "11 objects exceed maxObjects 10"},
+		{"bytes", models.TransferQuota{MaxBytes: 100}, 0, [][2]int64{{1, 101}}, true, "101 bytes exceed maxBytes 100"},
+		{"cost", models.TransferQuota{MaxCost: 1}, 0.09, [][2]int64{{1, 20 * gb}}, true, "estimated egress cost 1.80 exceeds maxCost 1.00"},
+		{"cost within", models.TransferQuota{MaxCost: 1}, 0.09, [][2]int64{{1, 10 * gb}}, false, ""},
+		{"no limits", models.TransferQuota{}, 0.09, [][2]int64{{1 << 20, 1 << 40}}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cause error
+			g := quota.Register("plan-"+tt.name, &tt.limits, tt.price, func(err error) { cause = err })
+			var err error
+			for _, p := range tt.plans {
+				if err = g.Plan(p[0], p[1]); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Plan err = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+			var exceeded *quota.ExceededError
+			if !errors.As(err, &exceeded) || exceeded.Phase != quota.PhasePlanning || exceeded.Reason != tt.reason {
+				t.Errorf("err = %#v, want planning %q", err, tt.reason)
+			}
+			if cause != err || g.Err() != err {
+				t.Errorf("the task must be cancelled with the exceeded error, cause = %v", cause)
+			}
+			if report := g.Report(); report.Phase != quota.PhasePlanning || report.Reason != tt.reason {
+				t.Errorf("report = %+v", report)
+			}
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  models.TransferQuota
+		objects []string
+		wantErr string
+	}{
+		{"within", models.TransferQuota{MaxObjects: 2, MaxBytes: 10}, []string{"hello", "world"}, ""},
+		{"bytes", models.TransferQuota{MaxBytes: 8}, []string{"hello", "world"}, "10 bytes exceed maxBytes 8"},
+		{"objects", models.TransferQuota{MaxObjects: 1}, []string{"a", "b"}, "2 objects exceed maxObjects 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := quota.Register("reader-"+tt.name, &tt.limits, 0, func(error) {})
+			var err error
+			for _, data := range tt.objects {
+				if _, err = io.Copy(io.Discard, g.Reader(strings.NewReader(data))); err != nil {
+					break
+				}
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var exceeded *quota.ExceededError
+			if !errors.As(err, &exceeded) || exceeded.Phase != quota.PhaseExecution || exceeded.Reason != tt.wantErr {
+				t.Fatalf("err = %v, want execution %q", err, tt.wantErr)
+			}
+			// later reads fail at once
+			if _, err := g.Reader(strings.NewReader("x")).Read(make([]byte, 1)); err == nil {
+				t.Error("reads after the quota is exceeded must fail")
+			}
+		})
+	}
+}
+
+func TestRegisterLookup(t *testing.T) {
+	g := quota.Register("task", &models.TransferQuota{MaxObjects: 1}, 0, nil)
+	if quota.Lookup("task") != g {
+		t.Fatal("Lookup must return the registered guard")
+	}
+	if quota.Register("task", nil, 0, nil) != nil || quota.Lookup("task") != nil {
+		t.Fatal("a run without a quota must remove the guard of the previous run")
+	}
+
+	var none *quota.Guard
+	if none.Plan(1<<40, 1<<40) != nil || none.Err() != nil {
+		t.Error("a nil guard must not limit anything")
+	}
+}
+
+func TestExceededStatus(t *testing.T) {
+	tests := []struct {
+		limits *models.TransferQuota
+		want   models.Status
+	}{
+		{nil, models.StatusFailed},
+		{&models.TransferQuota{}, models.StatusFailed},
+		{&models.TransferQuota{OnExceed: models.QuotaFail}, models.StatusFailed},
+		{&models.TransferQuota{OnExceed: models.QuotaPause}, models.StatusPaused},
+	}
+	for _, tt := range tests {
+		if got := quota.ExceededStatus(tt.limits); got != tt.want {
+			t.Errorf("ExceededStatus(%+v) = %s, want %s", tt.limits, got, tt.want)
+		}
+	}
+}
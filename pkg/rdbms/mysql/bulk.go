@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/rs/zerolog/log"
+)
+
+// readerSeq numbers the reader handlers of LOAD DATA LOCAL INFILE.
+var readerSeq atomic.Int64
+
+// csvEscaper escapes a value for the FIELDS and LINES clauses used by LoadRows.
+var csvEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\x00", `\0`)
+
+// LocalInfile reports whether the server accepts LOAD DATA LOCAL INFILE.
+func (d *MysqlDBMS) LocalInfile() (bool, error) {
+	var enabled bool
+	if err := d.db.QueryRowContext(d.ctx, "SELECT @@GLOBAL.local_infile").Scan(&enabled); err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return false, err
+	}
+	return enabled, nil
+}
+
+// LoadRows loads the rows passed by rows into a table with LOAD DATA LOCAL INFILE,
+// run through exec. The rows are streamed to the server as CSV while rows runs,
+// so exec must run the statement once: a second run would load an empty stream.
+// The rows are values of columns as GetRows reads them.
+func (d *MysqlDBMS) LoadRows(exec func(query string) error, tableName string, columns []models.Column, rows func(emit func(row []sql.NullString) error) error) error {
+	pr, pw := io.Pipe()
+	name := fmt.Sprintf("%s-%d", tableName, readerSeq.Add(1))
+	gomysql.RegisterReaderHandler(name, func() io.Reader { return pr })
+	defer gomysql.DeregisterReaderHandler(name)
+
+	rowsErr := make(chan error, 1)
+	go func() {
+		err := rows(func(row []sql.NullString) error {
+			return writeCSVRow(pw, row)
+		})
+		pw.CloseWithError(err)
+		rowsErr <- err
+	}()
+
+	targets, sets := loadTargets(columns)
+	loadErr := exec(fmt.Sprintf("LOAD DATA LOCAL INFILE 'Reader::%s' INTO TABLE %s CHARACTER SET utf8mb4 "+
+		`FIELDS TERMINATED BY ',' ENCLOSED BY '"' ESCAPED BY '\\' LINES TERMINATED BY '\n' (%s)%s`,
+		name, escapeColumnName(tableName), targets, sets))
+	// stop rows when the server gave up before reading everything
+	pr.CloseWithError(io.ErrClosedPipe)
+
+	if err := <-rowsErr; err != nil && err != io.ErrClosedPipe {
+		return err
+	}
+	return loadErr
+}
+
+// writeCSVRow writes a row as a line of LoadRows' CSV, with NULL as \N.
+func writeCSVRow(w io.Writer, row []sql.NullString) error {
+	var line strings.Builder
+	for i, val := range row {
+		if i > 0 {
+			line.WriteString(",")
+		}
+		if !val.Valid {
+			line.WriteString(`\N`)
+			continue
+		}
+		line.WriteString(`"`)
+		line.WriteString(csvEscaper.Replace(val.String))
+		line.WriteString(`"`)
+	}
+	line.WriteString("\n")
+	_, err := io.WriteString(w, line.String())
+	return err
+}
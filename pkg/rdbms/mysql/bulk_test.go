@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+func TestWriteCSVRow(t *testing.T) {
+	row := []sql.NullString{
+		{String: "plain", Valid: true},
+		{String: `say "hi", \ bye`, Valid: true},
+		{String: "two\nlines\r", Valid: true},
+		{},
+		{String: "", Valid: true},
+	}
+
+	var b strings.Builder
+	if err := writeCSVRow(&b, row); err != nil {
+		t.Fatal(err)
+	}
+	want := `"plain","say \"hi\", \\ bye","two\nlines\r",\N,""` + "\n"
+	if b.String() != want {
+		t.Errorf("writeCSVRow = %q, want %q", b.String(), want)
+	}
+}
+
+type failingExecer struct{ calls []string }
+
+func (e *failingExecer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	e.calls = append(e.calls, query)
+	return nil, errors.New("rejected")
+}
+
+func TestExecRetriesOnlyRewrittenStatements(t *testing.T) {
+	for _, provider := range []models.Provider{models.AWS, models.NCP} {
+		d := &MysqlDBMS{provider: provider, ctx: context.Background()}
+		e := &failingExecer{}
+		if err := d.exec(e, "LOAD DATA LOCAL INFILE 'Reader::t-1' INTO TABLE `t`"); err == nil {
+			t.Errorf("%s: a rejected load must fail", provider)
+		}
+		if len(e.calls) != 1 {
+			t.Errorf("%s: load ran %d times, want 1", provider, len(e.calls))
+		}
+	}
+
+	d := &MysqlDBMS{provider: models.NCP, ctx: context.Background()}
+	e := &failingExecer{}
+	_ = d.exec(e, "CREATE DATABASE `db` DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci;")
+	if len(e.calls) != 2 || !strings.HasPrefix(e.calls[1], "CALL sys.ncp_create_db") {
+		t.Errorf("NCP CREATE DATABASE calls = %q", e.calls)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultCharset and defaultCollation are what the force policy converts to by default
+	defaultCharset   = "utf8mb4"
+	defaultCollation = "utf8mb4_general_ci"
+
+	// maxKeyBytes is the key length limit of InnoDB with the DYNAMIC and COMPRESSED row formats
+	maxKeyBytes = 3072
+)
+
+// charsetPattern matches a character set with an optional collation, or a collation alone,
+// in the CREATE DATABASE and CREATE TABLE forms: CHARACTER SET x COLLATE y and CHARSET=x COLLATE=y.
+var charsetPattern = regexp.MustCompile(`(?i)\b(?:(CHARACTER\s+SET|CHARSET)(\s*=\s*|\s+)(\w+)(?:\s+COLLATE(\s*=\s*|\s+)(\w+))?|COLLATE(\s*=\s*|\s+)(\w+))`)
+
+// SetCharsetPolicy sets how ShowCreateDBSql and ShowCreateTableSql convert character sets and collations.
+func (d *MysqlDBMS) SetCharsetPolicy(policy models.CharsetPolicy) {
+	d.charset = policy
+}
+
+// convertCharsets rewrites the character sets and collations of a CREATE statement by the policy.
+func (d *MysqlDBMS) convertCharsets(query string) string {
+	if d.charset.Mode == models.CharsetPreserve {
+		return query
+	}
+	return charsetPattern.ReplaceAllStringFunc(query, func(match string) string {
+		m := charsetPattern.FindStringSubmatch(match)
+		if m[1] == "" {
+			// a collation alone keeps the character set it inherits, which is converted where it is declared
+			_, collation := convertCharset(d.charset, "", m[7])
+			if collation == "" {
+				return ""
+			}
+			return "COLLATE" + m[6] + collation
+		}
+
+		charset, collation := convertCharset(d.charset, m[3], m[5])
+		out := m[1] + m[2] + charset
+		if collation != "" {
+			sep := m[4]
+			if sep == "" {
+				sep = m[2]
+			}
+			out += " COLLATE" + sep + collation
+		}
+		return out
+	})
+}
+
+// convertCharset returns the target character set and collation of a source
+// character set and collation, either of which may be empty.
+func convertCharset(policy models.CharsetPolicy, charset, collation string) (string, string) {
+	switch policy.Mode {
+	case models.CharsetPreserve:
+		return charset, collation
+	case models.CharsetMap:
+		for _, key := range []string{collation, charset} {
+			if key == "" {
+				continue
+			}
+			for from, to := range policy.Collations {
+				if strings.EqualFold(from, key) {
+					return collationCharset(to), to
+				}
+			}
+		}
+		return charset, collation
+	}
+
+	// binary strings have no characters to convert
+	if strings.EqualFold(charset, "binary") || strings.EqualFold(collation, "binary") {
+		return charset, collation
+	}
+	targetCharset, targetCollation := policy.Charset, policy.Collation
+	if targetCharset == "" && targetCollation == "" {
+		targetCharset, targetCollation = defaultCharset, defaultCollation
+	}
+	if targetCharset == "" {
+		targetCharset = collationCharset(targetCollation)
+	}
+	return targetCharset, targetCollation
+}
+
+// collationCharset returns the character set of a collation, which prefixes its name.
+func collationCharset(collation string) string {
+	charset, _, _ := strings.Cut(collation, "_")
+	return charset
+}
+
+// CharsetIssues returns the indexes of dbName whose keys would exceed the
+// InnoDB key length limit once their columns are converted by the policy.
+// Only the string columns of a key are counted.
+func (d *MysqlDBMS) CharsetIssues(dbName string) ([]models.CharsetIssue, error) {
+	if d.charset.Mode == models.CharsetPreserve {
+		return nil, nil
+	}
+
+	q, done := d.reader()
+	defer done()
+	maxLen := map[string]int64{}
+	csRows, err := q.QueryContext(d.ctx, "SELECT CHARACTER_SET_NAME, MAXLEN FROM information_schema.CHARACTER_SETS")
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	defer csRows.Close()
+	for csRows.Next() {
+		var name string
+		var n int64
+		if err := csRows.Scan(&name, &n); err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return nil, err
+		}
+		maxLen[strings.ToLower(name)] = n
+	}
+	if err := csRows.Err(); err != nil {
+		return nil, err
+	}
+
+	keyRows, err := q.QueryContext(d.ctx, "SELECT s.TABLE_NAME, s.INDEX_NAME, s.COLUMN_NAME, s.SUB_PART, c.CHARACTER_MAXIMUM_LENGTH, c.CHARACTER_SET_NAME, c.COLLATION_NAME "+
+		"FROM information_schema.STATISTICS s JOIN information_schema.COLUMNS c "+
+		"ON c.TABLE_SCHEMA = s.TABLE_SCHEMA AND c.TABLE_NAME = s.TABLE_NAME AND c.COLUMN_NAME = s.COLUMN_NAME "+
+		"WHERE s.TABLE_SCHEMA = ? AND s.INDEX_TYPE = 'BTREE' AND c.CHARACTER_SET_NAME IS NOT NULL "+
+		"ORDER BY s.TABLE_NAME, s.INDEX_NAME, s.SEQ_IN_INDEX", dbName)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	defer keyRows.Close()
+
+	var issues []models.CharsetIssue
+	var key *models.CharsetIssue
+	flush := func() {
+		if key != nil && key.KeyBytes > key.MaxBytes {
+			issues = append(issues, *key)
+		}
+	}
+	for keyRows.Next() {
+		var table, index, column, charset, collation string
+		var subPart, chars sql.NullInt64
+		if err := keyRows.Scan(&table, &index, &column, &subPart, &chars, &charset, &collation); err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return nil, err
+		}
+		if key == nil || key.Table != table || key.Index != index {
+			flush()
+			key = &models.CharsetIssue{Table: table, Index: index, MaxBytes: maxKeyBytes}
+		}
+		if subPart.Valid {
+			chars = subPart
+		}
+		target, _ := convertCharset(d.charset, charset, collation)
+		bytes, ok := maxLen[strings.ToLower(target)]
+		if !ok {
+			bytes = 4
+		}
+		key.Columns = append(key.Columns, column)
+		key.KeyBytes += chars.Int64 * bytes
+	}
+	if err := keyRows.Err(); err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	flush()
+	return issues, nil
+}
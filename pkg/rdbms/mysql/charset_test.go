@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"testing"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+func TestConvertCharsets(t *testing.T) {
+	table := "CREATE TABLE `t` (\n" +
+		"  `a` varchar(10) CHARACTER SET latin1 COLLATE latin1_bin DEFAULT NULL,\n" +
+		"  `b` varchar(10) COLLATE utf8mb4_bin DEFAULT NULL,\n" +
+		"  `c` varbinary(10) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci"
+	tests := []struct {
+		name   string
+		policy models.CharsetPolicy
+		want   string
+	}{
+		{"default", models.CharsetPolicy{}, "CREATE TABLE `t` (\n" +
+			"  `a` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL,\n" +
+			"  `b` varchar(10) COLLATE utf8mb4_general_ci DEFAULT NULL,\n" +
+			"  `c` varbinary(10) DEFAULT NULL\n" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci"},
+		{"preserve", models.CharsetPolicy{Mode: models.CharsetPreserve}, table},
+		{"force collation", models.CharsetPolicy{Mode: models.CharsetForce, Collation: "utf8mb4_0900_ai_ci"}, "CREATE TABLE `t` (\n" +
+			"  `a` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL,\n" +
+			"  `b` varchar(10) COLLATE utf8mb4_0900_ai_ci DEFAULT NULL,\n" +
+			"  `c` varbinary(10) DEFAULT NULL\n" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci"},
+		{"map", models.CharsetPolicy{Mode: models.CharsetMap, Collations: map[string]string{"latin1": "utf8mb4_bin", "utf8mb4_0900_ai_ci": "utf8mb4_unicode_ci"}}, "CREATE TABLE `t` (\n" +
+			"  `a` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL,\n" +
+			"  `b` varchar(10) COLLATE utf8mb4_bin DEFAULT NULL,\n" +
+			"  `c` varbinary(10) DEFAULT NULL\n" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci"},
+	}
+	for _, tt := range tests {
+		d := &MysqlDBMS{charset: tt.policy}
+		if got := d.convertCharsets(table); got != tt.want {
+			t.Errorf("%s: convertCharsets =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+
+	d := &MysqlDBMS{}
+	db := "CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET latin1 */"
+	if got, want := d.convertCharsets(db), "CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci */"; got != want {
+		t.Errorf("convertCharsets = %s, want %s", got, want)
+	}
+}
+
+func TestCharsetWrappers(t *testing.T) {
+	db := "CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET latin1 */"
+	if got, want := EnsureCharsetAndCollate(db, "latin1", ""), "CREATE DATABASE `app` /*!40100 DEFAULT CHARACTER SET utf8mb4 */ COLLATE utf8mb4_general_ci"; got != want {
+		t.Errorf("EnsureCharsetAndCollate = %s, want %s", got, want)
+	}
+	table := "CREATE TABLE `t` (`a` int) ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_swedish_ci"
+	if got, want := ReplaceCharsetAndCollate(table), "CREATE TABLE `t` (`a` int) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci"; got != want {
+		t.Errorf("ReplaceCharsetAndCollate = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// integerTypes are the column types a table can be split on.
+var integerTypes = []string{"tinyint", "smallint", "mediumint", "int", "bigint"}
+
+// TableDependencies returns the tables of dbName that each table references with a foreign key.
+func (d *MysqlDBMS) TableDependencies(dbName string) (map[string][]string, error) {
+	q, done := d.reader()
+	defer done()
+	rows, err := q.QueryContext(d.ctx,
+		"SELECT DISTINCT TABLE_NAME, REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE "+
+			"WHERE TABLE_SCHEMA = ? AND REFERENCED_TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME IS NOT NULL", dbName, dbName)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	deps := map[string][]string{}
+	for rows.Next() {
+		var table, referenced string
+		if err := rows.Scan(&table, &referenced); err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return nil, err
+		}
+		deps[table] = append(deps[table], referenced)
+	}
+	return deps, rows.Err()
+}
+
+// TableChunks splits a table with a single integer primary key into WHERE
+// conditions on key ranges of about rows rows each. It returns nil when the
+// table is smaller or cannot be split.
+func (d *MysqlDBMS) TableChunks(dbName, tableName string, rows int64) ([]string, error) {
+	if rows <= 0 {
+		return nil, nil
+	}
+
+	q, done := d.reader()
+	defer done()
+
+	var estimate int64
+	err := q.QueryRowContext(d.ctx,
+		"SELECT COALESCE(TABLE_ROWS, 0) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", dbName, tableName).Scan(&estimate)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	if estimate <= rows {
+		return nil, nil
+	}
+
+	keyRows, err := q.QueryContext(d.ctx,
+		"SELECT COLUMN_NAME, DATA_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_KEY = 'PRI'", dbName, tableName)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	defer keyRows.Close()
+
+	var keys, types []string
+	for keyRows.Next() {
+		var key, dataType string
+		if err := keyRows.Scan(&key, &dataType); err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return nil, err
+		}
+		keys = append(keys, key)
+		types = append(types, strings.ToLower(dataType))
+	}
+	if err := keyRows.Err(); err != nil {
+		return nil, err
+	}
+	keyRows.Close()
+	if len(keys) != 1 || !slices.Contains(integerTypes, types[0]) {
+		log.Info().Msgf("table %s has no single integer primary key and is exported whole", tableName)
+		return nil, nil
+	}
+
+	key := escapeColumnName(keys[0])
+	var low, high int64
+	err = q.QueryRowContext(d.ctx, fmt.Sprintf("SELECT COALESCE(MIN(%s), 0), COALESCE(MAX(%s), 0) FROM %s.%s",
+		key, key, escapeColumnName(dbName), escapeColumnName(tableName))).Scan(&low, &high)
+	if err != nil {
+		// unsigned keys above the int64 range are not split
+		log.Info().Err(err).Msgf("table %s is exported whole", tableName)
+		return nil, nil
+	}
+	return keyRanges(key, low, high, (estimate+rows-1)/rows), nil
+}
+
+// keyRanges splits the keys from low to high into count conditions on key.
+// The first and last condition are open so that rows inserted meanwhile are not lost.
+func keyRanges(key string, low, high, count int64) []string {
+	step := (high-low)/count + 1
+	if count <= 1 || step <= 0 {
+		return nil
+	}
+
+	var chunks []string
+	for bound := low + step; bound <= high; bound += step {
+		if len(chunks) == 0 {
+			chunks = append(chunks, fmt.Sprintf("%s < %d", key, bound))
+		} else {
+			chunks = append(chunks, fmt.Sprintf("%s >= %d AND %s < %d", key, bound-step, key, bound))
+		}
+		if bound > high-step {
+			// the next bound would overflow or pass high
+			return append(chunks, fmt.Sprintf("%s >= %d", key, bound))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyRanges(t *testing.T) {
+	got := keyRanges("`id`", 1, 100, 4)
+	want := []string{"`id` < 26", "`id` >= 26 AND `id` < 51", "`id` >= 51 AND `id` < 76", "`id` >= 76"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("keyRanges = %q, want %q", got, want)
+	}
+	if got := keyRanges("`id`", 5, 5, 3); got != nil {
+		t.Errorf("keyRanges of a single key = %q", got)
+	}
+}
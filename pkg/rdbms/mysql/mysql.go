@@ -29,6 +29,9 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// DefaultMaxPacketSize is the default size limit in bytes of a multi-row INSERT.
+const DefaultMaxPacketSize = 1 << 20
+
 // mysqlDBMS struct
 type MysqlDBMS struct {
 	provider       models.Provider
@@ -36,6 +39,16 @@ type MysqlDBMS struct {
 	targetProvider models.Provider
 	ctx            context.Context
 	Collector      diagnostics.Collector
+
+	maxPacketSize int
+	disableChecks bool
+	definer       string
+	charset       models.CharsetPolicy
+
+	// pinned holds the connections of a snapshot that the reads take turns on
+	pinned    chan *sql.Conn
+	snapshot  []*sql.Conn
+	holdsLock bool
 }
 
 type MysqlDBOption func(*MysqlDBMS)
@@ -58,10 +71,11 @@ func (d *MysqlDBMS) SetTargetProvdier(provider models.Provider) {
 
 func New(provider models.Provider, sqlDB *sql.DB, opts ...MysqlDBOption) *MysqlDBMS {
 	dms := &MysqlDBMS{
-		provider:  provider,
-		db:        sqlDB,
-		ctx:       context.TODO(),
-		Collector: *diagnostics.NewCollector(sqlDB),
+		provider:      provider,
+		db:            sqlDB,
+		ctx:           context.TODO(),
+		Collector:     *diagnostics.NewCollector(sqlDB),
+		maxPacketSize: DefaultMaxPacketSize,
 	}
 
 	for _, opt := range opts {
@@ -71,13 +85,98 @@ func New(provider models.Provider, sqlDB *sql.DB, opts ...MysqlDBOption) *MysqlD
 	return dms
 }
 
+// SetContext sets the context used by the queries of the database.
+func (d *MysqlDBMS) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// SetMaxPacketSize sets the size limit in bytes of the multi-row INSERTs built by GetInsert.
+func (d *MysqlDBMS) SetMaxPacketSize(size int) {
+	if size > 0 {
+		d.maxPacketSize = size
+	}
+}
+
+// SetDefiner sets the definer that replaces the definers of the views,
+// routines, triggers and events listed by ListSchemaObjects.
+func (d *MysqlDBMS) SetDefiner(definer string) {
+	d.definer = definer
+}
+
+// SetDisableChecks turns off unique and foreign key checks in the sessions of the database.
+func (d *MysqlDBMS) SetDisableChecks(disable bool) {
+	d.disableChecks = disable
+}
+
+// execer is a database handle or a single connection.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// querier is what a read runs on: the pool or a pinned connection.
+type querier interface {
+	execer
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// reader returns what a read runs on: a pinned connection of the snapshot,
+// given back by the returned function, or the pool.
+func (d *MysqlDBMS) reader() (querier, func()) {
+	if d.pinned == nil {
+		return d.db, func() {}
+	}
+	conn := <-d.pinned
+	return conn, func() { d.pinned <- conn }
+}
+
 // Functions that execute EXEC commands in sql
 func (d *MysqlDBMS) Exec(query string) error {
-	_, err := d.db.Exec(query)
+	return d.exec(d.db, query)
+}
+
+// Session runs fn with an exec function bound to a single connection, so that
+// session-scope statements like USE apply to the statements that follow them.
+func (d *MysqlDBMS) Session(fn func(exec func(query string) error) error) error {
+	conn, err := d.db.Conn(d.ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to open a connection")
+		return err
+	}
+	defer conn.Close()
+
+	if d.disableChecks {
+		if _, err := conn.ExecContext(d.ctx, "SET FOREIGN_KEY_CHECKS = 0, UNIQUE_CHECKS = 0"); err != nil {
+			log.Error().Err(err).Msg("Failed to disable checks")
+			return err
+		}
+		defer func() {
+			// the connection goes back to the pool, so it is discarded when the checks stay off
+			if _, err := conn.ExecContext(context.Background(), "SET FOREIGN_KEY_CHECKS = 1, UNIQUE_CHECKS = 1"); err != nil {
+				log.Error().Err(err).Msg("Failed to enable checks")
+				discard(conn)
+			}
+		}()
+	}
+
+	return fn(func(query string) error {
+		return d.exec(conn, query)
+	})
+}
+
+// exec runs query on e. A failed statement is only run again when the NCP
+// rewrite of CREATE DATABASE changes it; any other statement runs once, as
+// running it again is not safe: a LOAD DATA would read an exhausted stream.
+func (d *MysqlDBMS) exec(e execer, query string) error {
+	_, err := e.ExecContext(d.ctx, query)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to execute SQL query")
-		FormatNCPDatabaseCreateSQL(d.provider, &query)
-		_, retryErr := d.db.Exec(query)
+		retry := query
+		FormatNCPDatabaseCreateSQL(d.provider, &retry)
+		if retry == query {
+			return err
+		}
+		_, retryErr := e.ExecContext(d.ctx, retry)
 		if retryErr != nil {
 			log.Error().Err(retryErr).Str("Provider", string(d.provider)).Str("tagetProvider", string(d.provider)).Msg("Failed to execute transformed NCP SQL query")
 			return retryErr
@@ -109,13 +208,15 @@ func EnsureCharsetAndCollate(query, charSet, collate string) string {
 
 // Delete database
 func (d *MysqlDBMS) DeleteDB(dbName string) error {
-	_, err := d.db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbName))
+	_, err := d.db.ExecContext(d.ctx, fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbName))
 	return err
 }
 
 // Get database list
 func (d *MysqlDBMS) ListDB(dst *[]string) error {
-	rows, err := d.db.Query("SHOW DATABASES")
+	q, done := d.reader()
+	defer done()
+	rows, err := q.QueryContext(d.ctx, "SHOW DATABASES")
 	if err != nil {
 		log.Error().Err(err).Msgf("SQL query executed failed %v", rows)
 		return err
@@ -137,23 +238,37 @@ func (d *MysqlDBMS) ListDB(dst *[]string) error {
 	return nil
 }
 
-// Get table list
+// DatabaseSize returns the size in bytes of the table data of dbName, without indexes.
+func (d *MysqlDBMS) DatabaseSize(dbName string) (int64, error) {
+	var size int64
+	err := d.db.QueryRowContext(d.ctx,
+		"SELECT COALESCE(SUM(DATA_LENGTH), 0) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?", dbName).Scan(&size)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return 0, err
+	}
+	return size, nil
+}
+
+// Get table list, without views
 func (d *MysqlDBMS) ListTable(dbName string, dst *[]string) error {
-	_, err := d.db.Exec(fmt.Sprintf("USE %s;", dbName))
+	q, done := d.reader()
+	defer done()
+	_, err := q.ExecContext(d.ctx, fmt.Sprintf("USE %s;", dbName))
 	if err != nil {
 		log.Error().Err(err).Msgf("SQL query executed failed")
 		return err
 	}
 
-	rows, err := d.db.Query("SHOW TABLES")
+	rows, err := q.QueryContext(d.ctx, "SHOW FULL TABLES WHERE Table_type = 'BASE TABLE'")
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
+		var tableName, tableType string
+		if err := rows.Scan(&tableName, &tableType); err != nil {
 			return err
 		}
 		*dst = append(*dst, tableName)
@@ -163,7 +278,9 @@ func (d *MysqlDBMS) ListTable(dbName string, dst *[]string) error {
 
 // ShowCreateDBSql modifies the CREATE DATABASE SQL and returns it
 func (d *MysqlDBMS) ShowCreateDBSql(dbName string, dbCreateSql *string) error {
-	err := d.db.QueryRow(fmt.Sprintf("SHOW CREATE DATABASE %s;", dbName)).Scan(&dbName, dbCreateSql)
+	q, done := d.reader()
+	err := q.QueryRowContext(d.ctx, fmt.Sprintf("SHOW CREATE DATABASE %s;", dbName)).Scan(&dbName, dbCreateSql)
+	done()
 	if err != nil {
 		log.Error().Err(err).Msgf("SQL query executed failed")
 		return err
@@ -172,10 +289,11 @@ func (d *MysqlDBMS) ShowCreateDBSql(dbName string, dbCreateSql *string) error {
 	// Add "IF NOT EXISTS" to the CREATE DATABASE statement
 	*dbCreateSql = strings.Replace(*dbCreateSql, "CREATE DATABASE", "CREATE DATABASE /*!32312 IF NOT EXISTS*/", 1)
 
-	// Ensure charset and collate are utf8mb4 and utf8mb4_general_ci
-	*dbCreateSql = addCharsetIfMissing(*dbCreateSql)
-	*dbCreateSql = addCollateIfMissing(*dbCreateSql)
-	*dbCreateSql = EnsureCharsetAndCollate(*dbCreateSql, extractCharacterSet(*dbCreateSql), extractCollation(*dbCreateSql))
+	// Convert charset and collate by the charset policy, utf8mb4 and utf8mb4_general_ci by default
+	if d.charset.Mode == "" || d.charset.Mode == models.CharsetForce {
+		*dbCreateSql = addCharsetIfMissing(*dbCreateSql)
+	}
+	*dbCreateSql = d.convertCharsets(*dbCreateSql)
 	*dbCreateSql += ";"
 
 	// If the target provider is NCP, modify the SQL to use NCP's specific procedure
@@ -199,94 +317,145 @@ func FormatNCPDatabaseCreateSQL(targetProvider models.Provider, dbCreateSql *str
 
 // Get table create sql
 func (d *MysqlDBMS) ShowCreateTableSql(dbName, tableName string, tableCreateSql *string) error {
-	if err := d.Exec(fmt.Sprintf("USE %s;", dbName)); err != nil {
+	q, done := d.reader()
+	defer done()
+	if err := d.exec(q, fmt.Sprintf("USE %s;", dbName)); err != nil {
 		log.Error().Err(err).Msgf("SQL query executed failed")
 		return err
 	}
-	if err := d.db.QueryRow(fmt.Sprintf("SHOW CREATE TABLE %s;", tableName)).Scan(&tableName, tableCreateSql); err != nil {
+	if err := q.QueryRowContext(d.ctx, fmt.Sprintf("SHOW CREATE TABLE %s;", tableName)).Scan(&tableName, tableCreateSql); err != nil {
 		log.Error().Err(err).Msgf("SQL query executed failed")
 		return err
 	}
 	*tableCreateSql = removeSequenceOption(*tableCreateSql)
 	*tableCreateSql = adjustColumnsToTimestamp(*tableCreateSql)
-	*tableCreateSql = ReplaceCharsetAndCollate(*tableCreateSql)
+	*tableCreateSql = d.convertCharsets(*tableCreateSql)
 	*tableCreateSql += ";"
 	return nil
 }
 
-// Get Insert sql
-func (d *MysqlDBMS) GetInsert(dbName, tableName string, insertSql *[]string) error {
-	colRows, err := d.db.Query("SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", dbName, tableName)
+// ListColumn returns the columns of a table in order, with their data types.
+func (d *MysqlDBMS) ListColumn(dbName, tableName string) ([]models.Column, error) {
+	q, done := d.reader()
+	defer done()
+	colRows, err := q.QueryContext(d.ctx, "SELECT COLUMN_NAME, DATA_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION", dbName, tableName)
 	if err != nil {
 		log.Error().Err(err).Msgf("SQL query executed failed")
-		return err
+		return nil, err
 	}
 	defer colRows.Close()
 
-	var columns []string
+	var columns []models.Column
 	for colRows.Next() {
-		var columnName string
-		if err := colRows.Scan(&columnName); err != nil {
+		var column models.Column
+		if err := colRows.Scan(&column.Name, &column.Type); err != nil {
 			log.Error().Err(err).Msgf("SQL query executed failed")
-			return err
+			return nil, err
 		}
-		columns = append(columns, columnName)
+		columns = append(columns, column)
 	}
+	return columns, colRows.Err()
+}
 
-	tableName = escapeColumnName(tableName)
-	escapedColumns := make([]string, len(columns))
+// GetRows passes the rows of a table matching where, or all rows when it is
+// empty, to emit as they are read. The row is reused for the next call of emit.
+// Binary values are read as hex, bits as integers and geometries as SRID:WKB hex,
+// so that every value is text that literal and LoadRows turn back into the value.
+func (d *MysqlDBMS) GetRows(dbName, tableName string, columns []models.Column, where string, emit func(row []sql.NullString) error) error {
+	selectExprs := make([]string, len(columns))
 	for i, column := range columns {
-		escapedColumns[i] = escapeColumnName(column)
+		selectExprs[i] = selectExpr(column)
 	}
 
-	selectQuery := "SELECT " + strings.Join(escapedColumns, ", ") + " FROM " + tableName
-	selRows, err := d.db.Query(selectQuery)
+	selectQuery := "SELECT " + strings.Join(selectExprs, ", ") + " FROM " + escapeColumnName(dbName) + "." + escapeColumnName(tableName)
+	if where != "" {
+		selectQuery += " WHERE " + where
+	}
+	q, done := d.reader()
+	defer done()
+	selRows, err := q.QueryContext(d.ctx, selectQuery)
 	if err != nil {
 		log.Error().Err(err).Msgf("SQL query executed failed")
 		return err
 	}
 	defer selRows.Close()
 
-	data := []map[string]sql.NullString{}
+	values := make([]sql.NullString, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range columns {
+		valuePtrs[i] = &values[i]
+	}
 
 	for selRows.Next() {
-		values := make([]sql.NullString, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range columns {
-			valuePtrs[i] = &values[i]
-		}
-
-		err := selRows.Scan(valuePtrs...)
-		if err != nil {
+		if err := selRows.Scan(valuePtrs...); err != nil {
 			log.Error().Err(err).Msgf("SQL query executed failed")
 			return err
 		}
-
-		entry := make(map[string]sql.NullString)
-		for i, column := range columns {
-			entry[column] = values[i]
+		if err := emit(values); err != nil {
+			return err
 		}
+	}
+	if err := selRows.Err(); err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return err
+	}
+	return nil
+}
+
+// GetInsert passes multi-row INSERT statements of the table to emit, each
+// at most the max packet size unless a single row is larger.
+func (d *MysqlDBMS) GetInsert(dbName, tableName string, emit func(insertSql string) error) error {
+	return d.GetInsertWhere(dbName, tableName, "", emit)
+}
 
-		data = append(data, entry)
+// GetInsertWhere is GetInsert for the rows matching where.
+func (d *MysqlDBMS) GetInsertWhere(dbName, tableName, where string, emit func(insertSql string) error) error {
+	columns, err := d.ListColumn(dbName, tableName)
+	if err != nil {
+		return err
 	}
 
-	for _, entry := range data {
-		values := []string{}
-		for _, column := range columns {
-			val := entry[column]
-			if val.Valid {
-				escapedValue := ReplaceEscapeString(val.String)
-				values = append(values, fmt.Sprintf("'%v'", escapedValue))
-			} else {
-				values = append(values, "NULL")
-			}
-		}
+	escapedColumns := make([]string, len(columns))
+	for i, column := range columns {
+		escapedColumns[i] = escapeColumnName(column.Name)
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", escapeColumnName(tableName), strings.Join(escapedColumns, ", "))
 
-		insertStatement := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", tableName, strings.Join(escapedColumns, ", "), strings.Join(values, ", "))
-		*insertSql = append(*insertSql, insertStatement)
+	var stmt strings.Builder
+	flush := func() error {
+		if stmt.Len() == 0 {
+			return nil
+		}
+		stmt.WriteString(";")
+		err := emit(stmt.String())
+		stmt.Reset()
+		return err
 	}
 
-	return nil
+	literals := make([]string, len(columns))
+	err = d.GetRows(dbName, tableName, columns, where, func(row []sql.NullString) error {
+		for i, val := range row {
+			literals[i] = literal(columns[i], val)
+		}
+		tuple := "(" + strings.Join(literals, ", ") + ")"
+
+		if stmt.Len() > 0 && stmt.Len()+len(tuple)+2 > d.maxPacketSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if stmt.Len() == 0 {
+			stmt.WriteString(prefix)
+		} else {
+			stmt.WriteString(",")
+		}
+		stmt.WriteString(tuple)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
 }
 
 // Get table create sql
@@ -294,14 +463,6 @@ func (d *MysqlDBMS) Diagnose(schema string, timeInput int64) (diagnostics.TimedR
 	return d.Collector.RunTimed(d.ctx, schema, time.Duration(timeInput)*time.Second)
 }
 
-// addCollateIfMissing adds COLLATE to DEFAULT CHARACTER SET if it's missing
-func addCollateIfMissing(sql string) string {
-	if !strings.Contains(sql, "COLLATE") {
-		sql = sql + " " + "COLLATE utf8mb4_general_ci"
-	}
-	return sql
-}
-
 // addCollateIfMissing adds COLLATE to DEFAULT CHARACTER SET if it's missing
 func addCharsetIfMissing(sql string) string {
 	if !strings.Contains(sql, "DEFAULT CHARACTER SET") && !strings.Contains(sql, "DEFAULT CHARSET") {
@@ -0,0 +1,293 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+// definerPattern matches the DEFINER clause of a view, routine, trigger or event.
+var definerPattern = regexp.MustCompile("(?i)DEFINER\\s*=\\s*(CURRENT_USER(\\(\\))?|(`[^`]*`|'[^']*'|[^\\s@]+)@(`[^`]*`|'[^']*'|[^\\s]+))")
+
+// systemUsers are the accounts of the server itself, which are never migrated.
+var systemUsers = []string{"root", "mysql.sys", "mysql.session", "mysql.infoschema", "mariadb.sys", "rdsadmin"}
+
+// ListSchemaObjects returns the routines, views, triggers and events of dbName
+// in an order they can be created in: functions, procedures, views after the
+// views they select from, triggers, then events.
+func (d *MysqlDBMS) ListSchemaObjects(dbName string) ([]models.SchemaObject, error) {
+	q, done := d.reader()
+	defer done()
+
+	var objects []models.SchemaObject
+	add := func(kind models.SchemaObjectType, query, column string) error {
+		names, err := queryNames(d, q, query, dbName)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			keyword := strings.ToUpper(string(kind))
+			create, err := showCreate(d, q, fmt.Sprintf("SHOW CREATE %s %s.%s", keyword, escapeColumnName(dbName), escapeColumnName(name)), column)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, models.SchemaObject{
+				Type:   kind,
+				Name:   name,
+				Drop:   fmt.Sprintf("DROP %s IF EXISTS %s;", keyword, escapeColumnName(name)),
+				Create: rewriteDefiner(create, d.definer) + ";",
+			})
+		}
+		return nil
+	}
+
+	if err := add(models.SchemaFunction, "SELECT ROUTINE_NAME FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'FUNCTION' ORDER BY ROUTINE_NAME", "Create Function"); err != nil {
+		return nil, err
+	}
+	if err := add(models.SchemaProcedure, "SELECT ROUTINE_NAME FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'PROCEDURE' ORDER BY ROUTINE_NAME", "Create Procedure"); err != nil {
+		return nil, err
+	}
+	routines := len(objects)
+	if err := add(models.SchemaView, "SELECT TABLE_NAME FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME", "Create View"); err != nil {
+		return nil, err
+	}
+	orderViews(objects[routines:])
+	if err := add(models.SchemaTrigger, "SELECT TRIGGER_NAME FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = ? ORDER BY EVENT_OBJECT_TABLE, ACTION_ORDER", "SQL Original Statement"); err != nil {
+		return nil, err
+	}
+	if err := add(models.SchemaEvent, "SELECT EVENT_NAME FROM information_schema.EVENTS WHERE EVENT_SCHEMA = ? ORDER BY EVENT_NAME", "Create Event"); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// orderViews orders views so that a view comes after the views it selects from.
+// Views in a cycle keep their order at the end.
+func orderViews(views []models.SchemaObject) {
+	ordered := make([]models.SchemaObject, 0, len(views))
+	pending := append([]models.SchemaObject(nil), views...)
+	for len(pending) > 0 {
+		var next []models.SchemaObject
+		for _, view := range pending {
+			if selectsFrom(view, pending) {
+				next = append(next, view)
+			} else {
+				ordered = append(ordered, view)
+			}
+		}
+		if len(next) == len(pending) {
+			ordered = append(ordered, next...)
+			break
+		}
+		pending = next
+	}
+	copy(views, ordered)
+}
+
+// selectsFrom reports whether view refers to one of the other views.
+func selectsFrom(view models.SchemaObject, views []models.SchemaObject) bool {
+	for _, other := range views {
+		if other.Name != view.Name && strings.Contains(view.Create, escapeColumnName(other.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteDefiner replaces the DEFINER clause of stmt with definer, given as
+// user@host or CURRENT_USER. An empty definer keeps stmt as it is.
+func rewriteDefiner(stmt, definer string) string {
+	if definer == "" {
+		return stmt
+	}
+	clause := "DEFINER=CURRENT_USER"
+	if !strings.EqualFold(definer, "CURRENT_USER") {
+		user, host := definer, "%"
+		if i := strings.LastIndex(definer, "@"); i >= 0 {
+			user, host = definer[:i], definer[i+1:]
+		}
+		clause = fmt.Sprintf("DEFINER=%s@%s", escapeColumnName(strings.Trim(user, "`'\"")), escapeColumnName(strings.Trim(host, "`'\"")))
+	}
+	return definerPattern.ReplaceAllLiteralString(stmt, clause)
+}
+
+// UserStatements returns the CREATE USER and GRANT statements of the accounts
+// selected by users, with their hosts mapped for the target.
+//
+// Accounts are read on a connection of their own that prints password hashes
+// as hex, so that binary hashes survive the dump.
+func (d *MysqlDBMS) UserStatements(dbName string, users models.UserMigration) ([]string, error) {
+	q, err := d.db.Conn(d.ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to open a connection")
+		return nil, err
+	}
+	defer q.Close()
+
+	// servers before MySQL 8.0.17 lack the variable and print hashes as text anyway
+	if _, err := q.ExecContext(d.ctx, "SET SESSION print_identified_with_as_hex = ON"); err != nil {
+		log.Debug().Err(err).Msg("print_identified_with_as_hex is not supported")
+	} else {
+		defer q.ExecContext(context.Background(), "SET SESSION print_identified_with_as_hex = DEFAULT")
+	}
+
+	accounts, err := d.listAccounts(q, dbName, users.Users)
+	if err != nil {
+		return nil, err
+	}
+
+	var stmts []string
+	for _, account := range accounts {
+		user, host := account[0], account[1]
+		target := host
+		if mapped, ok := users.HostMap[host]; ok {
+			target = mapped
+		} else if mapped, ok := users.HostMap["*"]; ok {
+			target = mapped
+		}
+
+		name := fmt.Sprintf("'%s'@'%s'", ReplaceEscapeString(user), ReplaceEscapeString(host))
+		create, err := showCreate(d, q, "SHOW CREATE USER "+name, "")
+		if err != nil {
+			return nil, err
+		}
+		grants, err := queryNames(d, q, "SHOW GRANTS FOR "+name)
+		if err != nil {
+			return nil, err
+		}
+
+		create = strings.Replace(create, "CREATE USER", "CREATE USER IF NOT EXISTS", 1)
+		for _, stmt := range append([]string{create}, grants...) {
+			for _, quote := range []string{"`", "'"} {
+				stmt = strings.ReplaceAll(stmt, quote+user+quote+"@"+quote+host+quote, quote+user+quote+"@"+quote+target+quote)
+			}
+			stmts = append(stmts, stmt+";")
+		}
+	}
+	return stmts, nil
+}
+
+// listAccounts returns the user and host of the accounts named users, or of
+// the accounts with grants on dbName when users is empty.
+func (d *MysqlDBMS) listAccounts(q querier, dbName string, users []string) ([][2]string, error) {
+	var rows *sql.Rows
+	var err error
+	if len(users) > 0 {
+		args := make([]any, len(users))
+		for i, user := range users {
+			args[i] = user
+		}
+		rows, err = q.QueryContext(d.ctx, "SELECT User, Host FROM mysql.user WHERE User IN (?"+strings.Repeat(", ?", len(users)-1)+") ORDER BY User, Host", args...)
+	} else {
+		rows, err = q.QueryContext(d.ctx,
+			"SELECT DISTINCT GRANTEE FROM information_schema.SCHEMA_PRIVILEGES WHERE TABLE_SCHEMA = ? "+
+				"UNION SELECT DISTINCT GRANTEE FROM information_schema.TABLE_PRIVILEGES WHERE TABLE_SCHEMA = ?", dbName, dbName)
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts [][2]string
+	for rows.Next() {
+		var account [2]string
+		if len(users) > 0 {
+			err = rows.Scan(&account[0], &account[1])
+		} else {
+			var grantee string
+			err = rows.Scan(&grantee)
+			account = splitGrantee(grantee)
+		}
+		if err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return nil, err
+		}
+		if !slices.Contains(systemUsers, account[0]) {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, rows.Err()
+}
+
+// splitGrantee splits a grantee like 'user'@'host' into its user and host.
+func splitGrantee(grantee string) [2]string {
+	user, host, _ := strings.Cut(grantee, "@")
+	return [2]string{strings.Trim(user, "'"), strings.Trim(host, "'")}
+}
+
+// showCreate returns the column of a SHOW CREATE statement, the first one when column is empty.
+func showCreate(d *MysqlDBMS, q querier, query, column string) (string, error) {
+	rows, err := q.QueryContext(d.ctx, query)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("%s returned nothing", query)
+	}
+	values := make([]sql.NullString, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return "", err
+	}
+	for i, name := range columns {
+		if column == "" || name == column {
+			return values[i].String, nil
+		}
+	}
+	return "", fmt.Errorf("%s has no column %q", query, column)
+}
+
+// queryNames returns the first column of the rows of query.
+func queryNames(d *MysqlDBMS, q querier, query string, args ...any) ([]string, error) {
+	rows, err := q.QueryContext(d.ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
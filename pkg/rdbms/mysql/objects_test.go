@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"testing"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+func TestRewriteDefiner(t *testing.T) {
+	view := "CREATE ALGORITHM=UNDEFINED DEFINER=`admin`@`10.0.%` SQL SECURITY DEFINER VIEW `v` AS select 1"
+	tests := []struct {
+		definer string
+		want    string
+	}{
+		{"", view},
+		{"app@%", "CREATE ALGORITHM=UNDEFINED DEFINER=`app`@`%` SQL SECURITY DEFINER VIEW `v` AS select 1"},
+		{"current_user", "CREATE ALGORITHM=UNDEFINED DEFINER=CURRENT_USER SQL SECURITY DEFINER VIEW `v` AS select 1"},
+	}
+	for _, tt := range tests {
+		if got := rewriteDefiner(view, tt.definer); got != tt.want {
+			t.Errorf("rewriteDefiner(%q) = %q, want %q", tt.definer, got, tt.want)
+		}
+	}
+
+	trigger := "CREATE DEFINER='root'@'localhost' TRIGGER `t` BEFORE INSERT ON `a` FOR EACH ROW SET NEW.x = 1"
+	want := "CREATE DEFINER=`app`@`%` TRIGGER `t` BEFORE INSERT ON `a` FOR EACH ROW SET NEW.x = 1"
+	if got := rewriteDefiner(trigger, "app"); got != want {
+		t.Errorf("rewriteDefiner = %q, want %q", got, want)
+	}
+}
+
+func TestOrderViews(t *testing.T) {
+	views := []models.SchemaObject{
+		{Name: "a", Create: "CREATE VIEW `a` AS select * from `b` join `c`"},
+		{Name: "b", Create: "CREATE VIEW `b` AS select * from `c`"},
+		{Name: "c", Create: "CREATE VIEW `c` AS select * from `t`"},
+	}
+	orderViews(views)
+	if got := views[0].Name + views[1].Name + views[2].Name; got != "cba" {
+		t.Errorf("view order = %s, want cba", got)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+// SampleCondition returns the condition that selects a random sample of the
+// rows of a table matching where: every row with the probability of a
+// percent sample, or rows rows drawn by primary key. A table without a
+// primary key gets each row with the share of its rows the sample is.
+func (d *MysqlDBMS) SampleCondition(dbName, tableName, where string, sample models.RowSample) (string, error) {
+	if sample.Percent > 0 {
+		return fmt.Sprintf("RAND() < %g", sample.Percent/100), nil
+	}
+
+	q, done := d.reader()
+	defer done()
+	keys, err := queryNames(d, q, "SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_KEY = 'PRI' ORDER BY ORDINAL_POSITION", dbName, tableName)
+	if err != nil {
+		return "", err
+	}
+	table := escapeColumnName(dbName) + "." + escapeColumnName(tableName)
+	filter := ""
+	if where != "" {
+		filter = " WHERE (" + where + ")"
+	}
+
+	if len(keys) == 0 {
+		var count int64
+		if err := q.QueryRowContext(d.ctx, "SELECT COUNT(*) FROM "+table+filter).Scan(&count); err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return "", err
+		}
+		if count <= sample.Rows {
+			return "", nil
+		}
+		log.Warn().Msgf("table %s has no primary key, about %d of its %d rows are sampled", tableName, sample.Rows, count)
+		return fmt.Sprintf("RAND() < %g", float64(sample.Rows)/float64(count)), nil
+	}
+
+	for i, key := range keys {
+		keys[i] = escapeColumnName(key)
+	}
+	columns := strings.Join(keys, ", ")
+	// the derived table lets the subquery of IN have a LIMIT
+	return fmt.Sprintf("(%s) IN (SELECT %s FROM (SELECT %s FROM %s%s ORDER BY RAND() LIMIT %d) AS sample)",
+		columns, columns, columns, table, filter, sample.Rows), nil
+}
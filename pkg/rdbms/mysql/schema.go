@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"database/sql"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Engine returns the engine of the database.
+func (d *MysqlDBMS) Engine() string {
+	return "mysql"
+}
+
+// TableSchemas describes the tables of dbName with their columns, indexes,
+// foreign keys and CHECK constraints, in the order of their names.
+func (d *MysqlDBMS) TableSchemas(dbName string) ([]models.TableSchema, error) {
+	q, done := d.reader()
+	defer done()
+
+	var tables []models.TableSchema
+	index := map[string]int{}
+	table := func(name string) *models.TableSchema {
+		return &tables[index[name]]
+	}
+
+	err := scanRows(d, q, "SELECT TABLE_NAME, COALESCE(AUTO_INCREMENT, 0) FROM information_schema.TABLES "+
+		"WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME", []any{dbName}, func(rows *sql.Rows) error {
+		var t models.TableSchema
+		if err := rows.Scan(&t.Name, &t.AutoIncrement); err != nil {
+			return err
+		}
+		index[t.Name] = len(tables)
+		tables = append(tables, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(d, q, "SELECT TABLE_NAME, COLUMN_NAME, DATA_TYPE, COLUMN_TYPE, IS_NULLABLE = 'YES', COLUMN_DEFAULT, EXTRA, COALESCE(COLLATION_NAME, '') "+
+		"FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME, ORDINAL_POSITION", []any{dbName}, func(rows *sql.Rows) error {
+		var name string
+		var c models.ColumnSchema
+		var def sql.NullString
+		if err := rows.Scan(&name, &c.Name, &c.DataType, &c.ColumnType, &c.Nullable, &def, &c.Extra, &c.Collation); err != nil {
+			return err
+		}
+		if def.Valid {
+			c.Default = &def.String
+		}
+		if _, ok := index[name]; ok {
+			table(name).Columns = append(table(name).Columns, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(d, q, "SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE = 0, INDEX_TYPE, COALESCE(COLUMN_NAME, ''), COALESCE(SUB_PART, 0) "+
+		"FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX", []any{dbName}, func(rows *sql.Rows) error {
+		var name string
+		var i models.IndexSchema
+		var col models.IndexColumn
+		if err := rows.Scan(&name, &i.Name, &i.Unique, &i.Type, &col.Name, &col.SubPart); err != nil {
+			return err
+		}
+		if _, ok := index[name]; !ok {
+			return nil
+		}
+		t := table(name)
+		if n := len(t.Indexes); n > 0 && t.Indexes[n-1].Name == i.Name {
+			t.Indexes[n-1].Columns = append(t.Indexes[n-1].Columns, col)
+			return nil
+		}
+		i.Primary = i.Name == "PRIMARY"
+		i.Columns = []models.IndexColumn{col}
+		t.Indexes = append(t.Indexes, i)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(d, q, "SELECT k.TABLE_NAME, k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME, r.DELETE_RULE, r.UPDATE_RULE "+
+		"FROM information_schema.KEY_COLUMN_USAGE k JOIN information_schema.REFERENTIAL_CONSTRAINTS r "+
+		"ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.TABLE_NAME = k.TABLE_NAME AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME "+
+		"WHERE k.TABLE_SCHEMA = ? AND k.REFERENCED_TABLE_NAME IS NOT NULL ORDER BY k.TABLE_NAME, k.CONSTRAINT_NAME, k.ORDINAL_POSITION", []any{dbName}, func(rows *sql.Rows) error {
+		var name, column, refDatabase, refColumn string
+		var fk models.ForeignKeySchema
+		if err := rows.Scan(&name, &fk.Name, &column, &refDatabase, &fk.RefTable, &refColumn, &fk.OnDelete, &fk.OnUpdate); err != nil {
+			return err
+		}
+		if _, ok := index[name]; !ok {
+			return nil
+		}
+		t := table(name)
+		if n := len(t.ForeignKeys); n > 0 && t.ForeignKeys[n-1].Name == fk.Name {
+			t.ForeignKeys[n-1].Columns = append(t.ForeignKeys[n-1].Columns, column)
+			t.ForeignKeys[n-1].RefColumns = append(t.ForeignKeys[n-1].RefColumns, refColumn)
+			return nil
+		}
+		if refDatabase != dbName {
+			fk.RefDatabase = refDatabase
+		}
+		fk.Columns = []string{column}
+		fk.RefColumns = []string{refColumn}
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(d, q, "SELECT TABLE_NAME, CONSTRAINT_NAME FROM information_schema.TABLE_CONSTRAINTS "+
+		"WHERE TABLE_SCHEMA = ? AND CONSTRAINT_TYPE = 'CHECK' ORDER BY TABLE_NAME, CONSTRAINT_NAME", []any{dbName}, func(rows *sql.Rows) error {
+		var name, check string
+		if err := rows.Scan(&name, &check); err != nil {
+			return err
+		}
+		if _, ok := index[name]; ok {
+			table(name).Checks = append(table(name).Checks, check)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// scanRows runs a query and passes its rows to scan one by one.
+func scanRows(d *MysqlDBMS, q querier, query string, args []any, scan func(rows *sql.Rows) error) error {
+	rows, err := q.QueryContext(d.ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Snapshot pins readers connections to one consistent view of the databases.
+// Every read goes through them until Release.
+//
+// The views are opened under FLUSH TABLES WITH READ LOCK, so that they are the
+// same on every connection and match the binary log position. With lock, the
+// lock is held until Release; otherwise it is released once the views are
+// open. When the server does not permit the lock and lock is not required, a
+// single connection is pinned and the position may be behind the view.
+func (d *MysqlDBMS) Snapshot(lock bool, readers int) (models.BinlogPosition, int, error) {
+	var pos models.BinlogPosition
+	if readers < 1 {
+		readers = 1
+	}
+
+	var conns []*sql.Conn
+	fail := func(err error) (models.BinlogPosition, int, error) {
+		for _, conn := range conns {
+			discard(conn)
+		}
+		return pos, 0, err
+	}
+
+	first, err := d.db.Conn(d.ctx)
+	if err != nil {
+		return fail(err)
+	}
+	conns = append(conns, first)
+
+	_, err = first.ExecContext(d.ctx, "FLUSH TABLES WITH READ LOCK")
+	locked := err == nil
+	if !locked {
+		if lock {
+			return fail(fmt.Errorf("FLUSH TABLES WITH READ LOCK: %w", err))
+		}
+		log.Warn().Err(err).Msg("cannot lock tables, exporting from a single snapshot whose binlog position may be behind")
+		readers = 1
+	}
+
+	for len(conns) < readers {
+		conn, err := d.db.Conn(d.ctx)
+		if err != nil {
+			return fail(err)
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		if _, err := conn.ExecContext(d.ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+			return fail(err)
+		}
+		if _, err := conn.ExecContext(d.ctx, "START TRANSACTION WITH CONSISTENT SNAPSHOT"); err != nil {
+			return fail(err)
+		}
+	}
+
+	pos, err = binlogPosition(d.ctx, first)
+	if err != nil {
+		log.Warn().Err(err).Msg("cannot read the binlog position")
+	}
+	pos.Exact = locked && err == nil
+
+	if locked && !lock {
+		if _, err := first.ExecContext(d.ctx, "UNLOCK TABLES"); err != nil {
+			return fail(err)
+		}
+	}
+
+	d.holdsLock = locked && lock
+	d.snapshot = conns
+	d.pinned = make(chan *sql.Conn, len(conns))
+	for _, conn := range conns {
+		d.pinned <- conn
+	}
+	return pos, len(conns), nil
+}
+
+// Release ends the snapshot and gives its connections back to the pool.
+func (d *MysqlDBMS) Release() error {
+	var errs []error
+	for i, conn := range d.snapshot {
+		// the connections go back to the pool, so this is not cancelled with the export
+		var err error
+		if i == 0 && d.holdsLock {
+			_, err = conn.ExecContext(context.Background(), "UNLOCK TABLES")
+		}
+		if err == nil {
+			_, err = conn.ExecContext(context.Background(), "COMMIT")
+		}
+		if err != nil {
+			errs = append(errs, err)
+			discard(conn)
+			continue
+		}
+		conn.Close()
+	}
+	d.snapshot = nil
+	d.pinned = nil
+	d.holdsLock = false
+	return errors.Join(errs...)
+}
+
+// discard closes conn without giving it back to the pool, which ends its
+// transaction and releases its locks on the server.
+func discard(conn *sql.Conn) {
+	conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
+
+// binlogPosition reads the binary log position of the server, empty when the binary log is off.
+func binlogPosition(ctx context.Context, q querier) (models.BinlogPosition, error) {
+	var pos models.BinlogPosition
+	rows, err := q.QueryContext(ctx, "SHOW MASTER STATUS")
+	if err != nil {
+		// renamed in MySQL 8.4
+		var newErr error
+		if rows, newErr = q.QueryContext(ctx, "SHOW BINARY LOG STATUS"); newErr != nil {
+			return pos, err
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return pos, err
+	}
+	if !rows.Next() {
+		return pos, rows.Err()
+	}
+	values := make([]sql.NullString, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return pos, err
+	}
+
+	for i, column := range columns {
+		switch column {
+		case "File":
+			pos.File = values[i].String
+		case "Position":
+			pos.Position, _ = strconv.ParseInt(values[i].String, 10, 64)
+		case "Executed_Gtid_Set":
+			// the sets of several sources are separated by newlines
+			pos.GTIDSet = strings.ReplaceAll(values[i].String, "\n", "")
+		}
+	}
+	return pos, nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+// columnKind is how the values of a column are read from the source and written to the target.
+type columnKind int
+
+const (
+	// kindText values are read as they are and written as quoted strings
+	kindText columnKind = iota
+	// kindBinary values are read as hex and written as hex literals
+	kindBinary
+	// kindBit values are read as integers and written as bit literals
+	kindBit
+	// kindGeometry values are read as SRID:WKB hex and written with ST_GeomFromWKB
+	kindGeometry
+)
+
+// stringEscaper escapes a value for a quoted string literal, as mysqldump does.
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\x00", `\0`, "\n", `\n`, "\r", `\r`, "\x1a", `\Z`)
+
+// kindOf returns the kind of a column from its information_schema DATA_TYPE.
+func kindOf(dataType string) columnKind {
+	switch strings.ToLower(dataType) {
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return kindBinary
+	case "bit":
+		return kindBit
+	case "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection":
+		return kindGeometry
+	}
+	return kindText
+}
+
+// selectExpr returns the expression that reads a column in the form literal and LoadRows expect.
+func selectExpr(column models.Column) string {
+	name := escapeColumnName(column.Name)
+	switch kindOf(column.Type) {
+	case kindBinary:
+		return fmt.Sprintf("HEX(%s)", name)
+	case kindBit:
+		return fmt.Sprintf("CAST(%s AS UNSIGNED)", name)
+	case kindGeometry:
+		return fmt.Sprintf("CONCAT(ST_SRID(%s), ':', HEX(ST_AsBinary(%s)))", name, name)
+	}
+	return name
+}
+
+// literal returns the SQL literal of a value read with selectExpr.
+func literal(column models.Column, val sql.NullString) string {
+	if !val.Valid {
+		return "NULL"
+	}
+	switch kindOf(column.Type) {
+	case kindBinary:
+		return "X'" + val.String + "'"
+	case kindBit:
+		if n, err := strconv.ParseUint(val.String, 10, 64); err == nil {
+			return fmt.Sprintf("b'%b'", n)
+		}
+	case kindGeometry:
+		if srid, wkb, ok := strings.Cut(val.String, ":"); ok {
+			return fmt.Sprintf("ST_GeomFromWKB(X'%s', %s)", wkb, srid)
+		}
+	}
+	return quoteString(val.String)
+}
+
+// quoteString returns s as a quoted string literal.
+func quoteString(s string) string {
+	return "'" + stringEscaper.Replace(s) + "'"
+}
+
+// loadTargets returns the column list and SET clause of a LOAD DATA statement
+// for values read with selectExpr. Values that are not text are loaded into
+// user variables and converted by the SET clause.
+func loadTargets(columns []models.Column) (string, string) {
+	targets := make([]string, len(columns))
+	var sets []string
+	for i, column := range columns {
+		name := escapeColumnName(column.Name)
+		variable := fmt.Sprintf("@v%d", i)
+		switch kindOf(column.Type) {
+		case kindBinary:
+			sets = append(sets, fmt.Sprintf("%s = UNHEX(%s)", name, variable))
+		case kindBit:
+			sets = append(sets, fmt.Sprintf("%s = CAST(%s AS UNSIGNED)", name, variable))
+		case kindGeometry:
+			sets = append(sets, fmt.Sprintf("%s = ST_GeomFromWKB(UNHEX(SUBSTRING_INDEX(%s, ':', -1)), CAST(SUBSTRING_INDEX(%s, ':', 1) AS UNSIGNED))", name, variable, variable))
+		default:
+			targets[i] = name
+			continue
+		}
+		targets[i] = variable
+	}
+	if len(sets) == 0 {
+		return strings.Join(targets, ", "), ""
+	}
+	return strings.Join(targets, ", "), " SET " + strings.Join(sets, ", ")
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+func TestLiteral(t *testing.T) {
+	tests := []struct {
+		dataType string
+		val      sql.NullString
+		want     string
+	}{
+		{"varchar", sql.NullString{}, "NULL"},
+		{"varchar", sql.NullString{String: "it's a \\ path\n\r\x00\x1a", Valid: true}, `'it\'s a \\ path\n\r\0\Z'`},
+		{"json", sql.NullString{String: `{"a": "x\"y"}`, Valid: true}, `'{"a": "x\\"y"}'`},
+		{"blob", sql.NullString{String: "00FF5C27", Valid: true}, "X'00FF5C27'"},
+		{"varbinary", sql.NullString{String: "", Valid: true}, "X''"},
+		{"bit", sql.NullString{String: "5", Valid: true}, "b'101'"},
+		{"bit", sql.NullString{String: "0", Valid: true}, "b'0'"},
+		{"point", sql.NullString{String: "4326:0101", Valid: true}, "ST_GeomFromWKB(X'0101', 4326)"},
+		{"int", sql.NullString{String: "-12", Valid: true}, "'-12'"},
+	}
+	for _, tt := range tests {
+		if got := literal(models.Column{Name: "c", Type: tt.dataType}, tt.val); got != tt.want {
+			t.Errorf("literal(%s, %q) = %s, want %s", tt.dataType, tt.val.String, got, tt.want)
+		}
+	}
+}
+
+func TestLoadTargets(t *testing.T) {
+	columns := []models.Column{{Name: "id", Type: "int"}, {Name: "data", Type: "blob"}, {Name: "flags", Type: "bit"}, {Name: "shape", Type: "geometry"}}
+	targets, sets := loadTargets(columns)
+	if want := "`id`, @v1, @v2, @v3"; targets != want {
+		t.Errorf("targets = %s, want %s", targets, want)
+	}
+	want := " SET `data` = UNHEX(@v1), `flags` = CAST(@v2 AS UNSIGNED), " +
+		"`shape` = ST_GeomFromWKB(UNHEX(SUBSTRING_INDEX(@v3, ':', -1)), CAST(SUBSTRING_INDEX(@v3, ':', 1) AS UNSIGNED))"
+	if sets != want {
+		t.Errorf("sets = %s, want %s", sets, want)
+	}
+
+	if _, sets := loadTargets(columns[:1]); sets != "" {
+		t.Errorf("sets of text columns = %q, want none", sets)
+	}
+}
+
+// TestRoundTrip copies a table with a column of every MySQL type into another
+// database, with INSERTs and with LOAD DATA, and compares the rows. It runs
+// against the server of MC_DATA_MANAGER_TEST_MYSQL_DSN, like user:password@tcp(host:3306)/
+func TestRoundTrip(t *testing.T) {
+	dsn := os.Getenv("MC_DATA_MANAGER_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("MC_DATA_MANAGER_TEST_MYSQL_DSN is not set")
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	d := New(models.Provider("mysql"), db)
+
+	exec := func(t *testing.T, query string) {
+		t.Helper()
+		if err := d.Exec(query); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+	}
+	for _, name := range []string{"mcdm_types_src", "mcdm_types_dst"} {
+		exec(t, "DROP DATABASE IF EXISTS "+name)
+		exec(t, "CREATE DATABASE "+name)
+		defer d.Exec("DROP DATABASE IF EXISTS " + name)
+	}
+
+	exec(t, "CREATE TABLE mcdm_types_src.types ("+
+		"id INT PRIMARY KEY, "+
+		"c_tinyint TINYINT, c_smallint SMALLINT UNSIGNED, c_mediumint MEDIUMINT, c_bigint BIGINT UNSIGNED, "+
+		"c_decimal DECIMAL(30,10), c_float FLOAT, c_double DOUBLE, c_bit BIT(13), c_bool BOOLEAN, "+
+		"c_date DATE, c_datetime DATETIME(6), c_timestamp TIMESTAMP(3) NULL, c_time TIME(2), c_year YEAR, "+
+		"c_char CHAR(10), c_varchar VARCHAR(100), c_text TEXT, c_latin1 VARCHAR(20) CHARACTER SET latin1, "+
+		"c_binary BINARY(4), c_varbinary VARBINARY(20), c_blob BLOB, c_longblob LONGBLOB, "+
+		"c_enum ENUM('a','b'), c_set SET('x','y','z'), c_json JSON, "+
+		"c_geometry GEOMETRY, c_point POINT, c_polygon POLYGON, c_collection GEOMETRYCOLLECTION)")
+	exec(t, "INSERT INTO mcdm_types_src.types VALUES ("+
+		"1, -128, 65535, -8388608, 18446744073709551615, "+
+		"-12345678901234567890.0123456789, 1.1, 2.2250738585072014e-308, b'1010101010101', TRUE, "+
+		"'2024-02-29', '9999-12-31 23:59:59.999999', '2038-01-19 03:14:07.999', '-838:59:59.00', 2155, "+
+		`'pad', 'it''s a \\ path\n\r\0\Z\t', '한글 😀', 'café', `+
+		"X'00FF0A27', X'', X'00FF5C27220A0D1A', REPEAT(X'00FF', 40000), "+
+		`'b', 'x,z', '{"a": "x\\"y", "b": [1, 2.5, null], "c": "\\u0000"}', `+
+		"ST_GeomFromText('LINESTRING(0 0, 1 1.5)'), ST_GeomFromText('POINT(1 2)', 4326), "+
+		"ST_GeomFromText('POLYGON((0 0, 4 0, 4 4, 0 4, 0 0), (1 1, 2 1, 2 2, 1 1))'), "+
+		"ST_GeomFromText('GEOMETRYCOLLECTION(POINT(1 1), LINESTRING(0 0, 2 2))'))")
+	exec(t, "INSERT INTO mcdm_types_src.types (id) VALUES (2)")
+
+	columns, err := d.ListColumn("mcdm_types_src", "types")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := tableRows(t, db, "mcdm_types_src")
+
+	copyRows := map[string]func(exec func(query string) error) error{
+		"insert": func(exec func(query string) error) error {
+			return d.GetInsert("mcdm_types_src", "types", exec)
+		},
+		"load data": func(exec func(query string) error) error {
+			return d.LoadRows(exec, "types", columns, func(emit func(row []sql.NullString) error) error {
+				return d.GetRows("mcdm_types_src", "types", columns, "", emit)
+			})
+		},
+	}
+	for name, copyRow := range copyRows {
+		t.Run(name, func(t *testing.T) {
+			if name == "load data" {
+				if enabled, err := d.LocalInfile(); err != nil || !enabled {
+					t.Skip("local_infile is disabled")
+				}
+			}
+			exec(t, "DROP TABLE IF EXISTS mcdm_types_dst.types")
+			exec(t, "CREATE TABLE mcdm_types_dst.types LIKE mcdm_types_src.types")
+			err := d.Session(func(exec func(query string) error) error {
+				if err := exec("USE mcdm_types_dst"); err != nil {
+					return err
+				}
+				return copyRow(exec)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := tableRows(t, db, "mcdm_types_dst")
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("rows differ after copy:\n got %v\nwant %v", got, want)
+			}
+		})
+	}
+}
+
+// tableRows returns the raw values of the rows of the types table of a database.
+func tableRows(t *testing.T, db *sql.DB, dbName string) [][]sql.NullString {
+	t.Helper()
+	rows, err := db.Query(fmt.Sprintf("SELECT * FROM %s.types ORDER BY id", dbName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out [][]sql.NullString
+	for rows.Next() {
+		row := make([]sql.NullString, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range row {
+			ptrs[i] = &row[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package postgres
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/rs/zerolog/log"
+)
+
+// copyEscaper escapes a value for the text format of COPY.
+var copyEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// ListColumn returns the columns of a table in order, with their types.
+// Generated columns are left out, as their values cannot be copied.
+func (d *PostgresDBMS) ListColumn(dbName, tableName string) ([]models.Column, error) {
+	db, err := d.pool(dbName)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(d.ctx, "SELECT attname, format_type(atttypid, atttypmod) FROM pg_attribute "+
+		"WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped AND attgenerated = '' ORDER BY attnum", quoteTable(tableName))
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []models.Column
+	for rows.Next() {
+		var column models.Column
+		if err := rows.Scan(&column.Name, &column.Type); err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// GetRows passes the rows of a table matching where, or all rows when it is
+// empty, to emit as they are read with COPY TO STDOUT. Values are in the text
+// format of PostgreSQL. The row is reused for the next call of emit.
+func (d *PostgresDBMS) GetRows(dbName, tableName string, columns []models.Column, where string, emit func(row []sql.NullString) error) error {
+	db, err := d.pool(dbName)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("COPY (SELECT %s FROM %s", quoteColumns(columns), quoteTable(tableName))
+	if where != "" {
+		query += " WHERE " + where
+	}
+	query += ") TO STDOUT"
+
+	conn, err := db.Conn(d.ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to open a connection")
+		return err
+	}
+	defer conn.Close()
+
+	row := make([]sql.NullString, len(columns))
+	w := &lineWriter{emit: func(line string) error {
+		if err := decodeCopyRow(line, row); err != nil {
+			return err
+		}
+		return emit(row)
+	}}
+	err = conn.Raw(func(driverConn any) error {
+		pgConn := driverConn.(*stdlib.Conn).Conn().PgConn()
+		_, err := pgConn.CopyTo(d.ctx, w, query)
+		return err
+	})
+	if err != nil {
+		// a COPY stopped halfway leaves the connection unusable
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		discard(conn)
+		return err
+	}
+	return nil
+}
+
+// GetInsert passes COPY ... FROM stdin blocks with the rows of the table to
+// emit, each at most the max packet size unless a single row is larger.
+func (d *PostgresDBMS) GetInsert(dbName, tableName string, emit func(insertSql string) error) error {
+	return d.GetInsertWhere(dbName, tableName, "", emit)
+}
+
+// GetInsertWhere is GetInsert for the rows matching where.
+func (d *PostgresDBMS) GetInsertWhere(dbName, tableName, where string, emit func(insertSql string) error) error {
+	columns, err := d.ListColumn(dbName, tableName)
+	if err != nil {
+		return err
+	}
+	block := &copyBlock{
+		prefix: fmt.Sprintf("COPY %s (%s) FROM stdin;\n", quoteTable(tableName), quoteColumns(columns)),
+		size:   d.maxPacketSize,
+		emit:   emit,
+	}
+	err = d.GetRows(dbName, tableName, columns, where, func(row []sql.NullString) error {
+		return block.add(encodeCopyRow(row))
+	})
+	if err != nil {
+		return err
+	}
+	return block.flush()
+}
+
+// copyBlock collects rows into COPY ... FROM stdin blocks of at most size
+// bytes, unless a single row is larger, and passes them to emit.
+type copyBlock struct {
+	prefix string
+	size   int
+	emit   func(block string) error
+	b      strings.Builder
+}
+
+// add appends a row in the text format of COPY, emitting the block first when the row does not fit.
+func (c *copyBlock) add(line string) error {
+	if c.b.Len() > 0 && c.b.Len()+len(line)+2 > c.size {
+		if err := c.flush(); err != nil {
+			return err
+		}
+	}
+	if c.b.Len() == 0 {
+		c.b.WriteString(c.prefix)
+	}
+	c.b.WriteString(line)
+	return nil
+}
+
+// flush emits the rows added since the last block.
+func (c *copyBlock) flush() error {
+	if c.b.Len() == 0 {
+		return nil
+	}
+	c.b.WriteString(`\.`)
+	err := c.emit(c.b.String())
+	c.b.Reset()
+	return err
+}
+
+// quoteColumns returns the quoted names of columns separated by commas.
+func quoteColumns(columns []models.Column) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = quoteIdent(column.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// encodeCopyRow returns a row as a line of the text format of COPY, with NULL as \N.
+func encodeCopyRow(row []sql.NullString) string {
+	var line strings.Builder
+	for i, val := range row {
+		if i > 0 {
+			line.WriteString("\t")
+		}
+		if !val.Valid {
+			line.WriteString(`\N`)
+			continue
+		}
+		line.WriteString(copyEscaper.Replace(val.String))
+	}
+	line.WriteString("\n")
+	return line.String()
+}
+
+// decodeCopyRow reads a line of the text format of COPY, without its newline, into row.
+func decodeCopyRow(line string, row []sql.NullString) error {
+	fields := strings.Split(line, "\t")
+	if len(fields) != len(row) {
+		return fmt.Errorf("COPY row has %d columns, want %d", len(fields), len(row))
+	}
+	for i, field := range fields {
+		if field == `\N` {
+			row[i] = sql.NullString{}
+			continue
+		}
+		row[i] = sql.NullString{String: unescapeCopy(field), Valid: true}
+	}
+	return nil
+}
+
+// unescapeCopy decodes the backslash sequences of a value of the text format of COPY.
+func unescapeCopy(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		if c != '\\' || i+1 == len(field) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch c = field[i]; c {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case 'x':
+			n := 0
+			for n < 2 && i+1+n < len(field) && isHex(field[i+1+n]) {
+				n++
+			}
+			if n == 0 {
+				b.WriteByte('x')
+				continue
+			}
+			v, _ := strconv.ParseUint(field[i+1:i+1+n], 16, 8)
+			b.WriteByte(byte(v))
+			i += n
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			n := 1
+			for n < 3 && i+n < len(field) && '0' <= field[i+n] && field[i+n] <= '7' {
+				n++
+			}
+			v, _ := strconv.ParseUint(field[i:i+n], 8, 16)
+			b.WriteByte(byte(v))
+			i += n - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+// lineWriter passes the lines written to it to emit, without their newlines.
+type lineWriter struct {
+	buf  []byte
+	emit func(line string) error
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	start := 0
+	for {
+		i := bytes.IndexByte(w.buf[start:], '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.emit(string(w.buf[start : start+i])); err != nil {
+			return 0, err
+		}
+		start += i + 1
+	}
+	w.buf = append(w.buf[:0], w.buf[start:]...)
+	return len(p), nil
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+// sequenceDefault matches the sequences that column defaults take values from.
+var sequenceDefault = regexp.MustCompile(`nextval\('((?:[^']|'')+)'::regclass\)`)
+
+// ShowCreateDBSql returns the CREATE DATABASE statement of dbName with its encoding and locale.
+func (d *PostgresDBMS) ShowCreateDBSql(dbName string, dbCreateSql *string) error {
+	var encoding, collate, ctype string
+	err := d.db.QueryRowContext(d.ctx, "SELECT pg_encoding_to_char(encoding), datcollate, datctype FROM pg_database WHERE datname = $1", dbName).
+		Scan(&encoding, &collate, &ctype)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return err
+	}
+	*dbCreateSql = fmt.Sprintf("CREATE DATABASE %s WITH TEMPLATE = template0 ENCODING = %s LC_COLLATE = %s LC_CTYPE = %s;",
+		quoteIdent(dbName), quoteLiteral(encoding), quoteLiteral(collate), quoteLiteral(ctype))
+	return nil
+}
+
+// ShowCreateTableSql returns the statements that create a table: its schema
+// and the sequences of its column defaults first, then the table with its
+// constraints, the ownership of its sequences and its other indexes.
+func (d *PostgresDBMS) ShowCreateTableSql(dbName, tableName string, tableCreateSql *string) error {
+	db, err := d.pool(dbName)
+	if err != nil {
+		return err
+	}
+	schema, _ := splitTable(tableName)
+	table := quoteTable(tableName)
+
+	var stmts []string
+	if schema != "public" {
+		stmts = append(stmts, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", quoteIdent(schema)))
+	}
+
+	rows, err := db.QueryContext(d.ctx, "SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull, "+
+		"COALESCE(pg_get_expr(ad.adbin, ad.adrelid), ''), a.attidentity, a.attgenerated, COALESCE(co.collname, '') "+
+		"FROM pg_attribute a JOIN pg_type t ON t.oid = a.atttypid "+
+		"LEFT JOIN pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum "+
+		"LEFT JOIN pg_collation co ON co.oid = a.attcollation AND a.attcollation <> t.typcollation "+
+		"WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum", table)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return err
+	}
+	var defs, sequences []string
+	for rows.Next() {
+		var name, typ, def, identity, generated, collation string
+		var notNull bool
+		if err := rows.Scan(&name, &typ, &notNull, &def, &identity, &generated, &collation); err != nil {
+			rows.Close()
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return err
+		}
+		col := quoteIdent(name) + " " + typ
+		if collation != "" {
+			col += " COLLATE " + quoteIdent(collation)
+		}
+		switch {
+		case generated == "s":
+			col += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", def)
+		case identity == "a":
+			col += " GENERATED ALWAYS AS IDENTITY"
+		case identity == "d":
+			col += " GENERATED BY DEFAULT AS IDENTITY"
+		case def != "":
+			col += " DEFAULT " + def
+			for _, m := range sequenceDefault.FindAllStringSubmatch(def, -1) {
+				sequences = append(sequences, strings.ReplaceAll(m[1], "''", "'"))
+			}
+		}
+		if notNull {
+			col += " NOT NULL"
+		}
+		defs = append(defs, col)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var owned []string
+	for _, seq := range sequences {
+		create, owner, err := d.sequence(db, seq, table)
+		if err != nil {
+			return err
+		}
+		stmts = append(stmts, create)
+		if owner != "" {
+			owned = append(owned, fmt.Sprintf("ALTER SEQUENCE %s OWNED BY %s.%s;", seq, table, quoteIdent(owner)))
+		}
+	}
+
+	constraints, err := queryStrings(d, db, "SELECT format('CONSTRAINT %I %s', conname, pg_get_constraintdef(oid)) FROM pg_constraint "+
+		"WHERE conrelid = $1::regclass AND contype IN ('p', 'u', 'c', 'f', 'x') ORDER BY contype <> 'p', conname", table)
+	if err != nil {
+		return err
+	}
+	defs = append(defs, constraints...)
+	stmts = append(stmts, fmt.Sprintf("CREATE TABLE %s (\n  %s\n);", table, strings.Join(defs, ",\n  ")))
+	stmts = append(stmts, owned...)
+
+	indexes, err := queryStrings(d, db, "SELECT pg_get_indexdef(i.indexrelid) || ';' FROM pg_index i "+
+		"WHERE i.indrelid = $1::regclass AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = i.indexrelid AND c.conrelid = i.indrelid) "+
+		"ORDER BY i.indexrelid", table)
+	if err != nil {
+		return err
+	}
+	stmts = append(stmts, indexes...)
+
+	*tableCreateSql = strings.Join(stmts, "\n")
+	return nil
+}
+
+// sequence returns the statement that creates a sequence as the default of a
+// column of table refers to it, and the column of table that owns it, if any.
+func (d *PostgresDBMS) sequence(db querier, seq, table string) (string, string, error) {
+	var typ, cycle string
+	var start, increment, minValue, maxValue, cache int64
+	var owner string
+	err := db.QueryRowContext(d.ctx, "SELECT format_type(s.seqtypid, NULL), s.seqstart, s.seqincrement, s.seqmin, s.seqmax, s.seqcache, "+
+		"CASE WHEN s.seqcycle THEN 'CYCLE' ELSE 'NO CYCLE' END, "+
+		"COALESCE((SELECT a.attname FROM pg_depend dep JOIN pg_attribute a ON a.attrelid = dep.refobjid AND a.attnum = dep.refobjsubid "+
+		"WHERE dep.objid = s.seqrelid AND dep.deptype = 'a' AND dep.refobjid = $2::regclass), '') "+
+		"FROM pg_sequence s WHERE s.seqrelid = $1::regclass", seq, table).
+		Scan(&typ, &start, &increment, &minValue, &maxValue, &cache, &cycle, &owner)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return "", "", err
+	}
+	return fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s AS %s INCREMENT BY %d MINVALUE %d MAXVALUE %d START WITH %d CACHE %d %s;",
+		seq, typ, increment, minValue, maxValue, start, cache, cycle), owner, nil
+}
+
+// TableDependencies returns, for each table of dbName, the tables its foreign keys reference.
+func (d *PostgresDBMS) TableDependencies(dbName string) (map[string][]string, error) {
+	db, err := d.pool(dbName)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(d.ctx, "SELECT n.nspname, c.relname, rn.nspname, r.relname FROM pg_constraint k "+
+		"JOIN pg_class c ON c.oid = k.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace "+
+		"JOIN pg_class r ON r.oid = k.confrelid JOIN pg_namespace rn ON rn.oid = r.relnamespace "+
+		"WHERE k.contype = 'f' AND k.conrelid <> k.confrelid AND "+userSchemas)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	deps := map[string][]string{}
+	for rows.Next() {
+		var schema, table, refSchema, refTable string
+		if err := rows.Scan(&schema, &table, &refSchema, &refTable); err != nil {
+			return nil, err
+		}
+		name := tableName(schema, table)
+		deps[name] = append(deps[name], tableName(refSchema, refTable))
+	}
+	return deps, rows.Err()
+}
+
+// ListSchemaObjects returns the functions, procedures, views, materialized
+// views and triggers of dbName in the order they can be created, followed by
+// the values of its sequences. Objects of extensions are left out.
+func (d *PostgresDBMS) ListSchemaObjects(dbName string) ([]models.SchemaObject, error) {
+	db, err := d.pool(dbName)
+	if err != nil {
+		return nil, err
+	}
+	queries := []struct {
+		query string
+		build func(fields []string) models.SchemaObject
+	}{
+		{"SELECT n.nspname, p.proname, p.prokind::text, pg_get_functiondef(p.oid) FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace " +
+			"WHERE p.prokind IN ('f', 'p') AND " + userSchemas + " AND NOT EXISTS (SELECT 1 FROM pg_depend dep WHERE dep.objid = p.oid AND dep.deptype = 'e') " +
+			"ORDER BY p.oid",
+			func(f []string) models.SchemaObject {
+				typ := models.SchemaFunction
+				if f[2] == "p" {
+					typ = models.SchemaProcedure
+				}
+				// CREATE OR REPLACE needs no drop
+				return models.SchemaObject{Type: typ, Name: tableName(f[0], f[1]), Create: f[3] + ";"}
+			}},
+		{"SELECT n.nspname, c.relname, c.relkind::text, pg_get_viewdef(c.oid) FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+			"WHERE c.relkind IN ('v', 'm') AND " + userSchemas + " AND NOT EXISTS (SELECT 1 FROM pg_depend dep WHERE dep.objid = c.oid AND dep.deptype = 'e') " +
+			"ORDER BY c.oid",
+			func(f []string) models.SchemaObject {
+				name := quoteIdent(f[0]) + "." + quoteIdent(f[1])
+				def := strings.TrimSuffix(strings.TrimSpace(f[3]), ";")
+				if f[2] == "m" {
+					return models.SchemaObject{Type: models.SchemaView, Name: tableName(f[0], f[1]),
+						Drop:   fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s CASCADE;", name),
+						Create: fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS\n%s\nWITH DATA;", name, def)}
+				}
+				return models.SchemaObject{Type: models.SchemaView, Name: tableName(f[0], f[1]),
+					Drop:   fmt.Sprintf("DROP VIEW IF EXISTS %s CASCADE;", name),
+					Create: fmt.Sprintf("CREATE VIEW %s AS\n%s;", name, def)}
+			}},
+		{"SELECT n.nspname, c.relname, t.tgname, pg_get_triggerdef(t.oid) FROM pg_trigger t " +
+			"JOIN pg_class c ON c.oid = t.tgrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+			"WHERE NOT t.tgisinternal AND " + userSchemas + " ORDER BY t.oid",
+			func(f []string) models.SchemaObject {
+				return models.SchemaObject{Type: models.SchemaTrigger, Name: f[2],
+					Drop:   fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s.%s;", quoteIdent(f[2]), quoteIdent(f[0]), quoteIdent(f[1])),
+					Create: f[3] + ";"}
+			}},
+		{"SELECT schemaname, sequencename, last_value::text, '' FROM pg_sequences n " +
+			"WHERE last_value IS NOT NULL AND " + strings.ReplaceAll(userSchemas, "n.nspname", "n.schemaname") + " ORDER BY 1, 2",
+			func(f []string) models.SchemaObject {
+				name := quoteIdent(f[0]) + "." + quoteIdent(f[1])
+				return models.SchemaObject{Type: models.SchemaSequence, Name: tableName(f[0], f[1]),
+					Create: fmt.Sprintf("SELECT setval(%s, %s, true);", quoteLiteral(name), f[2])}
+			}},
+	}
+
+	var objects []models.SchemaObject
+	for _, q := range queries {
+		rows, err := db.QueryContext(d.ctx, q.query)
+		if err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return nil, err
+		}
+		for rows.Next() {
+			fields := make([]string, 4)
+			if err := rows.Scan(&fields[0], &fields[1], &fields[2], &fields[3]); err != nil {
+				rows.Close()
+				log.Error().Err(err).Msgf("SQL query executed failed")
+				return nil, err
+			}
+			objects = append(objects, q.build(fields))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return objects, nil
+}
+
+// querier is a pool or a connection.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// queryStrings returns the first column of the rows of a query.
+func queryStrings(d *PostgresDBMS, db querier, query string, args ...any) ([]string, error) {
+	rows, err := db.QueryContext(d.ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/rdbms/mysql/diagnostics"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultMaxPacketSize is the default size limit in bytes of the COPY blocks of an export.
+const DefaultMaxPacketSize = 1 << 20
+
+// userSchemas is the condition that leaves out the system schemas of the namespace n.
+const userSchemas = "n.nspname NOT IN ('pg_catalog', 'information_schema') AND n.nspname NOT LIKE 'pg\\_%'"
+
+// PostgresDBMS is a PostgreSQL server. A connection belongs to one database
+// of the server, so the server keeps a pool for each database it reads or writes.
+type PostgresDBMS struct {
+	provider       models.Provider
+	targetProvider models.Provider
+	ctx            context.Context
+
+	// db is the pool of dbName, the database the server was opened on
+	db     *sql.DB
+	dbName string
+	// open opens a pool on another database of the server
+	open  func(dbName string) (*sql.DB, error)
+	mu    sync.Mutex
+	pools map[string]*sql.DB
+
+	maxPacketSize int
+	disableChecks bool
+}
+
+// New returns the server of sqlDB, a pool on dbName. open opens pools on the
+// other databases of the server.
+func New(provider models.Provider, sqlDB *sql.DB, dbName string, open func(dbName string) (*sql.DB, error)) *PostgresDBMS {
+	return &PostgresDBMS{
+		provider:      provider,
+		db:            sqlDB,
+		dbName:        dbName,
+		open:          open,
+		pools:         map[string]*sql.DB{},
+		ctx:           context.TODO(),
+		maxPacketSize: DefaultMaxPacketSize,
+	}
+}
+
+func (d *PostgresDBMS) GetProvdier() models.Provider {
+	return d.provider
+}
+
+func (d *PostgresDBMS) SetProvdier(provider models.Provider) {
+	d.provider = provider
+}
+
+func (d *PostgresDBMS) GetTargetProvdier() models.Provider {
+	return d.targetProvider
+}
+
+func (d *PostgresDBMS) SetTargetProvdier(provider models.Provider) {
+	d.targetProvider = provider
+}
+
+// SetContext sets the context used by the queries of the database.
+func (d *PostgresDBMS) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// SetMaxPacketSize sets the size limit in bytes of the COPY blocks of an export.
+func (d *PostgresDBMS) SetMaxPacketSize(size int) {
+	if size > 0 {
+		d.maxPacketSize = size
+	}
+}
+
+// SetDisableChecks turns off triggers and foreign key checks in the sessions
+// of the database, by making them replication sessions.
+func (d *PostgresDBMS) SetDisableChecks(disable bool) {
+	d.disableChecks = disable
+}
+
+// UseStatement returns the psql meta-command that connects to dbName.
+func (d *PostgresDBMS) UseStatement(dbName string) string {
+	return `\connect ` + quoteIdent(dbName)
+}
+
+// DropTableStatement returns the statement that drops a table and what depends on it.
+func (d *PostgresDBMS) DropTableStatement(tableName string) string {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE;", quoteTable(tableName))
+}
+
+// pool returns the pool of a database of the server.
+func (d *PostgresDBMS) pool(dbName string) (*sql.DB, error) {
+	if dbName == "" || dbName == d.dbName {
+		return d.db, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if db, ok := d.pools[dbName]; ok {
+		return db, nil
+	}
+	db, err := d.open(dbName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to open database %s", dbName)
+		return nil, err
+	}
+	d.pools[dbName] = db
+	return db, nil
+}
+
+// closePool closes the pool of a database, so that the database can be dropped.
+func (d *PostgresDBMS) closePool(dbName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if db, ok := d.pools[dbName]; ok {
+		db.Close()
+		delete(d.pools, dbName)
+	}
+}
+
+// Functions that execute EXEC commands in sql
+func (d *PostgresDBMS) Exec(query string) error {
+	return d.Session(func(exec func(query string) error) error {
+		return exec(query)
+	})
+}
+
+// Session runs fn with an exec function bound to a single connection. exec
+// runs scripts as psql does for what a dump holds: \connect moves the session
+// to another database and COPY ... FROM stdin reads the rows up to \.
+func (d *PostgresDBMS) Session(fn func(exec func(query string) error) error) error {
+	s := &session{d: d}
+	defer s.release()
+	if err := s.connect(d.dbName); err != nil {
+		return err
+	}
+	return fn(s.exec)
+}
+
+// session is the connection of a Session, which \connect replaces.
+type session struct {
+	d    *PostgresDBMS
+	conn *sql.Conn
+}
+
+// connect replaces the connection of the session with one to dbName.
+func (s *session) connect(dbName string) error {
+	s.release()
+	db, err := s.d.pool(dbName)
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(s.d.ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to open a connection")
+		return err
+	}
+	if s.d.disableChecks {
+		if _, err := conn.ExecContext(s.d.ctx, "SET session_replication_role = replica"); err != nil {
+			log.Error().Err(err).Msg("Failed to disable checks")
+			conn.Close()
+			return err
+		}
+	}
+	s.conn = conn
+	return nil
+}
+
+// release gives the connection of the session back to its pool.
+func (s *session) release() {
+	if s.conn == nil {
+		return
+	}
+	if s.d.disableChecks {
+		// the connection goes back to the pool, so it is discarded when the checks stay off
+		if _, err := s.conn.ExecContext(context.Background(), "RESET session_replication_role"); err != nil {
+			log.Error().Err(err).Msg("Failed to enable checks")
+			discard(s.conn)
+			s.conn = nil
+			return
+		}
+	}
+	s.conn.Close()
+	s.conn = nil
+}
+
+// exec runs a script on the session.
+func (s *session) exec(script string) error {
+	return splitScript(script, func(part scriptPart) error {
+		switch part.kind {
+		case partConnect:
+			return s.connect(part.text)
+		case partCopy:
+			return s.copyFrom(part.text, part.data)
+		}
+		if _, err := s.conn.ExecContext(s.d.ctx, part.text); err != nil {
+			// CREATE DATABASE has no IF NOT EXISTS, a database that exists is kept
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "42P04" {
+				log.Warn().Msg(pgErr.Message)
+				return nil
+			}
+			log.Error().Err(err).Msg("Failed to execute SQL query")
+			return err
+		}
+		log.Debug().Msg("SQL query executed successfully")
+		return nil
+	})
+}
+
+// copyFrom runs a COPY ... FROM STDIN statement with its rows in the text format.
+func (s *session) copyFrom(stmt, data string) error {
+	return s.conn.Raw(func(driverConn any) error {
+		pgConn := driverConn.(*stdlib.Conn).Conn().PgConn()
+		if _, err := pgConn.CopyFrom(s.d.ctx, strings.NewReader(data), stmt); err != nil {
+			log.Error().Err(err).Msg("Failed to copy rows")
+			return err
+		}
+		return nil
+	})
+}
+
+// discard closes a connection without giving it back to its pool.
+func discard(conn *sql.Conn) {
+	conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
+
+// Delete database
+func (d *PostgresDBMS) DeleteDB(dbName string) error {
+	d.closePool(dbName)
+	_, err := d.db.ExecContext(d.ctx, "DROP DATABASE IF EXISTS "+quoteIdent(dbName))
+	return err
+}
+
+// Get database list, without the templates and the maintenance databases of managed services
+func (d *PostgresDBMS) ListDB(dst *[]string) error {
+	rows, err := d.db.QueryContext(d.ctx, "SELECT datname FROM pg_database WHERE NOT datistemplate AND datallowconn "+
+		"AND datname NOT IN ('postgres', 'rdsadmin', 'azure_maintenance', 'azure_sys', 'cloudsqladmin') ORDER BY datname")
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dbName string
+		if err := rows.Scan(&dbName); err != nil {
+			log.Error().Err(err).Msgf("SQL query executed failed")
+			return err
+		}
+		*dst = append(*dst, dbName)
+	}
+	return rows.Err()
+}
+
+// DatabaseSize returns the size in bytes of dbName.
+func (d *PostgresDBMS) DatabaseSize(dbName string) (int64, error) {
+	var size int64
+	if err := d.db.QueryRowContext(d.ctx, "SELECT pg_database_size($1)", dbName).Scan(&size); err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return 0, err
+	}
+	return size, nil
+}
+
+// Get table list of the user schemas, without views and partitions. Tables
+// outside the public schema are named schema.table.
+func (d *PostgresDBMS) ListTable(dbName string, dst *[]string) error {
+	db, err := d.pool(dbName)
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(d.ctx, "SELECT n.nspname, c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace "+
+		"WHERE c.relkind = 'r' AND NOT c.relispartition AND "+userSchemas+" ORDER BY n.nspname, c.relname")
+	if err != nil {
+		log.Error().Err(err).Msgf("SQL query executed failed")
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schema, table string
+		if err := rows.Scan(&schema, &table); err != nil {
+			return err
+		}
+		*dst = append(*dst, tableName(schema, table))
+	}
+	return rows.Err()
+}
+
+// Diagnose is not supported: the collector reads the MySQL status variables.
+func (d *PostgresDBMS) Diagnose(schema string, timeInput int64) (diagnostics.TimedResult, error) {
+	return diagnostics.TimedResult{}, errors.New("diagnostics are not supported for PostgreSQL")
+}
+
+// tableName returns the name ListTable gives a table of a schema.
+func tableName(schema, table string) string {
+	if schema == "public" {
+		return table
+	}
+	return schema + "." + table
+}
+
+// splitTable returns the schema and table of a name given by ListTable.
+func splitTable(name string) (string, string) {
+	if schema, table, ok := strings.Cut(name, "."); ok {
+		return schema, table
+	}
+	return "public", name
+}
+
+// quoteTable returns the quoted qualified name of a table given by ListTable.
+func quoteTable(name string) string {
+	schema, table := splitTable(name)
+	return quoteIdent(schema) + "." + quoteIdent(table)
+}
+
+// quoteIdent returns an identifier in double quotes.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral returns a string literal in single quotes.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+// SampleCondition returns the condition that selects a random sample of the
+// rows of a table matching where: every row with the probability of a
+// percent sample, or rows rows drawn by their physical location.
+func (d *PostgresDBMS) SampleCondition(dbName, tableName, where string, sample models.RowSample) (string, error) {
+	if sample.Percent > 0 {
+		return fmt.Sprintf("random() < %g", sample.Percent/100), nil
+	}
+	filter := ""
+	if where != "" {
+		filter = " WHERE (" + where + ")"
+	}
+	return fmt.Sprintf("ctid = ANY (ARRAY(SELECT ctid FROM %s%s ORDER BY random() LIMIT %d))", quoteTable(tableName), filter, sample.Rows), nil
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package postgres
+
+import (
+	"testing"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+func TestSampleCondition(t *testing.T) {
+	d := &PostgresDBMS{}
+	tests := []struct {
+		table, where string
+		sample       models.RowSample
+		want         string
+	}{
+		{"orders", "", models.RowSample{Percent: 5}, "random() < 0.05"},
+		{"sales.orders", "created_at > now() - interval '90 days'", models.RowSample{Rows: 100},
+			`ctid = ANY (ARRAY(SELECT ctid FROM "sales"."orders" WHERE (created_at > now() - interval '90 days') ORDER BY random() LIMIT 100))`},
+		{"orders", "status = 'new' OR status = 'paid'", models.RowSample{Rows: 10},
+			`ctid = ANY (ARRAY(SELECT ctid FROM "public"."orders" WHERE (status = 'new' OR status = 'paid') ORDER BY random() LIMIT 10))`},
+	}
+	for _, tt := range tests {
+		got, err := d.SampleCondition("shop", tt.table, tt.where, tt.sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("SampleCondition(%s, %+v) = %s, want %s", tt.table, tt.sample, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// partKind is what a part of a script is.
+type partKind int
+
+const (
+	// partSQL is a statement
+	partSQL partKind = iota
+	// partConnect is a \connect meta-command
+	partConnect
+	// partCopy is a COPY ... FROM stdin statement with its rows
+	partCopy
+)
+
+// scriptPart is a statement or meta-command of a script.
+type scriptPart struct {
+	kind partKind
+	// text is the statement, the database of \connect or the COPY statement without its semicolon
+	text string
+	// data is the rows of a COPY in the text format
+	data string
+}
+
+var (
+	copyFromStdin = regexp.MustCompile(`(?is)^COPY\s.*\sFROM\s+stdin\b[^;]*;$`)
+	dollarTag     = regexp.MustCompile(`^\$(?:[A-Za-z_\x80-\xff][A-Za-z0-9_\x80-\xff]*)?\$`)
+)
+
+// splitScript passes the statements and meta-commands of a script to run in
+// order. Statements end with a semicolon outside of literals, quoted
+// identifiers, comments and dollar-quoted bodies; meta-commands take the line
+// they start.
+func splitScript(script string, run func(part scriptPart) error) error {
+	start, sqlStart := 0, -1
+	for i := 0; i < len(script); {
+		c := script[i]
+		if sqlStart < 0 && c == '\\' && (i == 0 || script[i-1] == '\n') {
+			end := lineEnd(script, i)
+			dbName, err := connectTarget(strings.TrimSpace(script[i:end]))
+			if err != nil {
+				return err
+			}
+			if err := run(scriptPart{kind: partConnect, text: dbName}); err != nil {
+				return err
+			}
+			i = skipNewline(script, end)
+			start = i
+			continue
+		}
+
+		switch {
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			i = lineEnd(script, i)
+			continue
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(script)
+			}
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		}
+		if sqlStart < 0 {
+			sqlStart = i
+		}
+
+		switch {
+		case c == '\'':
+			escapes := i > 0 && (script[i-1] == 'E' || script[i-1] == 'e') && (i < 2 || !isIdentChar(script[i-2]))
+			i = quotedEnd(script, i, '\'', escapes)
+		case c == '"':
+			i = quotedEnd(script, i, '"', false)
+		case c == '$' && (i == 0 || !isIdentChar(script[i-1])):
+			tag := dollarTag.FindString(script[i:])
+			if tag == "" {
+				i++
+				break
+			}
+			if end := strings.Index(script[i+len(tag):], tag); end >= 0 {
+				i += len(tag) + end + len(tag)
+			} else {
+				i = len(script)
+			}
+		case c == ';':
+			i++
+			stmt := script[sqlStart:i]
+			if copyFromStdin.MatchString(stmt) {
+				dataStart := skipNewline(script, lineEnd(script, i))
+				dataEnd, next, err := copyDataEnd(script, dataStart)
+				if err != nil {
+					return err
+				}
+				if err := run(scriptPart{kind: partCopy, text: strings.TrimSuffix(stmt, ";"), data: script[dataStart:dataEnd]}); err != nil {
+					return err
+				}
+				i = next
+			} else if err := run(scriptPart{kind: partSQL, text: script[start:i]}); err != nil {
+				return err
+			}
+			start, sqlStart = i, -1
+		default:
+			i++
+		}
+	}
+
+	if sqlStart >= 0 {
+		return run(scriptPart{kind: partSQL, text: script[start:]})
+	}
+	return nil
+}
+
+// copyDataEnd returns where the rows of a COPY starting at start end, at the
+// line \., and where the script goes on after that line.
+func copyDataEnd(script string, start int) (int, int, error) {
+	for pos := start; pos < len(script); {
+		end := lineEnd(script, pos)
+		if strings.TrimRight(script[pos:end], "\r") == `\.` {
+			return pos, skipNewline(script, end), nil
+		}
+		pos = skipNewline(script, end)
+	}
+	return 0, 0, fmt.Errorf(`COPY rows at offset %d are not terminated by \.`, start)
+}
+
+// connectTarget returns the database of a \connect meta-command.
+func connectTarget(line string) (string, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || (fields[0] != `\connect` && fields[0] != `\c`) {
+		return "", fmt.Errorf("unsupported meta-command: %s", line)
+	}
+	dbName := fields[1]
+	if len(dbName) >= 2 && strings.HasPrefix(dbName, `"`) && strings.HasSuffix(dbName, `"`) {
+		dbName = strings.ReplaceAll(dbName[1:len(dbName)-1], `""`, `"`)
+	}
+	return dbName, nil
+}
+
+// quotedEnd returns the index after the quote that closes the literal or identifier opened at start.
+func quotedEnd(script string, start int, quote byte, escapes bool) int {
+	for i := start + 1; i < len(script); i++ {
+		switch script[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case quote:
+			if i+1 < len(script) && script[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(script)
+}
+
+// lineEnd returns the index of the newline that ends the line of i, or the end of the script.
+func lineEnd(script string, i int) int {
+	if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(script)
+}
+
+// skipNewline returns the index after the newline at i.
+func skipNewline(script string, i int) int {
+	if i < len(script) {
+		return i + 1
+	}
+	return i
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
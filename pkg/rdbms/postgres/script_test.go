@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package postgres
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitScript(t *testing.T) {
+	script := `CREATE DATABASE "shop" WITH TEMPLATE = template0;
+\connect "shop"
+-- a comment; not a statement
+CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN RETURN NEW; END; $body$ LANGUAGE plpgsql;
+INSERT INTO t VALUES ('a;b', E'c\';d');
+COPY "public"."t" ("id", "name") FROM stdin;
+1	x;y
+2	\N
+\.
+SELECT 1`
+
+	var got []scriptPart
+	err := splitScript(script, func(part scriptPart) error {
+		part.text = strings.TrimSpace(part.text)
+		got = append(got, part)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scriptPart{
+		{kind: partSQL, text: `CREATE DATABASE "shop" WITH TEMPLATE = template0;`},
+		{kind: partConnect, text: "shop"},
+		{kind: partSQL, text: "-- a comment; not a statement\nCREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN RETURN NEW; END; $body$ LANGUAGE plpgsql;"},
+		{kind: partSQL, text: `INSERT INTO t VALUES ('a;b', E'c\';d');`},
+		{kind: partCopy, text: `COPY "public"."t" ("id", "name") FROM stdin`, data: "1\tx;y\n2\t\\N\n"},
+		{kind: partSQL, text: "SELECT 1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitScript() =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestCopyRow(t *testing.T) {
+	row := []sql.NullString{
+		{String: "tab\there", Valid: true},
+		{String: "line\nbreak\\", Valid: true},
+		{},
+		{String: "", Valid: true},
+	}
+	line := encodeCopyRow(row)
+	if want := "tab\\there\tline\\nbreak\\\\\t\\N\t\n"; line != want {
+		t.Errorf("encodeCopyRow() = %q, want %q", line, want)
+	}
+
+	got := make([]sql.NullString, len(row))
+	if err := decodeCopyRow(strings.TrimSuffix(line, "\n"), got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, row) {
+		t.Errorf("decodeCopyRow() = %v, want %v", got, row)
+	}
+	if unescapeCopy(`\x41\101`) != "AA" {
+		t.Errorf("unescapeCopy() does not decode hex and octal escapes")
+	}
+}
@@ -0,0 +1,562 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package postgres
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+// maxIdentLen is the length in bytes beyond which PostgreSQL truncates identifiers.
+const maxIdentLen = 63
+
+// spatialTypes are the MySQL geometry types.
+var spatialTypes = []string{"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection"}
+
+// Engine returns the engine of the database.
+func (d *PostgresDBMS) Engine() string {
+	return "postgres"
+}
+
+// TranslateSchema plans the migration of the tables of dbName from a database
+// of another engine: the statements that create them, the conversion of their
+// values and the constructs PostgreSQL changes or cannot represent.
+func (d *PostgresDBMS) TranslateSchema(engine, dbName string, tables []models.TableSchema, objects []models.SchemaObject) (*models.TranslationReport, error) {
+	if engine != "mysql" {
+		return nil, fmt.Errorf("translation from %s is not supported", engine)
+	}
+	return translateMySQL(dbName, tables, objects), nil
+}
+
+// LoadTranslated copies the rows of a table translated from another engine
+// through exec as COPY blocks, converting the values of its columns. Zero
+// dates, which PostgreSQL rejects, become NULL.
+func (d *PostgresDBMS) LoadTranslated(exec func(query string) error, table models.TableTranslation, rows func(emit func(row []sql.NullString) error) error) error {
+	columns := make([]models.Column, len(table.Columns))
+	for i, column := range table.Columns {
+		columns[i] = column.Column
+	}
+	block := &copyBlock{
+		prefix: fmt.Sprintf("COPY %s (%s) FROM stdin;\n", quoteTable(table.Target), quoteColumns(columns)),
+		size:   d.maxPacketSize,
+		emit:   exec,
+	}
+
+	zeroDates := 0
+	err := rows(func(row []sql.NullString) error {
+		for i, column := range table.Columns {
+			if !row[i].Valid || column.Conversion == "" {
+				continue
+			}
+			val, ok := convertValue(column.Conversion, row[i].String)
+			if !ok {
+				zeroDates++
+			}
+			row[i] = sql.NullString{String: val, Valid: ok}
+		}
+		return block.add(encodeCopyRow(row))
+	})
+	if err != nil {
+		return err
+	}
+	if zeroDates > 0 {
+		log.Warn().Msgf("%d zero dates of table %s were copied as NULL", zeroDates, table.Table)
+	}
+	return block.flush()
+}
+
+// convertValue converts a value read from MySQL for PostgreSQL. It returns
+// false when the value becomes NULL.
+func convertValue(conversion models.ValueConversion, val string) (string, bool) {
+	switch conversion {
+	case models.ConvertBoolean:
+		if val == "0" {
+			return "f", true
+		}
+		return "t", true
+	case models.ConvertBinary:
+		return `\x` + val, true
+	case models.ConvertGeometry:
+		_, wkb, _ := strings.Cut(val, ":")
+		return `\x` + wkb, true
+	case models.ConvertDate:
+		return val, !zeroDate(val)
+	case models.ConvertTimestamp:
+		return val + "+00", !zeroDate(val)
+	}
+	return val, true
+}
+
+// zeroDate reports whether a MySQL date has a zero year, month or day.
+func zeroDate(val string) bool {
+	return len(val) >= 10 && (val[:4] == "0000" || val[5:7] == "00" || val[8:10] == "00")
+}
+
+// translator builds the translation of the tables of a MySQL database.
+type translator struct {
+	report *models.TranslationReport
+	tables map[string]models.TableSchema
+	// collations counts the columns of each collation PostgreSQL does not have
+	collations map[string]int
+	// identities move the identity sequences past the copied rows
+	identities []string
+}
+
+// translateMySQL translates the tables of a MySQL database. Indexes and
+// foreign keys are created after the rows are copied, views, routines,
+// triggers and events are reported and left out.
+func translateMySQL(dbName string, tables []models.TableSchema, objects []models.SchemaObject) *models.TranslationReport {
+	t := &translator{
+		report: &models.TranslationReport{
+			Source:   "mysql",
+			Target:   "postgres",
+			Database: dbName,
+			Issues:   []models.TranslationIssue{},
+		},
+		tables:     map[string]models.TableSchema{},
+		collations: map[string]int{},
+	}
+	for _, table := range tables {
+		t.tables[table.Name] = table
+	}
+
+	t.report.Schema = append(t.report.Schema,
+		fmt.Sprintf("CREATE DATABASE %s WITH TEMPLATE = template0 ENCODING = 'UTF8';", quoteIdent(dbName)),
+		`\connect `+quoteIdent(dbName))
+	for _, table := range tables {
+		t.table(table)
+	}
+	for _, table := range tables {
+		t.indexes(table)
+	}
+	for _, table := range tables {
+		t.foreignKeys(table)
+	}
+	t.report.Finish = append(t.report.Finish, t.identities...)
+
+	collations := make([]string, 0, len(t.collations))
+	for collation := range t.collations {
+		collations = append(collations, collation)
+	}
+	sort.Strings(collations)
+	for _, collation := range collations {
+		t.issue(models.TranslationLossy, collation, "collation",
+			fmt.Sprintf("%d columns use it; they get the default collation of the database, which compares case- and accent-sensitively", t.collations[collation]))
+	}
+
+	for _, object := range objects {
+		t.issue(models.TranslationUnsupported, object.Name, string(object.Type),
+			fmt.Sprintf("MySQL %ss are not translated and left out", object.Type))
+	}
+	return t.report
+}
+
+// issue records a construct the translation changes or leaves out.
+func (t *translator) issue(level models.TranslationLevel, object, construct, detail string) {
+	t.report.Issues = append(t.report.Issues, models.TranslationIssue{Level: level, Object: object, Construct: construct, Detail: detail})
+}
+
+// table translates a table with its columns and primary key.
+func (t *translator) table(table models.TableSchema) {
+	target := "public." + table.Name
+	translation := models.TableTranslation{Table: table.Name, Target: target}
+
+	var defs, checks []string
+	for _, column := range table.Columns {
+		object := table.Name + "." + column.Name
+		typ, conversion, check := t.columnType(object, column)
+		def := quoteIdent(column.Name) + " " + typ + t.collate(column) + t.columnDefault(object, column, typ, conversion)
+		if !column.Nullable {
+			def += " NOT NULL"
+		}
+		defs = append(defs, def)
+		if check != "" {
+			checks = append(checks, check)
+		}
+		if strings.Contains(strings.ToLower(column.Extra), "auto_increment") {
+			t.identities = append(t.identities, fmt.Sprintf("SELECT setval(pg_get_serial_sequence(%s, %s), GREATEST(COALESCE(MAX(%s), 0) + 1, %d), false) FROM %s;",
+				quoteLiteral(quoteTable(target)), quoteLiteral(column.Name), quoteIdent(column.Name), max(table.AutoIncrement, 1), quoteTable(target)))
+		}
+		translation.Columns = append(translation.Columns, models.ColumnTranslation{
+			Column:     models.Column{Name: column.Name, Type: column.DataType},
+			TargetType: typ,
+			Conversion: conversion,
+		})
+	}
+	for _, index := range table.Indexes {
+		if !index.Primary {
+			continue
+		}
+		if columns, ok := t.indexColumns(table.Name+"."+index.Name, index); ok {
+			defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", columns))
+		}
+	}
+	defs = append(defs, checks...)
+	for _, check := range table.Checks {
+		t.issue(models.TranslationUnsupported, table.Name+"."+check, "CHECK constraint", "MySQL expressions are not translated; the constraint is left out")
+	}
+
+	t.report.Schema = append(t.report.Schema,
+		fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE;", quoteTable(target)),
+		fmt.Sprintf("CREATE TABLE %s (\n  %s\n);", quoteTable(target), strings.Join(defs, ",\n  ")))
+	t.report.Tables = append(t.report.Tables, translation)
+}
+
+// columnType returns the PostgreSQL type of a MySQL column, how its values
+// are converted and the CHECK constraint that keeps its values, if any.
+func (t *translator) columnType(object string, column models.ColumnSchema) (string, models.ValueConversion, string) {
+	columnType := strings.ToLower(column.ColumnType)
+	unsigned := strings.Contains(columnType, " unsigned")
+	args := typeArgs(column.ColumnType)
+	arg := func(i int, def string) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return def
+	}
+	signless := func(typ string) string {
+		if unsigned {
+			t.issue(models.TranslationLossy, object, "UNSIGNED", typ+" has no unsigned variant; negative values are no longer rejected")
+		}
+		return typ
+	}
+
+	switch dataType := strings.ToLower(column.DataType); dataType {
+	case "tinyint":
+		if strings.HasPrefix(columnType, "tinyint(1)") && !unsigned {
+			t.issue(models.TranslationLossy, object, "TINYINT(1)", "becomes boolean; values other than 0 and 1 become true")
+			return "boolean", models.ConvertBoolean, ""
+		}
+		return "smallint", "", ""
+	case "smallint":
+		if unsigned {
+			return "integer", "", ""
+		}
+		return "smallint", "", ""
+	case "mediumint":
+		return "integer", "", ""
+	case "int", "integer":
+		if unsigned {
+			return "bigint", "", ""
+		}
+		return "integer", "", ""
+	case "bigint":
+		if !unsigned {
+			return "bigint", "", ""
+		}
+		if strings.Contains(strings.ToLower(column.Extra), "auto_increment") {
+			t.issue(models.TranslationLossy, object, "BIGINT UNSIGNED AUTO_INCREMENT", "becomes a bigint identity; values above 9223372036854775807 do not fit")
+			return "bigint", "", ""
+		}
+		return "numeric(20)", "", ""
+	case "decimal", "numeric":
+		return signless(fmt.Sprintf("numeric(%s,%s)", arg(0, "10"), arg(1, "0"))), "", ""
+	case "float":
+		return signless("real"), "", ""
+	case "double", "real":
+		return signless("double precision"), "", ""
+	case "bit":
+		bits, _ := strconv.Atoi(arg(0, "1"))
+		if bits <= 1 {
+			return "boolean", models.ConvertBoolean, ""
+		}
+		t.issue(models.TranslationLossy, object, fmt.Sprintf("BIT(%d)", bits), "becomes an integer holding the bits")
+		if bits < 64 {
+			return "bigint", "", ""
+		}
+		return "numeric(20)", "", ""
+	case "char", "varchar":
+		return fmt.Sprintf("%s(%s)", dataType, arg(0, "1")), "", ""
+	case "tinytext", "text", "mediumtext", "longtext":
+		return "text", "", ""
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "bytea", models.ConvertBinary, ""
+	case "date":
+		return "date", models.ConvertDate, ""
+	case "datetime":
+		return fmt.Sprintf("timestamp(%s) without time zone", arg(0, "0")), models.ConvertDate, ""
+	case "timestamp":
+		return fmt.Sprintf("timestamp(%s) with time zone", arg(0, "0")), models.ConvertTimestamp, ""
+	case "time":
+		// TIME holds durations up to 838 hours, which time of day cannot
+		return "interval", "", ""
+	case "year":
+		return "smallint", "", ""
+	case "json":
+		return "jsonb", "", ""
+	case "enum":
+		values := make([]string, len(args))
+		for i, value := range args {
+			values[i] = quoteLiteral(unquoteValue(value))
+		}
+		t.issue(models.TranslationLossy, object, "ENUM", "becomes text with a CHECK constraint; it sorts by value instead of by position")
+		return "text", "", fmt.Sprintf("CHECK (%s IN (%s))", quoteIdent(column.Name), strings.Join(values, ", "))
+	case "set":
+		t.issue(models.TranslationLossy, object, "SET", "becomes text holding the members separated by commas; the members are not checked")
+		return "text", "", ""
+	default:
+		if slices.Contains(spatialTypes, dataType) {
+			t.issue(models.TranslationLossy, object, strings.ToUpper(dataType), "becomes bytea holding the WKB; the SRID and spatial functions are lost")
+			return "bytea", models.ConvertGeometry, ""
+		}
+		t.issue(models.TranslationUnsupported, object, strings.ToUpper(dataType), "the type is not translated; the column becomes text")
+		return "text", "", ""
+	}
+}
+
+// collate returns the COLLATE clause of a column. Binary collations compare
+// like the C collation; the others are counted for the report.
+func (t *translator) collate(column models.ColumnSchema) string {
+	switch {
+	case column.Collation == "":
+		return ""
+	case strings.HasSuffix(column.Collation, "_bin"):
+		return ` COLLATE "C"`
+	}
+	t.collations[column.Collation]++
+	return ""
+}
+
+// columnDefault returns the DEFAULT or identity clause of a column.
+func (t *translator) columnDefault(object string, column models.ColumnSchema, typ string, conversion models.ValueConversion) string {
+	extra := strings.ToLower(column.Extra)
+	if strings.Contains(extra, "on update") {
+		t.issue(models.TranslationLossy, object, "ON UPDATE CURRENT_TIMESTAMP", "is not translated; updates keep the value unless a trigger sets it")
+	}
+	switch {
+	case strings.Contains(extra, "auto_increment"):
+		return " GENERATED BY DEFAULT AS IDENTITY"
+	case strings.Contains(extra, "virtual generated"), strings.Contains(extra, "stored generated"):
+		t.issue(models.TranslationLossy, object, "generated column", "becomes a plain column holding the copied values")
+		return ""
+	case column.Default == nil:
+		return ""
+	}
+
+	def := *column.Default
+	// MariaDB quotes literal defaults and writes NULL for no default
+	if def == "NULL" {
+		return ""
+	}
+	if len(def) >= 2 && def[0] == '\'' && def[len(def)-1] == '\'' {
+		def = unquoteValue(def)
+	}
+
+	upper := strings.ToUpper(strings.Trim(def, "()"))
+	switch {
+	case strings.HasPrefix(upper, "CURRENT_TIMESTAMP"), strings.HasPrefix(upper, "NOW"), strings.HasPrefix(upper, "LOCALTIMESTAMP"):
+		if strings.HasSuffix(typ, "without time zone") {
+			return " DEFAULT LOCALTIMESTAMP"
+		}
+		return " DEFAULT CURRENT_TIMESTAMP"
+	case upper == "CURDATE" || upper == "CURRENT_DATE":
+		return " DEFAULT CURRENT_DATE"
+	case strings.Contains(extra, "default_generated"):
+		t.issue(models.TranslationUnsupported, object, "DEFAULT expression", fmt.Sprintf("the default %s is not translated and left out", def))
+		return ""
+	}
+
+	if strings.EqualFold(column.DataType, "bit") {
+		def = bitValue(def)
+	}
+	switch conversion {
+	case models.ConvertBoolean:
+		if def == "0" {
+			return " DEFAULT false"
+		}
+		return " DEFAULT true"
+	case models.ConvertBinary:
+		if strings.HasPrefix(def, "0x") {
+			return " DEFAULT " + quoteLiteral(`\x`+def[2:])
+		}
+		return " DEFAULT " + quoteLiteral(`\x`+hex.EncodeToString([]byte(def)))
+	case models.ConvertGeometry:
+		return ""
+	case models.ConvertDate, models.ConvertTimestamp:
+		if zeroDate(def) {
+			t.issue(models.TranslationLossy, object, "zero date default", "PostgreSQL has no zero dates; the default is left out")
+			return ""
+		}
+		if conversion == models.ConvertTimestamp {
+			def += "+00"
+		}
+	}
+	return " DEFAULT " + quoteLiteral(def)
+}
+
+// indexes translates the indexes of a table other than its primary key.
+func (t *translator) indexes(table models.TableSchema) {
+	for _, index := range table.Indexes {
+		object := table.Name + "." + index.Name
+		if index.Primary {
+			continue
+		}
+		if typ := strings.ToUpper(index.Type); typ == "FULLTEXT" || typ == "SPATIAL" {
+			t.issue(models.TranslationUnsupported, object, typ+" index", "is not translated and left out")
+			continue
+		}
+		columns, ok := t.indexColumns(object, index)
+		if !ok {
+			continue
+		}
+		unique := ""
+		if index.Unique {
+			unique = "UNIQUE "
+		}
+		t.report.Finish = append(t.report.Finish, fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);",
+			unique, quoteIdent(truncateIdent(table.Name+"_"+index.Name)), quoteTable("public."+table.Name), columns))
+	}
+}
+
+// indexColumns returns the quoted columns of an index, or false when the
+// index cannot be translated.
+func (t *translator) indexColumns(object string, index models.IndexSchema) (string, bool) {
+	columns := make([]string, len(index.Columns))
+	for i, column := range index.Columns {
+		if column.Name == "" {
+			t.issue(models.TranslationUnsupported, object, "functional index", "MySQL expressions are not translated; the index is left out")
+			return "", false
+		}
+		if column.SubPart > 0 {
+			detail := fmt.Sprintf("indexes the whole column %s instead of its first %d characters", column.Name, column.SubPart)
+			if index.Unique || index.Primary {
+				detail += "; uniqueness applies to whole values"
+			}
+			t.issue(models.TranslationLossy, object, "prefix index", detail)
+		}
+		columns[i] = quoteIdent(column.Name)
+	}
+	return strings.Join(columns, ", "), true
+}
+
+// foreignKeys translates the foreign keys of a table. They are added without
+// checking the copied rows, which Validate does apart.
+func (t *translator) foreignKeys(table models.TableSchema) {
+	for _, fk := range table.ForeignKeys {
+		object := table.Name + "." + fk.Name
+		if _, ok := t.tables[fk.RefTable]; !ok && fk.RefDatabase == "" {
+			t.issue(models.TranslationUnsupported, object, "foreign key to a table left out",
+				fmt.Sprintf("references %s, which is not migrated; the foreign key is left out", fk.RefTable))
+			continue
+		}
+		if fk.RefDatabase != "" {
+			t.issue(models.TranslationUnsupported, object, "cross-database foreign key",
+				fmt.Sprintf("references %s.%s, which is not migrated; the foreign key is left out", fk.RefDatabase, fk.RefTable))
+			continue
+		}
+		if !t.uniqueKey(fk.RefTable, fk.RefColumns) {
+			t.issue(models.TranslationUnsupported, object, "foreign key to non-unique columns",
+				"PostgreSQL needs a primary key or unique index on the referenced columns; the foreign key is left out")
+			continue
+		}
+		columns := make([]string, len(fk.Columns))
+		for i, column := range fk.Columns {
+			columns[i] = quoteIdent(column)
+		}
+		refColumns := make([]string, len(fk.RefColumns))
+		for i, column := range fk.RefColumns {
+			refColumns[i] = quoteIdent(column)
+		}
+		t.report.Finish = append(t.report.Finish, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s) ON DELETE %s ON UPDATE %s NOT VALID;",
+			quoteTable("public."+table.Name), quoteIdent(fk.Name), strings.Join(columns, ", "),
+			quoteTable("public."+fk.RefTable), strings.Join(refColumns, ", "), fk.OnDelete, fk.OnUpdate))
+		t.report.Validate = append(t.report.Validate, fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s;", quoteTable("public."+table.Name), quoteIdent(fk.Name)))
+	}
+}
+
+// uniqueKey reports whether columns are the whole primary key or a whole
+// unique index of a table.
+func (t *translator) uniqueKey(table string, columns []string) bool {
+	for _, index := range t.tables[table].Indexes {
+		if !index.Primary && !index.Unique || len(index.Columns) != len(columns) {
+			continue
+		}
+		match := true
+		for _, column := range index.Columns {
+			if column.SubPart > 0 || !slices.Contains(columns, column.Name) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// typeArgs returns the arguments in parentheses of a MySQL column type, with
+// the quotes of enum and set values kept.
+func typeArgs(columnType string) []string {
+	start := strings.IndexByte(columnType, '(')
+	if start < 0 {
+		return nil
+	}
+	var args []string
+	var arg strings.Builder
+	quoted := false
+	for i := start + 1; i < len(columnType); i++ {
+		c := columnType[i]
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case !quoted && c == ',':
+			args = append(args, strings.TrimSpace(arg.String()))
+			arg.Reset()
+			continue
+		case !quoted && c == ')':
+			return append(args, strings.TrimSpace(arg.String()))
+		}
+		arg.WriteByte(c)
+	}
+	return args
+}
+
+// unquoteValue removes the single quotes around a MySQL value.
+func unquoteValue(value string) string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "'"), "'")
+	return strings.ReplaceAll(value, "''", "'")
+}
+
+// bitValue returns a b'...' bit literal as a number.
+func bitValue(def string) string {
+	if !strings.HasPrefix(def, "b'") {
+		return def
+	}
+	n, err := strconv.ParseUint(strings.Trim(def[1:], "'"), 2, 64)
+	if err != nil {
+		return def
+	}
+	return strconv.FormatUint(n, 10)
+}
+
+// truncateIdent shortens an identifier to the length PostgreSQL keeps.
+func truncateIdent(name string) string {
+	for len(name) > maxIdentLen {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package postgres
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+func TestTranslateMySQL(t *testing.T) {
+	zero := "0000-00-00 00:00:00"
+	now := "CURRENT_TIMESTAMP"
+	off := "0"
+	tables := []models.TableSchema{
+		{
+			Name:          "orders",
+			AutoIncrement: 42,
+			Columns: []models.ColumnSchema{
+				{Name: "id", DataType: "bigint", ColumnType: "bigint unsigned", Extra: "auto_increment"},
+				{Name: "state", DataType: "enum", ColumnType: "enum('new','it''s')", Collation: "utf8mb4_0900_ai_ci"},
+				{Name: "paid", DataType: "tinyint", ColumnType: "tinyint(1)", Default: &off},
+				{Name: "created", DataType: "timestamp", ColumnType: "timestamp", Default: &now, Extra: "DEFAULT_GENERATED on update CURRENT_TIMESTAMP"},
+				{Name: "shipped", DataType: "datetime", ColumnType: "datetime(3)", Nullable: true, Default: &zero},
+				{Name: "code", DataType: "varchar", ColumnType: "varchar(20)", Collation: "utf8mb4_bin"},
+				{Name: "customer", DataType: "int", ColumnType: "int"},
+			},
+			Indexes: []models.IndexSchema{
+				{Name: "PRIMARY", Primary: true, Unique: true, Type: "BTREE", Columns: []models.IndexColumn{{Name: "id"}}},
+				{Name: "code", Unique: true, Type: "BTREE", Columns: []models.IndexColumn{{Name: "code", SubPart: 10}}},
+				{Name: "ft", Type: "FULLTEXT", Columns: []models.IndexColumn{{Name: "code"}}},
+			},
+			ForeignKeys: []models.ForeignKeySchema{
+				{Name: "fk_customer", Columns: []string{"customer"}, RefTable: "customers", RefColumns: []string{"id"}, OnDelete: "CASCADE", OnUpdate: "RESTRICT"},
+				{Name: "fk_name", Columns: []string{"code"}, RefTable: "customers", RefColumns: []string{"name"}, OnDelete: "RESTRICT", OnUpdate: "RESTRICT"},
+				{Name: "fk_store", Columns: []string{"customer"}, RefTable: "stores", RefColumns: []string{"id"}, OnDelete: "RESTRICT", OnUpdate: "RESTRICT"},
+			},
+		},
+		{
+			Name:    "customers",
+			Columns: []models.ColumnSchema{{Name: "id", DataType: "int", ColumnType: "int"}, {Name: "name", DataType: "varchar", ColumnType: "varchar(20)"}},
+			Indexes: []models.IndexSchema{{Name: "PRIMARY", Primary: true, Unique: true, Type: "BTREE", Columns: []models.IndexColumn{{Name: "id"}}}},
+		},
+	}
+	objects := []models.SchemaObject{{Type: models.SchemaView, Name: "open_orders"}}
+
+	report := translateMySQL("shop", tables, objects)
+
+	create := strings.Join(report.Schema, "\n")
+	for _, want := range []string{
+		`\connect "shop"`,
+		`"id" bigint GENERATED BY DEFAULT AS IDENTITY NOT NULL`,
+		`"state" text NOT NULL`,
+		`"paid" boolean DEFAULT false NOT NULL`,
+		`"created" timestamp(0) with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL`,
+		`"shipped" timestamp(3) without time zone,`,
+		`"code" varchar(20) COLLATE "C" NOT NULL`,
+		`PRIMARY KEY ("id")`,
+		`CHECK ("state" IN ('new', 'it''s'))`,
+	} {
+		if !strings.Contains(create, want) {
+			t.Errorf("schema lacks %s:\n%s", want, create)
+		}
+	}
+	wantFinish := []string{
+		`CREATE UNIQUE INDEX "orders_code" ON "public"."orders" ("code");`,
+		`ALTER TABLE "public"."orders" ADD CONSTRAINT "fk_customer" FOREIGN KEY ("customer") REFERENCES "public"."customers" ("id") ON DELETE CASCADE ON UPDATE RESTRICT NOT VALID;`,
+		`SELECT setval(pg_get_serial_sequence('"public"."orders"', 'id'), GREATEST(COALESCE(MAX("id"), 0) + 1, 42), false) FROM "public"."orders";`,
+	}
+	if !slices.Equal(report.Finish, wantFinish) {
+		t.Errorf("finish = %q, want %q", report.Finish, wantFinish)
+	}
+	if want := []string{`ALTER TABLE "public"."orders" VALIDATE CONSTRAINT "fk_customer";`}; !slices.Equal(report.Validate, want) {
+		t.Errorf("validate = %q, want %q", report.Validate, want)
+	}
+
+	var issues []string
+	for _, issue := range report.Issues {
+		issues = append(issues, string(issue.Level)+" "+issue.Object+" "+issue.Construct)
+	}
+	for _, want := range []string{
+		"lossy orders.id BIGINT UNSIGNED AUTO_INCREMENT",
+		"lossy orders.state ENUM",
+		"lossy orders.paid TINYINT(1)",
+		"lossy orders.created ON UPDATE CURRENT_TIMESTAMP",
+		"lossy orders.shipped zero date default",
+		"lossy orders.code prefix index",
+		"unsupported orders.ft FULLTEXT index",
+		"unsupported orders.fk_name foreign key to non-unique columns",
+		"unsupported orders.fk_store foreign key to a table left out",
+		"lossy utf8mb4_0900_ai_ci collation",
+		"unsupported open_orders view",
+	} {
+		if !slices.Contains(issues, want) {
+			t.Errorf("issues lack %q: %q", want, issues)
+		}
+	}
+
+	if conversion := report.Tables[0].Columns[3].Conversion; conversion != models.ConvertTimestamp {
+		t.Errorf("created conversion = %q, want %q", conversion, models.ConvertTimestamp)
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		conversion models.ValueConversion
+		in, want   string
+		valid      bool
+	}{
+		{models.ConvertBoolean, "0", "f", true},
+		{models.ConvertBoolean, "2", "t", true},
+		{models.ConvertBinary, "CAFE", `\xCAFE`, true},
+		{models.ConvertGeometry, "4326:0101", `\x0101`, true},
+		{models.ConvertDate, "0000-00-00 00:00:00", "", false},
+		{models.ConvertDate, "2024-02-00", "", false},
+		{models.ConvertTimestamp, "2024-02-03 04:05:06", "2024-02-03 04:05:06+00", true},
+	}
+	for _, tt := range tests {
+		got, valid := convertValue(tt.conversion, tt.in)
+		if valid != tt.valid || valid && got != tt.want {
+			t.Errorf("convertValue(%s, %q) = %q, %v, want %q, %v", tt.conversion, tt.in, got, valid, tt.want, tt.valid)
+		}
+	}
+}
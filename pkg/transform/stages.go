@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"golang.org/x/text/encoding/htmlindex"
+	xtransform "golang.org/x/text/transform"
+)
+
+func gzipStage(w io.Writer, r io.Reader) error {
+	gw := gzip.NewWriter(w)
+	if _, err := io.Copy(gw, r); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func gunzipReader(r io.Reader) (io.Reader, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("gunzip: %w", err)
+	}
+	return gr, nil
+}
+
+// csvToJSONLStage writes every CSV record as a JSON object keyed by the header row.
+func csvToJSONLStage(w io.Writer, r io.Reader) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("csv-to-jsonl: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	bw := bufio.NewWriter(w)
+	var line bytes.Buffer
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("csv-to-jsonl: %w", err)
+		}
+
+		// written by hand to keep the column order of the header
+		line.Reset()
+		line.WriteByte('{')
+		for i, value := range record {
+			if i > 0 {
+				line.WriteByte(',')
+			}
+			k, _ := json.Marshal(header[i])
+			v, _ := json.Marshal(value)
+			line.Write(k)
+			line.WriteByte(':')
+			line.Write(v)
+		}
+		line.WriteString("}\n")
+		if _, err := bw.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func utf8Reader(r io.Reader, charset string) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("to-utf8: unknown charset %q", charset)
+	}
+	return xtransform.NewReader(r, enc.NewDecoder()), nil
+}
+
+// split writes r as parts of at most partSize bytes named key.part-0001, key.part-0002, ...
+// With lines, parts end at line ends; a line longer than partSize gets a part of its own.
+func split(r io.Reader, key string, partSize int64, lines bool, sink Sink) ([]models.TransformOutput, error) {
+	s := &splitter{key: key, sink: sink, outputs: []models.TransformOutput{}}
+	defer s.abort()
+
+	br := bufio.NewReader(r)
+	lineStart := true
+	for {
+		var frag []byte
+		var err error
+		if lines {
+			frag, err = br.ReadSlice('\n')
+			if err == bufio.ErrBufferFull {
+				err = nil
+			}
+		} else {
+			buf := make([]byte, 32<<10)
+			var n int
+			n, err = br.Read(buf)
+			frag = buf[:n]
+		}
+
+		for len(frag) > 0 {
+			if s.w == nil || (s.written > 0 && s.written+int64(len(frag)) > partSize && (!lines || lineStart)) {
+				if err := s.next(); err != nil {
+					return nil, err
+				}
+			}
+			n := len(frag)
+			if !lines && s.written+int64(n) > partSize {
+				n = int(partSize - s.written)
+			}
+			if _, err := s.w.Write(frag[:n]); err != nil {
+				return nil, err
+			}
+			s.written += int64(n)
+			if lines {
+				lineStart = frag[n-1] == '\n'
+			}
+			frag = frag[n:]
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.w == nil {
+		// an empty object still gets one empty part
+		if err := s.next(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.close(); err != nil {
+		return nil, err
+	}
+	return s.outputs, nil
+}
+
+type splitter struct {
+	key     string
+	sink    Sink
+	outputs []models.TransformOutput
+
+	w       io.Writer
+	wc      io.WriteCloser
+	h       hash.Hash
+	written int64
+}
+
+// next closes the current part and opens the next one.
+func (s *splitter) next() error {
+	if err := s.close(); err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s.part-%04d", s.key, len(s.outputs)+1)
+	wc, err := s.sink(key)
+	if err != nil {
+		return err
+	}
+	s.wc = wc
+	s.h = sha256.New()
+	s.w = io.MultiWriter(wc, s.h)
+	s.written = 0
+	s.outputs = append(s.outputs, models.TransformOutput{Key: key})
+	return nil
+}
+
+func (s *splitter) close() error {
+	if s.wc == nil {
+		return nil
+	}
+	wc := s.wc
+	s.wc = nil
+	if err := wc.Close(); err != nil {
+		return err
+	}
+	out := &s.outputs[len(s.outputs)-1]
+	out.Size = s.written
+	out.SHA256 = hex.EncodeToString(s.h.Sum(nil))
+	return nil
+}
+
+// abort stops an unfinished part without committing it.
+func (s *splitter) abort() {
+	if s.wc == nil {
+		return
+	}
+	abortWriter(s.wc, errors.New("transform aborted"))
+	s.wc = nil
+}
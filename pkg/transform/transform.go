@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package transform converts object data while it is copied between object storages.
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Sink creates an object written by a pipeline.
+type Sink func(key string) (io.WriteCloser, error)
+
+// Rules are the compiled transform rules of a task.
+type Rules struct {
+	pipelines []*Pipeline
+}
+
+// Pipeline is the transform steps of one rule.
+type Pipeline struct {
+	// Rule describes the rule in reports
+	Rule  string
+	match string
+	steps []models.TransformStep
+}
+
+// Compile validates rules. It returns nil when there is no rule.
+func Compile(rules []models.TransformRule) (*Rules, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	compiled := &Rules{}
+	for i, rule := range rules {
+		if _, err := path.Match(rule.Match, ""); err != nil {
+			return nil, fmt.Errorf("transform rule %d: invalid match %q: %w", i, rule.Match, err)
+		}
+		if len(rule.Steps) == 0 {
+			return nil, fmt.Errorf("transform rule %d: no steps", i)
+		}
+
+		names := make([]string, 0, len(rule.Steps))
+		for j, step := range rule.Steps {
+			if err := validateStep(step, j == len(rule.Steps)-1); err != nil {
+				return nil, fmt.Errorf("transform rule %d: %w", i, err)
+			}
+			names = append(names, string(step.Type))
+		}
+
+		match := rule.Match
+		if match == "" {
+			match = "*"
+		}
+		compiled.pipelines = append(compiled.pipelines, &Pipeline{
+			Rule:  fmt.Sprintf("%s: %s", match, strings.Join(names, ",")),
+			match: rule.Match,
+			steps: rule.Steps,
+		})
+	}
+	return compiled, nil
+}
+
+func validateStep(step models.TransformStep, last bool) error {
+	switch step.Type {
+	case models.TransformGzip, models.TransformGunzip, models.TransformCSVToJSONL:
+	case models.TransformToUTF8:
+		if _, err := htmlindex.Get(step.Charset); err != nil {
+			return fmt.Errorf("to-utf8: unknown charset %q", step.Charset)
+		}
+	case models.TransformSplit:
+		if step.PartSize <= 0 {
+			return errors.New("split: partSize must be positive")
+		}
+		if !last {
+			return errors.New("split must be the last step")
+		}
+	default:
+		return fmt.Errorf("unknown transform %q", step.Type)
+	}
+	return nil
+}
+
+// Match returns the pipeline of the first rule matching key.
+// A nil *Rules matches nothing.
+func (r *Rules) Match(key string) (*Pipeline, bool) {
+	if r == nil {
+		return nil, false
+	}
+	for _, p := range r.pipelines {
+		if p.matches(key) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func (p *Pipeline) matches(key string) bool {
+	if p.match == "" {
+		return true
+	}
+	name := key
+	if !strings.Contains(p.match, "/") {
+		name = path.Base(key)
+	}
+	ok, _ := path.Match(p.match, name)
+	return ok
+}
+
+// Run transforms the object key read from src and writes the results to sink.
+// It returns the written objects with their size and SHA-256.
+func (p *Pipeline) Run(key string, src io.Reader, sink Sink) ([]models.TransformOutput, error) {
+	var pipes []*io.PipeReader
+	defer func() {
+		// stop the stage goroutines when the consumer gave up early
+		for _, pr := range pipes {
+			pr.CloseWithError(errors.New("transform aborted"))
+		}
+	}()
+
+	r := src
+	for _, step := range p.steps {
+		var err error
+		switch step.Type {
+		case models.TransformGzip:
+			r = stage(&pipes, r, gzipStage)
+			key += ".gz"
+		case models.TransformGunzip:
+			if r, err = gunzipReader(r); err != nil {
+				return nil, err
+			}
+			key = strings.TrimSuffix(key, ".gz")
+		case models.TransformCSVToJSONL:
+			r = stage(&pipes, r, csvToJSONLStage)
+			key = replaceExt(key, ".csv", ".jsonl")
+		case models.TransformToUTF8:
+			if r, err = utf8Reader(r, step.Charset); err != nil {
+				return nil, err
+			}
+		case models.TransformSplit:
+			return split(r, key, step.PartSize, step.Lines, sink)
+		}
+	}
+
+	out, err := write(sink, key, r)
+	if err != nil {
+		return nil, err
+	}
+	return []models.TransformOutput{out}, nil
+}
+
+// stage runs fn in a goroutine that writes the output of r to a pipe.
+func stage(pipes *[]*io.PipeReader, r io.Reader, fn func(w io.Writer, r io.Reader) error) io.Reader {
+	pr, pw := io.Pipe()
+	*pipes = append(*pipes, pr)
+	go func() {
+		pw.CloseWithError(fn(pw, r))
+	}()
+	return pr
+}
+
+// write stores r as one object.
+func write(sink Sink, key string, r io.Reader) (models.TransformOutput, error) {
+	w, err := sink(key)
+	if err != nil {
+		return models.TransformOutput{}, err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), r)
+	if err != nil {
+		abortWriter(w, err)
+		return models.TransformOutput{}, err
+	}
+	if err := w.Close(); err != nil {
+		return models.TransformOutput{}, err
+	}
+	return models.TransformOutput{Key: key, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// abortWriter stops w without committing it when w supports CloseWithError,
+// and closes it otherwise so its upload does not hang.
+func abortWriter(w io.WriteCloser, err error) {
+	if aw, ok := w.(interface{ CloseWithError(error) error }); ok {
+		_ = aw.CloseWithError(err)
+		return
+	}
+	_ = w.Close()
+}
+
+// replaceExt replaces the extension from of key with to, or appends to.
+func replaceExt(key, from, to string) string {
+	ext := path.Ext(key)
+	if strings.EqualFold(ext, from) {
+		return strings.TrimSuffix(key, ext) + to
+	}
+	return key + to
+}
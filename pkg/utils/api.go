@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -36,10 +37,15 @@ func GetNsId() string {
 }
 
 func RequestTumblebug(path string, method string, connName string, jsonBody []byte) ([]byte, error) {
+	return RequestTumblebugWithContext(context.Background(), path, method, connName, jsonBody)
+}
+
+// RequestTumblebugWithContext sends a request to Tumblebug that is aborted when ctx is done.
+func RequestTumblebugWithContext(ctx context.Context, path string, method string, connName string, jsonBody []byte) ([]byte, error) {
 	baseUrl := os.Getenv("TUMBLEBUG_URL")
 	url := fmt.Sprintf("%s%s", baseUrl, path)
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -66,7 +66,7 @@ func DirExists(dirPath string) bool {
 // Enum Validation
 func IsValidStatus(s models.Status) bool {
 	switch s {
-	case models.StatusActive, models.StatusInactive, models.StatusPending, models.StatusFailed, models.StatusCompleted:
+	case models.StatusActive, models.StatusInactive, models.StatusPending, models.StatusRunning, models.StatusCancelled, models.StatusFailed, models.StatusCompleted, models.StatusPaused:
 		return true
 	}
 	return false
@@ -82,7 +82,7 @@ func IsValidServiceType(s models.CloudServiceType) bool {
 
 func IsValidTaskType(s models.TaskType) bool {
 	switch s {
-	case models.Generate, models.Migrate, models.Backup, models.Restore:
+	case models.Generate, models.Migrate, models.Backup, models.Restore, models.Verify, models.Sync:
 		return true
 	}
 	return false
@@ -16,8 +16,10 @@ limitations under the License.
 package nrdbc
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/cloud-barista/mc-data-manager/pkg/progress"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -30,10 +32,17 @@ type NRDBMS interface {
 	ExportTable(tableName string, dstData *[]map[string]interface{}) error
 }
 
+// ContextNRDBMS is implemented by databases whose requests can be cancelled.
+type ContextNRDBMS interface {
+	SetContext(ctx context.Context)
+}
+
 type NRDBController struct {
 	client NRDBMS
 
-	logger *zerolog.Logger
+	ctx      context.Context
+	logger   *zerolog.Logger
+	progress *progress.Tracker
 }
 
 type Option func(*NRDBController)
@@ -44,13 +53,32 @@ func WithLogger(logger *zerolog.Logger) Option {
 	}
 }
 
+func WithProgress(tracker *progress.Tracker) Option {
+	return func(n *NRDBController) {
+		n.progress = tracker
+	}
+}
+
+// WithContext sets the context that cancels the requests of the controller.
+func WithContext(ctx context.Context) Option {
+	return func(n *NRDBController) {
+		if ctx != nil {
+			n.ctx = ctx
+		}
+	}
+}
+
 func New(nrdb NRDBMS, opts ...Option) (*NRDBController, error) {
 	nrdbc := &NRDBController{
 		client: nrdb,
+		ctx:    context.Background(),
 	}
 	for _, opt := range opts {
 		opt(nrdbc)
 	}
+	if c, ok := nrdb.(ContextNRDBMS); ok {
+		c.SetContext(nrdbc.ctx)
+	}
 
 	return nrdbc, nil
 }
@@ -132,20 +160,29 @@ func (src *NRDBController) Copy(dst *NRDBController) error {
 		return err
 	}
 	log.Debug().Msgf("%+v", tableList)
+	src.progress.AddTotal(int64(len(tableList)), 0)
 	for _, table := range tableList {
+		if err := src.ctx.Err(); err != nil {
+			src.logWrite("Error", "Migration cancelled", err)
+			return err
+		}
 		log.Debug().Msgf("%+v", table)
+		src.progress.SetCurrent(table)
 		src.logWrite("Info", fmt.Sprintf("Migration start: %s", table), nil)
 		data := []map[string]interface{}{}
 		src.logWrite("Info", fmt.Sprintf("Extract start: %s", table), nil)
 		if err := src.Get(table, &data); err != nil {
 			src.logWrite("Error", "Get error", err)
+			src.progress.Fail(table, err)
 			return err
 		}
 		src.logWrite("Info", fmt.Sprintf("Import start: %s", table), nil)
 		if err := dst.Put(table, &data); err != nil {
 			src.logWrite("Error", "Put error", err)
+			src.progress.Fail(table, err)
 			return err
 		}
+		src.progress.ObjectDone(table)
 		src.logWrite("Info", fmt.Sprintf("Migration success: src:/%s -> dst:/%s", table, table), nil)
 	}
 	return nil
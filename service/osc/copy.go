@@ -16,6 +16,7 @@ limitations under the License.
 package osc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -59,6 +60,12 @@ func (src *OSController) Copy(dst *OSController, flt *filtering.ObjectFilter) er
 	for _, skip := range skipList {
 		src.logWrite("Info", fmt.Sprintf("skip file : %s", skip.Key), nil)
 	}
+	src.progress.Skip(int64(len(skipList)))
+	if err := src.quota.Plan(int64(len(copyList)), sumSize(copyList)); err != nil {
+		src.logWrite("Error", "transfer quota", err)
+		return err
+	}
+	src.progress.AddTotal(int64(len(copyList)), sumSize(copyList))
 
 	jobs := make(chan models.Object, len(copyList))
 	resultChan := make(chan Result, len(copyList))
@@ -83,12 +90,18 @@ func (src *OSController) Copy(dst *OSController, flt *filtering.ObjectFilter) er
 	}()
 
 	for ret := range resultChan {
+		if errors.Is(ret.err, context.Canceled) {
+			continue
+		}
 		if ret.err != nil {
 			src.logWrite("Error", fmt.Sprintf("Migration failed: %s", ret.name), ret.err)
+			src.progress.Fail(ret.name, ret.err)
+			continue
 		}
+		src.progress.ObjectDone(ret.name)
 	}
 
-	return nil
+	return src.ctx.Err()
 }
 
 func copyWorker(src *OSController, dst *OSController, jobs chan models.Object, resultChan chan<- Result) {
@@ -98,34 +111,48 @@ func copyWorker(src *OSController, dst *OSController, jobs chan models.Object, r
 			err:  nil,
 		}
 
-		srcFile, err := src.osfs.Open(obj.Key)
-		if err != nil {
+		if err := src.ctx.Err(); err != nil {
 			ret.err = err
 			resultChan <- ret
 			continue
 		}
 
-		dstFile, err := dst.osfs.Create(obj.Key)
+		if pipeline, ok := src.transforms.Match(obj.Key); ok {
+			ret.err = src.transformObject(dst, obj, pipeline)
+			resultChan <- ret
+			continue
+		}
+
+		srcFile, err := src.osfs.Open(obj.Key)
 		if err != nil {
 			ret.err = err
 			resultChan <- ret
 			continue
 		}
 
-		n, err := io.Copy(dstFile, srcFile)
+		targetKey := src.TargetKey(obj.Key)
+		dstFile, err := dst.osfs.Create(targetKey)
 		if err != nil {
+			_ = srcFile.Close()
 			ret.err = err
 			resultChan <- ret
 			continue
 		}
 
-		if n != obj.Size {
-			ret.err = errors.New("copy failed")
+		n, err := io.Copy(dstFile, src.reader(srcFile))
+		if err == nil && n != obj.Size {
+			err = errors.New("copy failed")
+		}
+		if err != nil {
+			_ = srcFile.Close()
+			closeWithError(dstFile, err)
+			ret.err = err
 			resultChan <- ret
 			continue
 		}
 
 		if err := srcFile.Close(); err != nil {
+			closeWithError(dstFile, err)
 			ret.err = err
 			resultChan <- ret
 			continue
@@ -137,8 +164,17 @@ func copyWorker(src *OSController, dst *OSController, jobs chan models.Object, r
 			continue
 		}
 
-		src.logWrite("Info", fmt.Sprintf("Migration success: src:/%s -> dst:/%s", obj.Key, obj.Key), nil)
+		src.logWrite("Info", fmt.Sprintf("Migration success: src:/%s -> dst:/%s", obj.Key, targetKey), nil)
 
 		resultChan <- ret
 	}
 }
+
+// sumSize returns the total size of objList.
+func sumSize(objList []*models.Object) int64 {
+	var total int64
+	for _, obj := range objList {
+		total += obj.Size
+	}
+	return total
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/backup"
+	"github.com/cloud-barista/mc-data-manager/pkg/dedup"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+)
+
+// Repository opens a deduplicating backup repository in the bucket of the controller.
+func (osc *OSController) Repository(opts ...dedup.Option) (*dedup.Repository, error) {
+	if err := osc.osfs.CreateBucket(); err != nil {
+		osc.logWrite("Error", "CreateBucket error", err)
+		return nil, err
+	}
+	return dedup.Open(osc.osfs, opts...)
+}
+
+// DedupBackup backs up the objects selected by flt into repo as a new snapshot.
+//
+// Objects whose size, ETag and modification time did not change since the
+// latest snapshot reuse its chunk list without being downloaded. All other
+// objects are downloaded and only their chunks missing in the repository are stored.
+func (osc *OSController) DedupBackup(repo *dedup.Repository, meta models.BackupGeneration, flt *filtering.ObjectFilter) (*models.DedupSnapshot, error) {
+	catalog, err := repo.Catalog()
+	if err != nil {
+		osc.logWrite("Error", "repository catalog error", err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	snap := &models.DedupSnapshot{BackupGeneration: meta, Entries: []models.DedupEntry{}}
+	snap.ID = backup.NewGenerationID(catalog, now)
+	snap.StartedAt = now
+
+	prev := map[string]models.DedupEntry{}
+	if latest, ok := catalog.Latest(); ok {
+		base, err := repo.LoadSnapshot(latest.ID)
+		if err != nil {
+			osc.logWrite("Error", "LoadSnapshot error", err)
+			return nil, err
+		}
+		for _, entry := range base.Entries {
+			prev[entry.Key] = entry
+		}
+		snap.Base = latest.ID
+	}
+
+	srcObjList, err := osc.ObjectListWithFilter(flt)
+	if err != nil {
+		osc.logWrite("Error", "ObjectListWithFilter error", err)
+		return nil, err
+	}
+
+	fetchList := []*models.Object{}
+	var fetchSize int64
+	for _, obj := range srcObjList {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		if obj.LastModified.After(snap.Watermark) {
+			snap.Watermark = obj.LastModified
+		}
+		snap.Objects++
+		snap.Bytes += obj.Size
+
+		if old, ok := prev[obj.Key]; ok && unchanged(old, obj) {
+			snap.Entries = append(snap.Entries, old)
+			snap.Chunks += len(old.Chunks)
+			continue
+		}
+		fetchList = append(fetchList, obj)
+		fetchSize += obj.Size
+	}
+	osc.progress.Skip(int64(snap.Objects - len(fetchList)))
+	if err := osc.quota.Plan(int64(len(fetchList)), fetchSize); err != nil {
+		osc.logWrite("Error", "transfer quota", err)
+		return nil, err
+	}
+	osc.progress.AddTotal(int64(len(fetchList)), fetchSize)
+
+	entries, failed := osc.dedupObjects(repo, snap, fetchList)
+	if err := osc.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if failed > 0 {
+		return nil, fmt.Errorf("backup failed: %d of %d objects could not be stored", failed, len(fetchList))
+	}
+	snap.Entries = append(snap.Entries, entries...)
+
+	snap.FinishedAt = time.Now().UTC()
+	if err := repo.SaveSnapshot(snap); err != nil {
+		osc.logWrite("Error", "SaveSnapshot error", err)
+		return nil, err
+	}
+	osc.logWrite("Info", fmt.Sprintf("Snapshot %s: %d objects, %d new chunks, %d new bytes of %d",
+		snap.ID, snap.Objects, snap.NewChunks, snap.NewBytes, snap.Bytes), nil)
+	return snap, nil
+}
+
+// dedupObjects writes objList into repo and returns the new entries and the number of failed objects.
+func (osc *OSController) dedupObjects(repo *dedup.Repository, snap *models.DedupSnapshot, objList []*models.Object) ([]models.DedupEntry, int) {
+	jobs := make(chan *models.Object, len(objList))
+	for _, obj := range objList {
+		jobs <- obj
+	}
+	close(jobs)
+
+	var (
+		mu      sync.Mutex
+		entries []models.DedupEntry
+		failed  int
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < osc.threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range jobs {
+				entry, result, err := osc.dedupObject(repo, obj)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					entries = append(entries, entry)
+					snap.Chunks += len(entry.Chunks)
+					snap.NewChunks += result.NewChunks
+					snap.NewBytes += result.NewBytes
+					if result.NewChunks > 0 {
+						snap.NewObjects++
+					}
+				case osc.ctx.Err() == nil:
+					osc.logWrite("Error", fmt.Sprintf("Backup failed: %s", obj.Key), err)
+					osc.progress.Fail(obj.Key, err)
+					failed++
+				default:
+					failed++
+				}
+				mu.Unlock()
+
+				if err == nil {
+					osc.progress.ObjectDone(obj.Key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return entries, failed
+}
+
+func (osc *OSController) dedupObject(repo *dedup.Repository, obj *models.Object) (models.DedupEntry, dedup.WriteResult, error) {
+	if err := osc.ctx.Err(); err != nil {
+		return models.DedupEntry{}, dedup.WriteResult{}, err
+	}
+
+	src, err := osc.osfs.Open(obj.Key)
+	if err != nil {
+		return models.DedupEntry{}, dedup.WriteResult{}, err
+	}
+	defer src.Close()
+
+	result, err := repo.WriteObject(osc.reader(src))
+	if err != nil {
+		return models.DedupEntry{}, result, err
+	}
+	if obj.Size > 0 && result.Size != obj.Size {
+		return models.DedupEntry{}, result, errors.New("backup failed: size mismatch")
+	}
+
+	return models.DedupEntry{
+		Key:          obj.Key,
+		Size:         result.Size,
+		ETag:         obj.ETag,
+		LastModified: obj.LastModified,
+		Chunks:       result.Chunks,
+	}, result, nil
+}
+
+// unchanged reports whether obj is still the object recorded in a previous snapshot.
+func unchanged(old models.DedupEntry, obj *models.Object) bool {
+	if old.Size != obj.Size || !old.LastModified.Equal(obj.LastModified) {
+		return false
+	}
+	oldSum, oldOk := comparableETag(old.ETag)
+	newSum, newOk := comparableETag(obj.ETag)
+	return !oldOk || !newOk || oldSum == newSum
+}
+
+// RestoreSnapshot uploads the objects of a snapshot selected by opts.
+func (osc *OSController) RestoreSnapshot(repo *dedup.Repository, snap *models.DedupSnapshot, opts RestoreOptions) error {
+	if err := osc.osfs.CreateBucket(); err != nil {
+		osc.logWrite("Error", "CreateBucket error", err)
+		return err
+	}
+
+	objList := make([]LocalObject, 0, len(snap.Entries))
+	entries := make(map[string]models.DedupEntry, len(snap.Entries))
+	for _, entry := range snap.Entries {
+		objList = append(objList, LocalObject{Key: entry.Key, Size: entry.Size, LastModified: entry.LastModified})
+		entries[targetKey(opts.TargetPrefix, entry.Key)] = entry
+	}
+
+	putList, err := osc.selectRestore(objList, opts)
+	if err != nil {
+		return err
+	}
+	return osc.putObjects(putList, func(obj LocalObject) error {
+		if err := osc.ctx.Err(); err != nil {
+			return err
+		}
+
+		src := repo.OpenObject(entries[obj.Key])
+		defer src.Close()
+
+		dst, err := osc.osfs.Create(obj.Key)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, osc.reader(src)); err != nil {
+			closeWithError(dst, err)
+			return err
+		}
+		if err := dst.Close(); err != nil {
+			return err
+		}
+		osc.logWrite("Info", fmt.Sprintf("Import success: %s -> %s", snap.ID, obj.Key), nil)
+		return nil
+	})
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/dedup"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestDedupBackupAndRestore(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := newMemFS()
+	src.put("a.txt", strings.Repeat("a", 4096), t0)
+	src.put("b.txt", strings.Repeat("a", 4096), t0)
+	src.put("c.txt", "c", t0)
+
+	repo, err := dedup.Open(newMemFS(), dedup.WithChunkSizes(1024, 2048, 8192))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srcCtrl, _ := osc.New(src)
+	first, err := srcCtrl.DedupBackup(repo, models.BackupGeneration{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Objects != 3 || first.NewChunks >= first.Chunks {
+		t.Fatalf("identical objects must share chunks: %d new of %d", first.NewChunks, first.Chunks)
+	}
+
+	src.put("c.txt", "changed", t0.Add(time.Hour))
+	second, err := srcCtrl.DedupBackup(repo, models.BackupGeneration{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Base != first.ID || second.NewObjects != 1 || second.Objects != 3 {
+		t.Fatalf("second snapshot = %+v", second.BackupGeneration)
+	}
+
+	snap, err := repo.LoadSnapshot(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := newMemFS()
+	dstCtrl, _ := osc.New(dst)
+	if err := dstCtrl.RestoreSnapshot(repo, snap, osc.RestoreOptions{TargetPrefix: "restored"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := dst.get("restored/c.txt"); data != "c" {
+		t.Errorf("restored/c.txt = %q, want the first snapshot", data)
+	}
+	if data, _ := dst.get("restored/b.txt"); data != strings.Repeat("a", 4096) {
+		t.Errorf("restored/b.txt has %d bytes", len(data))
+	}
+}
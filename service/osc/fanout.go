@@ -0,0 +1,265 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+)
+
+// fanOutBufferSize is the size of the blocks an object is streamed to its targets in
+const fanOutBufferSize = 256 << 10
+
+// FanOutTarget is a destination of a fan-out copy.
+type FanOutTarget struct {
+	Name string
+	OSC  *OSController
+}
+
+type fanOutResult struct {
+	target int
+	err    error
+}
+
+// FanOut copies the objects selected by flt to every target and reads each source object once.
+//
+// An object is streamed to all targets that miss it at the same time. Failures
+// are recorded in the status of the target. With continueOnError the other
+// targets carry on, otherwise the copy stops at the first failure.
+func (src *OSController) FanOut(targets []FanOutTarget, flt *filtering.ObjectFilter, continueOnError bool) ([]models.TargetStatus, error) {
+	statuses := make([]models.TargetStatus, len(targets))
+	for i, t := range targets {
+		statuses[i] = models.TargetStatus{Target: t.Name, Status: models.StatusRunning}
+	}
+
+	srcObjList, err := src.ObjectListWithFilter(flt)
+	if err != nil {
+		src.logWrite("Error", "source objectList error", err)
+		return statuses, err
+	}
+
+	path, pathExcludeYn := "", ""
+	if flt != nil {
+		path = strings.TrimPrefix(flt.Path, "/")
+		pathExcludeYn = flt.PathExcludeYn
+	}
+
+	need := map[string][]int{}
+	for i, t := range targets {
+		dstObjList, err := t.prepare()
+		if err != nil {
+			src.logWrite("Error", fmt.Sprintf("target %s error", t.Name), err)
+			statuses[i].Status = models.StatusFailed
+			statuses[i].Error = err.Error()
+			if !continueOnError {
+				return statuses, err
+			}
+			continue
+		}
+
+		copyList, skipList := getDownloadList(dstObjList, srcObjList, path, pathExcludeYn)
+		statuses[i].Skipped = len(skipList)
+		for _, obj := range copyList {
+			need[obj.Key] = append(need[obj.Key], i)
+		}
+	}
+
+	copyList := []*models.Object{}
+	for _, obj := range srcObjList {
+		if _, ok := need[obj.Key]; ok {
+			copyList = append(copyList, obj)
+		}
+	}
+	src.progress.Skip(int64(len(srcObjList) - len(copyList)))
+	if err := src.quota.Plan(int64(len(copyList)), sumSize(copyList)); err != nil {
+		src.logWrite("Error", "transfer quota", err)
+		return nil, err
+	}
+	src.progress.AddTotal(int64(len(copyList)), sumSize(copyList))
+
+	jobs := make(chan *models.Object, len(copyList))
+	for _, obj := range copyList {
+		jobs <- obj
+	}
+	close(jobs)
+
+	var (
+		mu      sync.Mutex
+		stopped atomic.Bool
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < src.threads; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range jobs {
+				if src.ctx.Err() != nil || stopped.Load() {
+					continue
+				}
+
+				errs := src.fanOutObject(obj, targets, need[obj.Key])
+
+				mu.Lock()
+				for _, i := range need[obj.Key] {
+					if err := errs[i]; err != nil {
+						statuses[i].Failed++
+						if statuses[i].Error == "" {
+							statuses[i].Error = fmt.Sprintf("%s: %v", obj.Key, err)
+						}
+						src.logWrite("Error", fmt.Sprintf("Migration failed: %s -> %s", obj.Key, targets[i].Name), err)
+						continue
+					}
+					statuses[i].Copied++
+					statuses[i].Bytes += obj.Size
+				}
+				mu.Unlock()
+
+				if len(errs) == 0 {
+					src.logWrite("Info", fmt.Sprintf("Migration success: src:/%s -> %d targets", obj.Key, len(need[obj.Key])), nil)
+					src.progress.ObjectDone(obj.Key)
+					continue
+				}
+				if src.ctx.Err() == nil {
+					src.progress.Fail(obj.Key, fmt.Errorf("%d of %d targets failed", len(errs), len(need[obj.Key])))
+				}
+				if !continueOnError {
+					stopped.Store(true)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := src.ctx.Err(); err != nil {
+		for i := range statuses {
+			if statuses[i].Status == models.StatusRunning {
+				statuses[i].Status = models.StatusCancelled
+			}
+		}
+		return statuses, err
+	}
+
+	failed := 0
+	for i := range statuses {
+		if statuses[i].Status == models.StatusRunning && statuses[i].Failed == 0 && !stopped.Load() {
+			statuses[i].Status = models.StatusCompleted
+			continue
+		}
+		statuses[i].Status = models.StatusFailed
+		failed++
+	}
+	if failed > 0 {
+		return statuses, fmt.Errorf("%d of %d targets failed", failed, len(targets))
+	}
+	return statuses, nil
+}
+
+// prepare creates the bucket of the target and lists its objects.
+func (t FanOutTarget) prepare() ([]*models.Object, error) {
+	if err := t.OSC.osfs.CreateBucket(); err != nil {
+		return nil, err
+	}
+	return t.OSC.osfs.ObjectList()
+}
+
+// fanOutObject streams one source object to the targets idx and returns the errors by target.
+func (src *OSController) fanOutObject(obj *models.Object, targets []FanOutTarget, idx []int) map[int]error {
+	errs := map[int]error{}
+	srcFile, err := src.osfs.Open(obj.Key)
+	if err != nil {
+		for _, i := range idx {
+			errs[i] = err
+		}
+		return errs
+	}
+	defer srcFile.Close()
+
+	pipes := make(map[int]*io.PipeWriter, len(idx))
+	results := make(chan fanOutResult, len(idx))
+	for _, i := range idx {
+		pr, pw := io.Pipe()
+		pipes[i] = pw
+		go func(i int, dst *OSController) {
+			results <- fanOutResult{target: i, err: copyFromPipe(dst, src.TargetKey(obj.Key), obj, pr)}
+		}(i, targets[i].OSC)
+	}
+
+	var readErr error
+	r := src.reader(srcFile)
+	buf := make([]byte, fanOutBufferSize)
+	for len(pipes) > 0 {
+		n, err := r.Read(buf)
+		if n > 0 {
+			for i, pw := range pipes {
+				// a target that failed closed its pipe and is dropped
+				if _, werr := pw.Write(buf[:n]); werr != nil {
+					delete(pipes, i)
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			readErr = err
+			break
+		}
+	}
+	for _, pw := range pipes {
+		if readErr != nil {
+			pw.CloseWithError(readErr)
+		} else {
+			pw.Close()
+		}
+	}
+
+	for range idx {
+		ret := <-results
+		if ret.err != nil {
+			errs[ret.target] = ret.err
+		}
+	}
+	return errs
+}
+
+// copyFromPipe writes the data of obj read from pr to key in dst.
+func copyFromPipe(dst *OSController, key string, obj *models.Object, pr *io.PipeReader) error {
+	dstFile, err := dst.osfs.Create(key)
+	if err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+
+	n, err := io.Copy(dstFile, pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		closeWithError(dstFile, err)
+		return err
+	}
+	if n != obj.Size {
+		err := errors.New("copy failed")
+		closeWithError(dstFile, err)
+		return err
+	}
+	return dstFile.Close()
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+// brokenFS is a target that rejects every upload.
+type brokenFS struct {
+	*memFS
+}
+
+func (b brokenFS) Create(name string) (io.WriteCloser, error) {
+	return nil, errors.New("upload rejected")
+}
+
+func TestFanOut(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := newMemFS()
+	src.put("a.txt", "aaa", t0)
+	src.put("b.txt", "bbb", t0)
+
+	full := newMemFS()
+	partial := newMemFS()
+	partial.put("a.txt", "aaa", t0)
+	srcCtrl, _ := osc.New(src)
+	fullCtrl, _ := osc.New(full)
+	partialCtrl, _ := osc.New(partial)
+	brokenCtrl, _ := osc.New(brokenFS{newMemFS()})
+
+	targets := []osc.FanOutTarget{
+		{Name: "full", OSC: fullCtrl},
+		{Name: "broken", OSC: brokenCtrl},
+		{Name: "partial", OSC: partialCtrl},
+	}
+	statuses, err := srcCtrl.FanOut(targets, nil, true)
+	if err == nil {
+		t.Fatal("a failed target must fail the fan-out")
+	}
+
+	want := []models.TargetStatus{
+		{Target: "full", Status: models.StatusCompleted, Copied: 2, Bytes: 6},
+		{Target: "broken", Status: models.StatusFailed, Failed: 2},
+		{Target: "partial", Status: models.StatusCompleted, Copied: 1, Skipped: 1, Bytes: 3},
+	}
+	for i, st := range statuses {
+		st.Error = ""
+		if st != want[i] {
+			t.Errorf("target %d = %+v, want %+v", i, st, want[i])
+		}
+	}
+	if data, _ := full.get("b.txt"); data != "bbb" {
+		t.Errorf("full/b.txt = %q", data)
+	}
+	if data, _ := partial.get("b.txt"); data != "bbb" {
+		t.Errorf("partial/b.txt = %q", data)
+	}
+}
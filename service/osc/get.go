@@ -16,6 +16,7 @@ limitations under the License.
 package osc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/cloud-barista/mc-data-manager/models"
 	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/posixmeta"
 	"github.com/cloud-barista/mc-data-manager/pkg/utils"
 )
 
@@ -90,20 +92,36 @@ func (osc *OSController) MGet(dirPath string, flt *filtering.ObjectFilter) error
 	for _, skip := range skipList {
 		osc.logWrite("Info", fmt.Sprintf("skip file : %s", skip.Key), nil)
 	}
+	osc.progress.Skip(int64(len(skipList)))
+	if err := osc.quota.Plan(int64(len(downlaodList)), sumSize(downlaodList)); err != nil {
+		osc.logWrite("Error", "transfer quota", err)
+		return err
+	}
+	osc.progress.AddTotal(int64(len(downlaodList)), sumSize(downlaodList))
 
-	jobs := make(chan models.Object, len(downlaodList))
-	resultChan := make(chan Result, len(downlaodList))
+	osc.getObjects(dirPath, downlaodList)
+	return osc.ctx.Err()
+}
+
+// getObjects downloads objList into dirPath and returns the number of failed objects.
+//
+// Symbolic links are created after all regular files, so that no download is
+// written through a link that came from the bucket.
+func (osc *OSController) getObjects(dirPath string, objList []*models.Object) int {
+	jobs := make(chan models.Object, len(objList))
+	resultChan := make(chan Result, len(objList))
+	links := &pendingLinks{}
 
 	var wg sync.WaitGroup
 	for i := 0; i < osc.threads; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			mGetWorker(osc, dirPath, jobs, resultChan)
+			mGetWorker(osc, dirPath, jobs, resultChan, links)
 		}()
 	}
 
-	for _, obj := range downlaodList {
+	for _, obj := range objList {
 		jobs <- *obj
 	}
 	close(jobs)
@@ -113,12 +131,61 @@ func (osc *OSController) MGet(dirPath string, flt *filtering.ObjectFilter) error
 		close(resultChan)
 	}()
 
+	failed := 0
 	for ret := range resultChan {
+		if errors.Is(ret.err, context.Canceled) {
+			failed++
+			continue
+		}
 		if ret.err != nil {
 			osc.logWrite("Error", fmt.Sprintf("Export failed: %s", ret.name), ret.err)
+			osc.progress.Fail(ret.name, ret.err)
+			failed++
+			continue
 		}
+		osc.progress.ObjectDone(ret.name)
+	}
+
+	for _, link := range links.list {
+		if err := osc.createLink(dirPath, link); err != nil {
+			osc.logWrite("Error", fmt.Sprintf("Export failed: %s", link.obj.Key), err)
+			osc.progress.Fail(link.obj.Key, err)
+			failed++
+			continue
+		}
+		osc.logWrite("Info", fmt.Sprintf("Export success: %s -> %s (symlink)", link.obj.Key, link.fileName), nil)
+		osc.progress.ObjectDone(link.obj.Key)
+	}
+	return failed
+}
+
+// pendingLink is a symbolic link object whose creation waits for the regular files.
+type pendingLink struct {
+	obj      models.Object
+	fileName string
+	meta     map[string]string
+}
+
+type pendingLinks struct {
+	mu   sync.Mutex
+	list []pendingLink
+}
+
+func (p *pendingLinks) add(link pendingLink) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.list = append(p.list, link)
+}
+
+func (osc *OSController) createLink(dirPath string, link pendingLink) error {
+	if err := osc.ctx.Err(); err != nil {
+		return err
+	}
+	target, _ := posixmeta.Symlink(link.meta)
+	if err := posixmeta.CreateSymlink(dirPath, link.fileName, target); err != nil {
+		return err
 	}
-	return nil
+	return osc.applyMetadata(link.fileName, link.meta, link.obj.LastModified)
 }
 
 func getDownloadList(fileList, objList []*models.Object, path string, pathExcludeYn string) ([]*models.Object, []*models.Object) {
@@ -174,15 +241,25 @@ func combinePaths(basePath, relativePath string) (string, error) {
 
 	parts := strings.Split(relativePath, "/")
 	if bName == parts[0] {
-		return filepath.Join(basePath, strings.Join(parts[1:], "/")), nil
+		relativePath = strings.Join(parts[1:], "/")
 	}
-	return filepath.Join(basePath, relativePath), nil
+	fileName := filepath.Join(basePath, relativePath)
+	if rel, err := filepath.Rel(filepath.Clean(basePath), fileName); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("object key %s leaves %s", relativePath, basePath)
+	}
+	return fileName, nil
 }
 
-func mGetWorker(osc *OSController, dirPath string, jobs chan models.Object, resultChan chan<- Result) {
+func mGetWorker(osc *OSController, dirPath string, jobs chan models.Object, resultChan chan<- Result, links *pendingLinks) {
 	for obj := range jobs {
 		ret := Result{name: obj.Key}
 
+		if err := osc.ctx.Err(); err != nil {
+			ret.err = err
+			resultChan <- ret
+			continue
+		}
+
 		if strings.HasSuffix(obj.Key, "/") {
 			dstDir, err := combinePaths(dirPath, obj.Key)
 			if err != nil {
@@ -190,7 +267,7 @@ func mGetWorker(osc *OSController, dirPath string, jobs chan models.Object, resu
 				resultChan <- ret
 				continue
 			}
-			if err := os.MkdirAll(dstDir, 0o755); err != nil {
+			if err := posixmeta.MkdirAll(dirPath, dstDir); err != nil {
 				ret.err = err
 				resultChan <- ret
 				continue
@@ -207,11 +284,17 @@ func mGetWorker(osc *OSController, dirPath string, jobs chan models.Object, resu
 			resultChan <- ret
 			continue
 		}
-		if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+
+		meta, err := osc.objectMetadata(obj.Key)
+		if err != nil {
 			ret.err = err
 			resultChan <- ret
 			continue
 		}
+		if _, ok := posixmeta.Symlink(meta); ok {
+			links.add(pendingLink{obj: obj, fileName: fileName, meta: meta})
+			continue
+		}
 
 		src, err := osc.osfs.Open(obj.Key)
 		if err != nil {
@@ -219,7 +302,7 @@ func mGetWorker(osc *OSController, dirPath string, jobs chan models.Object, resu
 			resultChan <- ret
 			continue
 		}
-		dst, err := os.Create(fileName)
+		dst, err := posixmeta.CreateFile(dirPath, fileName)
 		if err != nil {
 			_ = src.Close()
 			ret.err = err
@@ -227,11 +310,13 @@ func mGetWorker(osc *OSController, dirPath string, jobs chan models.Object, resu
 			continue
 		}
 
-		n, copyErr := io.Copy(dst, src)
+		n, copyErr := io.Copy(dst, osc.reader(src))
 		_ = dst.Close()
 		_ = src.Close()
 
 		if copyErr != nil {
+			// do not leave a partial file behind, it would be skipped by the next run
+			_ = os.Remove(fileName)
 			ret.err = copyErr
 			resultChan <- ret
 			continue
@@ -241,6 +326,11 @@ func mGetWorker(osc *OSController, dirPath string, jobs chan models.Object, resu
 			resultChan <- ret
 			continue
 		}
+		if err := osc.applyMetadata(fileName, meta, obj.LastModified); err != nil {
+			ret.err = err
+			resultChan <- ret
+			continue
+		}
 
 		osc.logWrite("Info", fmt.Sprintf("Export success: %s -> %s", obj.Key, fileName), nil)
 		resultChan <- ret
@@ -0,0 +1,251 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/backup"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/posixmeta"
+)
+
+// IncrementalGet backs up the new or changed objects of osc into a new generation under dirPath.
+func (osc *OSController) IncrementalGet(dirPath, taskID string, flt *filtering.ObjectFilter) (*models.BackupGeneration, error) {
+	return osc.GetGeneration(dirPath, models.BackupGeneration{TaskID: taskID, Incremental: true}, flt)
+}
+
+// GetGeneration backs up the objects of osc into a new generation directory under dirPath
+// and records it in the backup catalog. meta carries the task, source and mode of the run.
+//
+// In incremental mode only objects that are new, changed in size or checksum, or
+// modified after the watermark of the latest generation are downloaded. The
+// manifest of the new generation still lists every object with the generation
+// holding its data, so a full point-in-time view can be rebuilt with BackupView.
+//
+// The generation is recorded only when every object was fetched, so a failed
+// or cancelled run is retried from the previous watermark.
+func (osc *OSController) GetGeneration(dirPath string, meta models.BackupGeneration, flt *filtering.ObjectFilter) (*models.BackupGeneration, error) {
+	catalog, err := backup.LoadCatalog(dirPath)
+	if err != nil {
+		osc.logWrite("Error", "LoadCatalog error", err)
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	gen := meta
+	gen.ID = backup.NewGenerationID(catalog, now)
+	gen.StartedAt = now
+
+	prev := map[string]models.BackupEntry{}
+	if latest, ok := catalog.Latest(); ok && gen.Incremental {
+		manifest, err := backup.LoadManifest(dirPath, latest.ID)
+		if err != nil {
+			osc.logWrite("Error", "LoadManifest error", err)
+			return nil, err
+		}
+		for _, entry := range manifest.Entries {
+			prev[entry.Key] = entry
+		}
+		gen.Base = latest.ID
+		gen.Watermark = latest.Watermark
+	}
+	watermark := gen.Watermark
+
+	srcObjList, err := osc.ObjectListWithFilter(flt)
+	if err != nil {
+		osc.logWrite("Error", "ObjectListWithFilter error", err)
+		return nil, err
+	}
+
+	manifest := &models.BackupManifest{Generation: gen.ID, Entries: []models.BackupEntry{}}
+	fetchList := []*models.Object{}
+	for _, obj := range srcObjList {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		if obj.LastModified.After(gen.Watermark) {
+			gen.Watermark = obj.LastModified
+		}
+
+		entry := models.BackupEntry{
+			Key:          obj.Key,
+			Size:         obj.Size,
+			ETag:         obj.ETag,
+			LastModified: obj.LastModified,
+			Generation:   gen.ID,
+		}
+		if old, ok := prev[obj.Key]; ok && !changedSince(old, obj, watermark) {
+			entry.Generation = old.Generation
+			entry.Checksum = old.Checksum
+		} else {
+			fetchList = append(fetchList, obj)
+			gen.NewObjects++
+			gen.NewBytes += obj.Size
+		}
+		gen.Objects++
+		gen.Bytes += obj.Size
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	osc.progress.Skip(int64(gen.Objects - gen.NewObjects))
+	if err := osc.quota.Plan(int64(len(fetchList)), gen.NewBytes); err != nil {
+		osc.logWrite("Error", "transfer quota", err)
+		return nil, err
+	}
+	osc.progress.AddTotal(int64(len(fetchList)), gen.NewBytes)
+	osc.logWrite("Info", fmt.Sprintf("Backup generation %s: %d of %d objects changed since %s",
+		gen.ID, gen.NewObjects, gen.Objects, watermark.Format(time.RFC3339)), nil)
+
+	genDir := filepath.Join(dirPath, gen.ID)
+	if err := os.MkdirAll(genDir, 0755); err != nil {
+		osc.logWrite("Error", "MkdirAll error", err)
+		return nil, err
+	}
+
+	failed := osc.getObjects(genDir, fetchList)
+	if err := osc.ctx.Err(); err != nil {
+		_ = os.RemoveAll(genDir)
+		return nil, err
+	}
+	if failed > 0 {
+		_ = os.RemoveAll(genDir)
+		return nil, fmt.Errorf("backup failed: %d of %d objects could not be fetched", failed, len(fetchList))
+	}
+
+	for i, entry := range manifest.Entries {
+		if entry.Generation != gen.ID {
+			continue
+		}
+		fileName, err := combinePaths(genDir, entry.Key)
+		if err != nil {
+			_ = os.RemoveAll(genDir)
+			return nil, err
+		}
+		if manifest.Entries[i].Checksum, err = backup.Checksum(fileName); err != nil {
+			_ = os.RemoveAll(genDir)
+			return nil, err
+		}
+	}
+
+	gen.FinishedAt = time.Now().UTC()
+	if err := backup.Record(dirPath, gen, manifest); err != nil {
+		_ = os.RemoveAll(genDir)
+		return nil, err
+	}
+	return &gen, nil
+}
+
+// changedSince reports whether obj must be fetched again although it is in the previous manifest.
+func changedSince(old models.BackupEntry, obj *models.Object, watermark time.Time) bool {
+	if obj.LastModified.After(watermark) || old.Size != obj.Size {
+		return true
+	}
+	oldSum, oldOk := comparableETag(old.ETag)
+	newSum, newOk := comparableETag(obj.ETag)
+	return oldOk && newOk && oldSum != newSum
+}
+
+// BackupView returns the point-in-time view of a generation as object key to local file path.
+// An empty genID selects the latest generation.
+func BackupView(dirPath, genID string) (map[string]string, error) {
+	objList, err := BackupObjects(dirPath, genID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := make(map[string]string, len(objList))
+	for _, obj := range objList {
+		view[obj.Key] = obj.FileName
+	}
+	return view, nil
+}
+
+// BackupObjects returns the objects of the point-in-time view of a generation.
+// An empty genID selects the latest generation.
+func BackupObjects(dirPath, genID string) ([]LocalObject, error) {
+	catalog, err := backup.LoadCatalog(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := backup.Select(catalog, genID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := backup.LoadManifest(dirPath, gen.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	objList := make([]LocalObject, 0, len(manifest.Entries))
+	for _, entry := range manifest.Entries {
+		fileName, err := combinePaths(filepath.Join(dirPath, entry.Generation), entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		objList = append(objList, LocalObject{
+			Key:          entry.Key,
+			FileName:     fileName,
+			Size:         entry.Size,
+			LastModified: entry.LastModified,
+		})
+	}
+	return objList, nil
+}
+
+// ReconstructBackup copies the point-in-time view of a generation into dstDir.
+func ReconstructBackup(dirPath, genID, dstDir string) error {
+	view, err := BackupView(dirPath, genID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return err
+	}
+	for key, srcName := range view {
+		dstName := filepath.Join(dstDir, filepath.FromSlash(key))
+		if err := copyLocalFile(dstDir, srcName, dstName); err != nil {
+			return fmt.Errorf("reconstruct %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func copyLocalFile(dstDir, srcName, dstName string) error {
+	src, err := os.Open(srcName)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := posixmeta.CreateFile(dstDir, dstName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
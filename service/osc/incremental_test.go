@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestIncrementalGet(t *testing.T) {
+	dir := t.TempDir()
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	fs := newMemFS()
+	fs.put("data/a.txt", "a", t0)
+	fs.put("data/b.txt", "b", t0)
+
+	ctrl, err := osc.New(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := ctrl.IncrementalGet(dir, "task", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.NewObjects != 2 || first.Objects != 2 {
+		t.Fatalf("first generation = %+v", first)
+	}
+
+	fs.put("data/b.txt", "bb", t0.Add(time.Hour))
+	fs.put("data/c.txt", "c", t0.Add(time.Hour))
+
+	second, err := ctrl.IncrementalGet(dir, "task", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.NewObjects != 2 || second.Objects != 3 || second.Base != first.ID {
+		t.Fatalf("second generation = %+v", second)
+	}
+	if !second.Watermark.Equal(t0.Add(time.Hour)) {
+		t.Fatalf("watermark = %v", second.Watermark)
+	}
+
+	view, err := osc.BackupView(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"data/a.txt": "a", "data/b.txt": "bb", "data/c.txt": "c"}
+	for key, content := range want {
+		data, err := os.ReadFile(view[key])
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", key, data, content)
+		}
+	}
+
+	old, err := osc.BackupView(dir, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(old["data/b.txt"]); string(data) != "b" {
+		t.Errorf("first generation data/b.txt = %q", data)
+	}
+	if _, ok := old["data/c.txt"]; ok {
+		t.Error("first generation must not contain data/c.txt")
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestReuseList(t *testing.T) {
+	fs := newMemFS()
+	fs.put("a.txt", "a", time.Now())
+	ctrl, _ := osc.New(fs)
+
+	flt := &filtering.ObjectFilter{}
+	listed := []*models.Object{{Key: "listed.txt"}}
+	ctrl.ReuseList(flt, listed)
+
+	// another filter lists the bucket
+	objs, err := ctrl.ObjectListWithFilter(&filtering.ObjectFilter{})
+	if err != nil || len(objs) != 1 || objs[0].Key != "a.txt" {
+		t.Fatalf("other filter: %v, %v", objs, err)
+	}
+
+	// the same filter gets the listing once
+	if objs, _ := ctrl.ObjectListWithFilter(flt); len(objs) != 1 || objs[0].Key != "listed.txt" {
+		t.Errorf("reused listing: %v", objs)
+	}
+	if objs, _ := ctrl.ObjectListWithFilter(flt); len(objs) != 1 || objs[0].Key != "a.txt" {
+		t.Errorf("second listing: %v", objs)
+	}
+}
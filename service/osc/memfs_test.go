@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+// memFS is an in-memory OSFS used by the tests.
+type memFS struct {
+	mu      sync.Mutex
+	objects map[string]*memObject
+}
+
+type memObject struct {
+	data     []byte
+	modified time.Time
+	meta     map[string]string
+}
+
+func newMemFS() *memFS {
+	return &memFS{objects: map[string]*memObject{}}
+}
+
+func (m *memFS) put(key, data string, modified time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = &memObject{data: []byte(data), modified: modified}
+}
+
+func (m *memFS) get(key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[key]
+	if !ok {
+		return "", false
+	}
+	return string(obj.data), true
+}
+
+func (m *memFS) CreateBucket() error { return nil }
+
+func (m *memFS) DeleteBucket() error { return nil }
+
+func (m *memFS) BucketList(filterKey, filterVal string) ([]models.ObjectStorage, error) {
+	return nil, nil
+}
+
+func (m *memFS) ObjectList() ([]*models.Object, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	objList := []*models.Object{}
+	for key, obj := range m.objects {
+		objList = append(objList, &models.Object{
+			Key:          key,
+			Size:         int64(len(obj.data)),
+			LastModified: obj.modified,
+		})
+	}
+	sort.Slice(objList, func(i, j int) bool { return objList[i].Key < objList[j].Key })
+	return objList, nil
+}
+
+func (m *memFS) Open(name string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	return io.NopCloser(bytes.NewReader(obj.data)), nil
+}
+
+func (m *memFS) Create(name string) (io.WriteCloser, error) {
+	return &memWriter{fs: m, name: name}, nil
+}
+
+func (m *memFS) CreateWithMetadata(name string, meta map[string]string) (io.WriteCloser, error) {
+	return &memWriter{fs: m, name: name, meta: meta}, nil
+}
+
+func (m *memFS) ObjectMetadata(name string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	return obj.meta, nil
+}
+
+func (m *memFS) DeleteObjects(keys []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.objects, key)
+	}
+	return nil
+}
+
+type memWriter struct {
+	bytes.Buffer
+	fs   *memFS
+	name string
+	meta map[string]string
+}
+
+func (w *memWriter) Close() error {
+	w.fs.mu.Lock()
+	defer w.fs.mu.Unlock()
+	w.fs.objects[w.name] = &memObject{data: w.Bytes(), modified: time.Now(), meta: w.meta}
+	return nil
+}
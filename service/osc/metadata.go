@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"io"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/posixmeta"
+)
+
+// metadataFS returns the file system as a MetadataOSFS when metadata is kept and the file system can store it.
+func (osc *OSController) metadataFS() (MetadataOSFS, bool) {
+	if !osc.metadata {
+		return nil, false
+	}
+	mfs, ok := osc.osfs.(MetadataOSFS)
+	return mfs, ok
+}
+
+// createObject opens key for writing and attaches the POSIX metadata of
+// fileName when metadata is kept.
+func (osc *OSController) createObject(key, fileName string) (io.WriteCloser, error) {
+	mfs, ok := osc.metadataFS()
+	if !ok {
+		return osc.osfs.Create(key)
+	}
+	meta, err := posixmeta.Capture(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return mfs.CreateWithMetadata(key, meta)
+}
+
+// objectMetadata returns the user metadata of key, or nil when metadata is not kept.
+func (osc *OSController) objectMetadata(key string) (map[string]string, error) {
+	mfs, ok := osc.metadataFS()
+	if !ok {
+		return nil, nil
+	}
+	return mfs.ObjectMetadata(key)
+}
+
+// keepsSymlinks reports whether a symbolic link can be uploaded as a link
+// instead of as the file it points to.
+func (osc *OSController) keepsSymlinks() bool {
+	_, ok := osc.metadataFS()
+	return ok
+}
+
+// warnMetadata logs that uploads lose their POSIX metadata when it was asked for and the file system cannot store it.
+func (osc *OSController) warnMetadata() {
+	if osc.metadata && !osc.keepsSymlinks() {
+		osc.logWrite("Warn", "this object storage does not keep object metadata: file modes, owners and mtimes are not preserved and symbolic links are uploaded as the files they point to", nil)
+	}
+}
+
+// applyMetadata restores the POSIX attributes of a downloaded file.
+func (osc *OSController) applyMetadata(fileName string, meta map[string]string, lastModified time.Time) error {
+	return posixmeta.Apply(fileName, meta, lastModified)
+}
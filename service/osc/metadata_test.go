@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestPOSIXMetadataRoundTrip(t *testing.T) {
+	mtime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	srcDir := filepath.Join(t.TempDir(), "data")
+	if err := os.MkdirAll(srcDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(srcDir, "secret.txt")
+	if err := os.WriteFile(fileName, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(fileName, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(fileName, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("secret.txt", filepath.Join(srcDir, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := newMemFS()
+	ctrl, _ := osc.New(fs, osc.WithMetadata(true))
+	if err := ctrl.MPut(srcDir); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := fs.get("data/link.txt"); data != "" {
+		t.Errorf("symlink must be uploaded as an empty object, got %q", data)
+	}
+
+	dstDir := t.TempDir()
+	if err := ctrl.MGet(dstDir, &filtering.ObjectFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filepath.Join(dstDir, "data", "secret.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("mode = %v, want 0640", info.Mode().Perm())
+	}
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("mtime = %v, want %v", info.ModTime(), mtime)
+	}
+
+	target, err := os.Readlink(filepath.Join(dstDir, "data", "link.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target != "secret.txt" {
+		t.Errorf("symlink target = %q, want %q", target, "secret.txt")
+	}
+}
+
+func TestSymlinkCannotEscape(t *testing.T) {
+	outside := t.TempDir()
+	fs := newMemFS()
+	ctrl, _ := osc.New(fs, osc.WithMetadata(true))
+	fs.mu.Lock()
+	fs.objects["data/a"] = &memObject{meta: map[string]string{"mcdm-symlink": outside}}
+	fs.objects["data/b"] = &memObject{meta: map[string]string{"mcdm-symlink": "../../etc"}}
+	fs.objects["data/a/passwd"] = &memObject{data: []byte("owned")}
+	fs.objects["data/ok"] = &memObject{meta: map[string]string{"mcdm-symlink": "file"}}
+	fs.objects["data/file"] = &memObject{data: []byte("hello")}
+	fs.mu.Unlock()
+
+	dstDir := filepath.Join(t.TempDir(), "data")
+	_ = ctrl.MGet(dstDir, &filtering.ObjectFilter{})
+
+	if _, err := os.Stat(filepath.Join(outside, "passwd")); !os.IsNotExist(err) {
+		t.Fatal("a download was written through a symlink from the bucket")
+	}
+	for _, name := range []string{"a", "b"} {
+		if info, err := os.Lstat(filepath.Join(dstDir, name)); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			t.Errorf("symlink %s pointing outside the backup was created", name)
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(dstDir, "ok")); err != nil || target != "file" {
+		t.Errorf("symlink ok = %q, %v", target, err)
+	}
+}
+
+func TestMetadataOptIn(t *testing.T) {
+	srcDir := filepath.Join(t.TempDir(), "data")
+	if err := os.MkdirAll(srcDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "a.txt"), []byte("hello"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("a.txt", filepath.Join(srcDir, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	// without WithMetadata objects are uploaded through Create, links as the files they point to
+	fs := newMemFS()
+	ctrl, _ := osc.New(fs)
+	if err := ctrl.MPut(srcDir); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"data/a.txt", "data/link.txt"} {
+		if meta, _ := fs.ObjectMetadata(key); len(meta) != 0 {
+			t.Errorf("%s has metadata %v", key, meta)
+		}
+	}
+	if data, _ := fs.get("data/link.txt"); data != "hello" {
+		t.Errorf("link uploaded as %q, want the file it points to", data)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+)
+
+// ErrObjectLockUnsupported is returned when the object storage cannot make objects immutable.
+var ErrObjectLockUnsupported = errors.New("object lock is not supported")
+
+// LockingOSFS is implemented by object storages that can make objects immutable.
+type LockingOSFS interface {
+	// CheckObjectLock reports whether the bucket can apply lock
+	CheckObjectLock(lock models.ObjectLock) error
+	LockObject(name string, lock models.ObjectLock) error
+}
+
+// CheckObjectLock reports whether the object storage of the controller can apply lock.
+// The error wraps ErrObjectLockUnsupported.
+func (osc *OSController) CheckObjectLock(lock models.ObjectLock) error {
+	l, ok := osc.osfs.(LockingOSFS)
+	if !ok {
+		return fmt.Errorf("%w by this provider", ErrObjectLockUnsupported)
+	}
+	if err := l.CheckObjectLock(lock); err != nil {
+		return fmt.Errorf("%w: %w", ErrObjectLockUnsupported, err)
+	}
+	return nil
+}
+
+// LockObjects applies lock to the objects keys.
+func (osc *OSController) LockObjects(keys []string, lock models.ObjectLock) error {
+	l, ok := osc.osfs.(LockingOSFS)
+	if !ok {
+		return fmt.Errorf("%w by this provider", ErrObjectLockUnsupported)
+	}
+
+	jobs := make(chan string, len(keys))
+	for _, key := range keys {
+		jobs <- key
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for i := 0; i < osc.threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range jobs {
+				if err := osc.ctx.Err(); err != nil {
+					return
+				}
+				if err := l.LockObject(key, lock); err != nil {
+					osc.logWrite("Error", fmt.Sprintf("Lock failed: %s", key), err)
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := osc.ctx.Err(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
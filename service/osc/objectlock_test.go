@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+// lockingFS is a memFS that records the locks applied to its objects.
+type lockingFS struct {
+	*memFS
+	locked map[string]models.ObjectLock
+}
+
+func (l *lockingFS) CheckObjectLock(lock models.ObjectLock) error {
+	if lock.Mode == models.ObjectLockCompliance {
+		return errors.New("bucket allows governance mode only")
+	}
+	return nil
+}
+
+func (l *lockingFS) LockObject(name string, lock models.ObjectLock) error {
+	if _, ok := l.objects[name]; !ok {
+		return errors.New("no such object")
+	}
+	l.locked[name] = lock
+	return nil
+}
+
+func TestObjectLock(t *testing.T) {
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	lock := models.ObjectLock{Mode: models.ObjectLockGovernance, RetainUntil: &until}
+
+	plain, _ := osc.New(newMemFS())
+	if err := plain.CheckObjectLock(lock); !errors.Is(err, osc.ErrObjectLockUnsupported) {
+		t.Fatalf("CheckObjectLock without lock support = %v", err)
+	}
+
+	fs := &lockingFS{memFS: newMemFS(), locked: map[string]models.ObjectLock{}}
+	fs.put("a", "a", until)
+	fs.put("b", "b", until)
+	ctrl, _ := osc.New(fs, osc.WithThreads(1))
+	if err := ctrl.CheckObjectLock(lock); err != nil {
+		t.Fatal(err)
+	}
+	compliance := models.ObjectLock{Mode: models.ObjectLockCompliance, RetainUntil: &until}
+	if err := ctrl.CheckObjectLock(compliance); !errors.Is(err, osc.ErrObjectLockUnsupported) {
+		t.Errorf("CheckObjectLock compliance = %v", err)
+	}
+
+	if err := ctrl.LockObjects([]string{"a", "b", "missing"}, lock); err == nil {
+		t.Error("locking a missing object must fail")
+	}
+	if len(fs.locked) != 2 {
+		t.Errorf("locked %d objects, want 2", len(fs.locked))
+	}
+}
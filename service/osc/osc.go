@@ -16,10 +16,14 @@ limitations under the License.
 package osc
 
 import (
+	"context"
 	"io"
 
 	"github.com/cloud-barista/mc-data-manager/models"
 	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/progress"
+	"github.com/cloud-barista/mc-data-manager/pkg/quota"
+	"github.com/cloud-barista/mc-data-manager/pkg/transform"
 	"github.com/rs/zerolog"
 )
 
@@ -36,14 +40,47 @@ type OSFS interface {
 type OSController struct {
 	osfs OSFS
 
-	logger  *zerolog.Logger
-	threads int
+	ctx      context.Context
+	logger   *zerolog.Logger
+	threads  int
+	progress *progress.Tracker
+
+	transforms  *transform.Rules
+	transformed *transformLog
+	// targetKeys maps source keys that are written under another key
+	targetKeys map[string]string
+	quota      *quota.Guard
+	// metadata keeps POSIX metadata in object metadata, see WithMetadata
+	metadata bool
+	// listed is a listing the caller already made, see ReuseList
+	listed *listing
+}
+
+type listing struct {
+	flt  *filtering.ObjectFilter
+	objs []*models.Object
 }
 
 type FilterableOSFS interface {
 	ObjectListWithFilter(*filtering.ObjectFilter) ([]*models.Object, error)
 }
 
+// ContextOSFS is implemented by file systems whose requests can be cancelled.
+type ContextOSFS interface {
+	SetContext(ctx context.Context)
+}
+
+// MetadataOSFS is implemented by file systems that can store user metadata with an object.
+type MetadataOSFS interface {
+	CreateWithMetadata(name string, meta map[string]string) (io.WriteCloser, error)
+	ObjectMetadata(name string) (map[string]string, error)
+}
+
+// DeletableOSFS is implemented by file systems that can delete objects.
+type DeletableOSFS interface {
+	DeleteObjects(keys []string) error
+}
+
 type Result struct {
 	name string
 	err  error
@@ -89,17 +126,54 @@ func WithLogger(logger *zerolog.Logger) Option {
 	}
 }
 
+func WithProgress(tracker *progress.Tracker) Option {
+	return func(o *OSController) {
+		o.progress = tracker
+	}
+}
+
+// WithQuota counts the transfers of the controller against the quota of a task.
+func WithQuota(guard *quota.Guard) Option {
+	return func(o *OSController) {
+		o.quota = guard
+	}
+}
+
+// WithMetadata keeps the POSIX metadata of local files in object metadata on
+// upload and applies it on download, when the file system can store it.
+// File systems may upload another way to carry the metadata.
+func WithMetadata(keep bool) Option {
+	return func(o *OSController) {
+		o.metadata = keep
+	}
+}
+
+// WithContext sets the context that cancels the transfers of the controller.
+func WithContext(ctx context.Context) Option {
+	return func(o *OSController) {
+		if ctx != nil {
+			o.ctx = ctx
+		}
+	}
+}
+
 func New(osfs OSFS, opts ...Option) (*OSController, error) {
 	osc := &OSController{
-		osfs:    osfs,
-		threads: 10,
-		logger:  nil,
+		osfs:        osfs,
+		ctx:         context.Background(),
+		threads:     10,
+		logger:      nil,
+		transformed: &transformLog{},
 	}
 
 	for _, opt := range opts {
 		opt(osc)
 	}
 
+	if c, ok := osfs.(ContextOSFS); ok {
+		c.SetContext(osc.ctx)
+	}
+
 	return osc, nil
 }
 
@@ -108,13 +182,57 @@ func (osc *OSController) logWrite(logLevel, msg string, err error) {
 		switch logLevel {
 		case "Info":
 			osc.logger.Info().Msg(msg)
+		case "Warn":
+			osc.logger.Warn().Msg(msg)
 		case "Error":
 			osc.logger.Error().Msgf("%s : %v", msg, err)
 		}
 	}
 }
 
+// abortableWriter is implemented by object writers that can drop an upload instead of completing it.
+type abortableWriter interface {
+	CloseWithError(err error) error
+}
+
+// closeWithError ends an upload that failed. The upload is aborted when w
+// supports it, otherwise w is closed, so that the upload goroutine behind w ends either way.
+func closeWithError(w io.WriteCloser, err error) {
+	if aw, ok := w.(abortableWriter); ok {
+		_ = aw.CloseWithError(err)
+		return
+	}
+	_ = w.Close()
+}
+
+// reader wraps r so that reads stop once the context is done or the quota is exceeded, and are counted as progress.
+func (osc *OSController) reader(r io.Reader) io.Reader {
+	return osc.progress.Reader(osc.quota.Reader(&contextReader{ctx: osc.ctx, r: r}))
+}
+
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(b []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(b)
+}
+
+// ReuseList makes the next ObjectListWithFilter call with flt return objs
+// instead of listing the bucket again.
+func (o *OSController) ReuseList(flt *filtering.ObjectFilter, objs []*models.Object) {
+	o.listed = &listing{flt: flt, objs: objs}
+}
+
 func (o *OSController) ObjectListWithFilter(flt *filtering.ObjectFilter) ([]*models.Object, error) {
+	if l := o.listed; l != nil && l.flt == flt {
+		o.listed = nil
+		return l.objs, nil
+	}
 	if f, ok := o.osfs.(FilterableOSFS); ok {
 		return f.ObjectListWithFilter(flt)
 	}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/pii"
+)
+
+// ScanPII scans the text objects selected by flt for personal data, reading at most
+// sampleBytes of every object when sampleBytes is positive.
+// The report lists only the objects in which personal data was found.
+func (src *OSController) ScanPII(flt *filtering.ObjectFilter, scanner *pii.Scanner, sampleBytes int64) (*models.PIIReport, error) {
+	objList, err := src.ObjectListWithFilter(flt)
+	if err != nil {
+		src.logWrite("Error", "source objectList error", err)
+		return nil, err
+	}
+
+	report := &models.PIIReport{Objects: []models.PIIObject{}}
+	jobs := make(chan *models.Object, len(objList))
+	for _, obj := range objList {
+		if pii.IsText(obj.Key) {
+			jobs <- obj
+			report.Scanned++
+		} else {
+			report.Skipped++
+		}
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for i := 0; i < src.threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range jobs {
+				found, err := src.scanObject(obj, scanner, sampleBytes)
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, err)
+				} else if found != nil {
+					report.Objects = append(report.Objects, *found)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := src.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		// an object that could not be scanned may hold personal data
+		return nil, errors.Join(errs...)
+	}
+	return report, nil
+}
+
+func (src *OSController) scanObject(obj *models.Object, scanner *pii.Scanner, sampleBytes int64) (*models.PIIObject, error) {
+	if err := src.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r, err := src.osfs.Open(obj.Key)
+	if err != nil {
+		return nil, fmt.Errorf("pii scan %s: %w", obj.Key, err)
+	}
+	defer r.Close()
+
+	res, err := scanner.Scan(&contextReader{ctx: src.ctx, r: r}, sampleBytes)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("pii scan %s: %w", obj.Key, err)
+	}
+	if !res.Found() {
+		return nil, nil
+	}
+
+	src.logWrite("Info", fmt.Sprintf("personal data found: %s", obj.Key), nil)
+	return &models.PIIObject{Key: obj.Key, Scanned: res.Scanned, Findings: res.Findings}, nil
+}
+
+// Quarantine makes later copies write the objects keys under prefix in the target.
+func (src *OSController) Quarantine(keys []string, prefix string) {
+	if src.targetKeys == nil {
+		src.targetKeys = map[string]string{}
+	}
+	prefix = strings.Trim(prefix, "/")
+	for _, key := range keys {
+		src.targetKeys[key] = path.Join(prefix, key)
+	}
+}
+
+// TargetKey returns the key a source object is written to.
+func (src *OSController) TargetKey(key string) string {
+	if k, ok := src.targetKeys[key]; ok {
+		return k
+	}
+	return key
+}
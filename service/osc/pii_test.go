@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/pii"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestScanPIIQuarantine(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := newMemFS()
+	src.put("members.csv", "name,email\nAna,ana@example.com\n", t0)
+	src.put("orders.json", `{"order": 12345, "total": 10.5}`, t0)
+	src.put("photo.jpg", "ana@example.com", t0)
+	dst := newMemFS()
+
+	srcCtrl, _ := osc.New(src)
+	dstCtrl, _ := osc.New(dst)
+	scanner, _ := pii.New(nil)
+	report, err := srcCtrl.ScanPII(&filtering.ObjectFilter{}, scanner, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Scanned != 2 || report.Skipped != 1 || len(report.Objects) != 1 || report.Objects[0].Key != "members.csv" {
+		t.Fatalf("report = %+v", report)
+	}
+	if f := report.Objects[0].Findings; len(f) != 1 || f[0].Samples[0] != "a***@example.com" {
+		t.Errorf("findings = %+v", f)
+	}
+
+	srcCtrl.Quarantine([]string{"members.csv"}, "/hold/")
+	if err := srcCtrl.Copy(dstCtrl, &filtering.ObjectFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dst.get("members.csv"); ok {
+		t.Error("quarantined object copied to its own key")
+	}
+	for _, key := range []string{"hold/members.csv", "orders.json", "photo.jpg"} {
+		if _, ok := dst.get(key); !ok {
+			t.Errorf("%s not copied", key)
+		}
+	}
+}
@@ -16,9 +16,9 @@ limitations under the License.
 package osc
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -66,6 +66,17 @@ func (osc *OSController) MPut(dirPath string) error {
 		return err
 	}
 
+	var totalSize int64
+	for _, obj := range objList {
+		totalSize += obj.Size
+	}
+	if err := osc.quota.Plan(int64(len(objList)), totalSize); err != nil {
+		osc.logWrite("Error", "transfer quota", err)
+		return err
+	}
+	osc.progress.AddTotal(int64(len(objList)), totalSize)
+	osc.warnMetadata()
+
 	jobs := make(chan models.Object, len(objList))
 	resultChan := make(chan Result, len(objList))
 
@@ -89,11 +100,17 @@ func (osc *OSController) MPut(dirPath string) error {
 	}()
 
 	for ret := range resultChan {
+		if errors.Is(ret.err, context.Canceled) {
+			continue
+		}
 		if ret.err != nil {
 			osc.logWrite("Error", fmt.Sprintf("Import failed: %s", ret.name), ret.err)
+			osc.progress.Fail(ret.name, ret.err)
+			continue
 		}
+		osc.progress.ObjectDone(ret.name)
 	}
-	return nil
+	return osc.ctx.Err()
 }
 
 func mPutWorker(osc *OSController, dirPath string, jobs chan models.Object, resultChan chan<- Result) {
@@ -103,13 +120,11 @@ func mPutWorker(osc *OSController, dirPath string, jobs chan models.Object, resu
 			err:  nil,
 		}
 
-		src, err := os.Open(obj.Key)
-		if err != nil {
+		if err := osc.ctx.Err(); err != nil {
 			ret.err = err
 			resultChan <- ret
 			continue
 		}
-		defer src.Close()
 
 		fileName, err := filepath.Rel(dirPath, obj.Key)
 		if err != nil {
@@ -119,32 +134,7 @@ func mPutWorker(osc *OSController, dirPath string, jobs chan models.Object, resu
 		}
 		fileName = strings.ReplaceAll(filepath.Join(filepath.Base(dirPath), fileName), "\\", "/")
 
-		dst, err := osc.osfs.Create(fileName)
-		if err != nil {
-			ret.err = err
-			resultChan <- ret
-			continue
-		}
-		defer dst.Close()
-
-		n, err := io.Copy(dst, src)
-		if err != nil {
-			ret.err = err
-			resultChan <- ret
-			continue
-		}
-
-		if n != obj.Size {
-			ret.err = errors.New("put failed")
-			resultChan <- ret
-			continue
-		}
-
-		dst.Close()
-		src.Close()
-
-		osc.logWrite("Info", fmt.Sprintf("Import success: %s -> %s", obj.Key, fileName), nil)
-
+		ret.err = osc.putFile(obj.Key, fileName)
 		resultChan <- ret
 	}
 }
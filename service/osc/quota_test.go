@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/quota"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestCopyQuota(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := newMemFS()
+	src.put("a.txt", strings.Repeat("a", 600), t0)
+	src.put("b.txt", strings.Repeat("b", 600), t0)
+
+	// the plan of 1200 bytes exceeds the quota before anything is copied
+	ctx, cancel := context.WithCancelCause(context.Background())
+	guard := quota.Register("quota-plan", &models.TransferQuota{MaxBytes: 1000}, 0, cancel)
+	srcCtrl, _ := osc.New(src, osc.WithContext(ctx), osc.WithQuota(guard))
+	dst := newMemFS()
+	dstCtrl, _ := osc.New(dst)
+
+	err := srcCtrl.Copy(dstCtrl, &filtering.ObjectFilter{})
+	var exceeded *quota.ExceededError
+	if !errors.As(err, &exceeded) || exceeded.Phase != quota.PhasePlanning {
+		t.Fatalf("Copy error = %v, want a planning quota error", err)
+	}
+	if !errors.As(context.Cause(ctx), &exceeded) {
+		t.Error("exceeding the quota must cancel the task")
+	}
+	if len(dst.objects) != 0 {
+		t.Errorf("copied %d objects over the quota", len(dst.objects))
+	}
+
+	// the cost of 1200 bytes at 1e6 per GB is about 1.12
+	guard = quota.Register("quota-cost", &models.TransferQuota{MaxCost: 2}, 1e6, nil)
+	srcCtrl, _ = osc.New(src, osc.WithQuota(guard))
+	if err := srcCtrl.Copy(dstCtrl, &filtering.ObjectFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if used := guard.Report().Used; used.Objects != 2 || used.Bytes != 1200 {
+		t.Errorf("used = %+v", used)
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/posixmeta"
+)
+
+// LocalObject is a local file that is restored as an object.
+type LocalObject struct {
+	Key          string
+	FileName     string
+	Size         int64
+	LastModified time.Time
+}
+
+// RestoreOptions selects what a restore uploads and how it treats existing objects.
+type RestoreOptions struct {
+	Filter       *filtering.ObjectFilter
+	TargetPrefix string
+	Overwrite    models.OverwritePolicy
+}
+
+// DirObjects returns the files under dirPath with their path relative to dirPath
+// as object key, which is the key a backup of a bucket into dirPath came from.
+func DirObjects(dirPath string) ([]LocalObject, error) {
+	objList := []LocalObject{}
+	err := filepath.Walk(dirPath, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dirPath, fileName)
+		if err != nil {
+			return err
+		}
+		objList = append(objList, LocalObject{
+			Key:          filepath.ToSlash(rel),
+			FileName:     fileName,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objList, nil
+}
+
+// Restore uploads the local objects selected by opts.
+//
+// Objects are matched against the filter by their key in the backup and are
+// uploaded under TargetPrefix. Objects that already exist in the bucket are
+// skipped, overwritten, or overwritten only when the backup copy is newer,
+// depending on the overwrite policy.
+func (osc *OSController) Restore(objList []LocalObject, opts RestoreOptions) error {
+	if err := osc.osfs.CreateBucket(); err != nil {
+		osc.logWrite("Error", "CreateBucket error", err)
+		return err
+	}
+
+	putList, err := osc.selectRestore(objList, opts)
+	if err != nil {
+		return err
+	}
+	osc.warnMetadata()
+	return osc.putObjects(putList, func(obj LocalObject) error {
+		return osc.putFile(obj.FileName, obj.Key)
+	})
+}
+
+// selectRestore returns the objects of objList selected by opts, with their target keys.
+func (osc *OSController) selectRestore(objList []LocalObject, opts RestoreOptions) ([]LocalObject, error) {
+	var existing map[string]*models.Object
+	if opts.Overwrite == models.OverwriteSkip || opts.Overwrite == models.OverwriteNewer {
+		dstObjList, err := osc.osfs.ObjectList()
+		if err != nil {
+			osc.logWrite("Error", "target objectList error", err)
+			return nil, err
+		}
+		existing = make(map[string]*models.Object, len(dstObjList))
+		for _, obj := range dstObjList {
+			existing[obj.Key] = obj
+		}
+	}
+
+	putList := []LocalObject{}
+	skipped := 0
+	var totalSize int64
+	for _, obj := range objList {
+		c := filtering.Candidate{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified}
+		if !filtering.MatchCandidate(opts.Filter, c) {
+			continue
+		}
+
+		obj.Key = targetKey(opts.TargetPrefix, obj.Key)
+		if dst, ok := existing[obj.Key]; ok {
+			if opts.Overwrite == models.OverwriteSkip || !obj.LastModified.After(dst.LastModified) {
+				osc.logWrite("Info", fmt.Sprintf("skip file : %s", obj.Key), nil)
+				skipped++
+				continue
+			}
+		}
+		putList = append(putList, obj)
+		totalSize += obj.Size
+	}
+	osc.progress.Skip(int64(skipped))
+	if err := osc.quota.Plan(int64(len(putList)), totalSize); err != nil {
+		osc.logWrite("Error", "transfer quota", err)
+		return nil, err
+	}
+	osc.progress.AddTotal(int64(len(putList)), totalSize)
+	return putList, nil
+}
+
+// targetKey places key under prefix.
+func targetKey(prefix, key string) string {
+	prefix = strings.Trim(strings.ReplaceAll(prefix, "\\", "/"), "/")
+	if prefix == "" {
+		return key
+	}
+	return path.Join(prefix, key)
+}
+
+// putObjects uploads objList with put and fails when any object could not be uploaded.
+func (osc *OSController) putObjects(objList []LocalObject, put func(LocalObject) error) error {
+	jobs := make(chan LocalObject, len(objList))
+	resultChan := make(chan Result, len(objList))
+
+	var wg sync.WaitGroup
+	for i := 0; i < osc.threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range jobs {
+				resultChan <- Result{name: obj.Key, err: put(obj)}
+			}
+		}()
+	}
+
+	for _, obj := range objList {
+		jobs <- obj
+	}
+	close(jobs)
+
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	failed := 0
+	for ret := range resultChan {
+		if errors.Is(ret.err, context.Canceled) {
+			continue
+		}
+		if ret.err != nil {
+			osc.logWrite("Error", fmt.Sprintf("Import failed: %s", ret.name), ret.err)
+			osc.progress.Fail(ret.name, ret.err)
+			failed++
+			continue
+		}
+		osc.progress.ObjectDone(ret.name)
+	}
+	if err := osc.ctx.Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d objects could not be imported", failed, len(objList))
+	}
+	return nil
+}
+
+// putFile uploads a local file as the object key.
+//
+// When metadata is kept, the mode, owner and mtime of
+// the file are kept with the object and a symbolic link is uploaded as an
+// empty object that records its target.
+func (osc *OSController) putFile(fileName, key string) error {
+	if err := osc.ctx.Err(); err != nil {
+		return err
+	}
+
+	if osc.keepsSymlinks() && posixmeta.IsSymlink(fileName) {
+		dst, err := osc.createObject(key, fileName)
+		if err != nil {
+			return err
+		}
+		if err := dst.Close(); err != nil {
+			return err
+		}
+		osc.logWrite("Info", fmt.Sprintf("Import success: %s -> %s (symlink)", fileName, key), nil)
+		return nil
+	}
+
+	src, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := osc.createObject(key, fileName)
+	if err != nil {
+		return err
+	}
+
+	// a failed or short copy is aborted, so that no truncated object is committed
+	n, err := io.Copy(dst, osc.reader(src))
+	if err == nil && n != info.Size() {
+		err = errors.New("put failed: size mismatch")
+	}
+	if err != nil {
+		closeWithError(dst, err)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	osc.logWrite("Info", fmt.Sprintf("Import success: %s -> %s", fileName, key), nil)
+	return nil
+}
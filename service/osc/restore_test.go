@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestRestore(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := newMemFS()
+	src.put("docs/a.txt", "a", t0)
+	src.put("docs/b.txt", "b", t0.Add(2*time.Hour))
+	src.put("images/c.png", "c", t0)
+
+	dir := t.TempDir()
+	srcCtrl, _ := osc.New(src)
+	if _, err := srcCtrl.GetGeneration(dir, models.BackupGeneration{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	objList, err := osc.BackupObjects(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flt, err := filtering.FromParams(&models.ObjectFilterParams{Path: "docs/", PathExcludeYn: "n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newMemFS()
+	dst.put("restored/docs/a.txt", "existing", t0.Add(time.Hour))
+	dst.put("restored/docs/b.txt", "existing", t0.Add(time.Hour))
+	dstCtrl, _ := osc.New(dst)
+
+	opts := osc.RestoreOptions{Filter: flt, TargetPrefix: "/restored/", Overwrite: models.OverwriteNewer}
+	if err := dstCtrl.Restore(objList, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := dst.get("restored/docs/a.txt"); data != "existing" {
+		t.Errorf("older backup copy must not overwrite, got %q", data)
+	}
+	if data, _ := dst.get("restored/docs/b.txt"); data != "b" {
+		t.Errorf("newer backup copy must overwrite, got %q", data)
+	}
+	if _, ok := dst.get("restored/images/c.png"); ok {
+		t.Error("object outside the filter must not be restored")
+	}
+}
+
+func TestDirObjectsKeepBucketKeys(t *testing.T) {
+	src := newMemFS()
+	src.put("docs/a.txt", "a", time.Now())
+	srcCtrl, _ := osc.New(src)
+
+	dir := t.TempDir()
+	if err := srcCtrl.MGet(dir, &filtering.ObjectFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	objList, err := osc.DirObjects(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objList) != 1 || objList[0].Key != "docs/a.txt" {
+		t.Fatalf("objects = %+v, want the key of the backed up object", objList)
+	}
+
+	dst := newMemFS()
+	dstCtrl, _ := osc.New(dst)
+	opts := osc.RestoreOptions{Filter: &filtering.ObjectFilter{Exact: []string{"docs/a.txt"}}}
+	if err := dstCtrl.Restore(objList, opts); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := dst.get("docs/a.txt"); data != "a" {
+		t.Errorf("a filter on the original key must match, restored %q", data)
+	}
+}
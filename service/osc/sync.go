@@ -0,0 +1,401 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+)
+
+// syncOp is a planned change of a sync run.
+type syncOp struct {
+	key      string
+	action   models.SyncAction
+	conflict bool
+	size     int64
+}
+
+// Sync synchronises the objects selected by flt between src and dst in both directions.
+//
+// state holds the objects that were in sync after the previous run. An object
+// changed on one side only is copied to the other side, and an object deleted
+// on one side is deleted on the other side when that file system can delete.
+// An object changed on both sides is a conflict resolved by policy. On return
+// state is replaced by the objects that are in sync now.
+//
+// Only the key conditions of flt decide whether an object is missing: an object
+// whose size or modification time moved out of flt is still on its side and is
+// never taken for a deletion. Such an object is synchronised while its version
+// on either side matches flt.
+func (src *OSController) Sync(dst *OSController, state *models.SyncState, policy models.ConflictPolicy, flt *filtering.ObjectFilter) (*models.SyncReport, error) {
+	report := &models.SyncReport{
+		Conflict:  policy,
+		StartedAt: time.Now(),
+		Items:     []models.SyncItem{},
+	}
+
+	if err := dst.osfs.CreateBucket(); err != nil {
+		src.logWrite("Error", "CreateBucket error", err)
+		return nil, err
+	}
+	srcObjList, err := src.ObjectListWithFilter(keyFilter(flt))
+	if err != nil {
+		src.logWrite("Error", "source objectList error", err)
+		return nil, err
+	}
+	dstObjList, err := dst.ObjectListWithFilter(keyFilter(flt))
+	if err != nil {
+		src.logWrite("Error", "target objectList error", err)
+		return nil, err
+	}
+
+	_, srcDeletes := src.osfs.(DeletableOSFS)
+	_, dstDeletes := dst.osfs.(DeletableOSFS)
+	inScope := func(obj *models.Object) bool {
+		return filtering.MatchCandidate(flt, filtering.Candidate{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified})
+	}
+	ops, unchanged := planSync(objectMap(srcObjList), objectMap(dstObjList), state.Objects, policy, srcDeletes, dstDeletes, inScope)
+	report.Summary.Unchanged = unchanged
+
+	var totalSize int64
+	for _, op := range ops {
+		totalSize += op.size
+	}
+	src.progress.Skip(int64(unchanged))
+	if err := src.quota.Plan(int64(len(ops)), totalSize); err != nil {
+		src.logWrite("Error", "transfer quota", err)
+		return nil, err
+	}
+	src.progress.AddTotal(int64(len(ops)), totalSize)
+
+	failed := src.runSync(dst, ops, report)
+	if err := src.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// list again, the copies changed the versions on the receiving side
+	srcObjList, err = src.ObjectListWithFilter(keyFilter(flt))
+	if err != nil {
+		return nil, err
+	}
+	dstObjList, err = dst.ObjectListWithFilter(keyFilter(flt))
+	if err != nil {
+		return nil, err
+	}
+	state.Objects = syncedState(objectMap(srcObjList), objectMap(dstObjList), state.Objects, failed)
+	state.LastSync = report.StartedAt
+
+	report.FinishedAt = time.Now()
+	src.logWrite("Info", fmt.Sprintf("Sync done: %d to target, %d to source, %d deleted on target, %d deleted on source, %d conflicts, %d failed",
+		report.Summary.CopiedToTarget, report.Summary.CopiedToSource, report.Summary.DeletedOnTarget,
+		report.Summary.DeletedOnSource, report.Summary.Conflicts, report.Summary.Failed), nil)
+	if report.Summary.Failed > 0 {
+		return report, fmt.Errorf("%d of %d changes could not be synchronised", report.Summary.Failed, len(ops))
+	}
+	return report, nil
+}
+
+// planSync decides the change of every object and returns the changes and the number of unchanged objects.
+// Objects for which inScope holds on no side are left alone.
+func planSync(srcObjs, dstObjs map[string]*models.Object, prev map[string]models.SyncEntry, policy models.ConflictPolicy, srcDeletes, dstDeletes bool, inScope func(*models.Object) bool) ([]syncOp, int) {
+	keys := map[string]bool{}
+	for key := range srcObjs {
+		keys[key] = true
+	}
+	for key := range dstObjs {
+		keys[key] = true
+	}
+
+	ops := []syncOp{}
+	unchanged := 0
+	for key := range keys {
+		a, b := srcObjs[key], dstObjs[key]
+		if !(a != nil && inScope(a)) && !(b != nil && inScope(b)) {
+			continue
+		}
+		entry, known := prev[key]
+
+		switch {
+		case a != nil && b != nil:
+			aChanged := !known || !sameVersion(entry.Source, a)
+			bChanged := !known || !sameVersion(entry.Target, b)
+			switch {
+			case aChanged && bChanged:
+				if sameContent(a, b) {
+					unchanged++
+					continue
+				}
+				ops = append(ops, resolveConflict(key, a, b, policy))
+			case aChanged:
+				ops = append(ops, syncOp{key: key, action: models.SyncCopyToTarget, size: a.Size})
+			case bChanged:
+				ops = append(ops, syncOp{key: key, action: models.SyncCopyToSource, size: b.Size})
+			default:
+				unchanged++
+			}
+		case a != nil:
+			// deleted on the target unless it is new or was changed since, then it is copied
+			if known && sameVersion(entry.Source, a) && srcDeletes {
+				ops = append(ops, syncOp{key: key, action: models.SyncDeleteOnSource})
+				continue
+			}
+			ops = append(ops, syncOp{key: key, action: models.SyncCopyToTarget, size: a.Size})
+		case b != nil:
+			if known && sameVersion(entry.Target, b) && dstDeletes {
+				ops = append(ops, syncOp{key: key, action: models.SyncDeleteOnTarget})
+				continue
+			}
+			ops = append(ops, syncOp{key: key, action: models.SyncCopyToSource, size: b.Size})
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].key < ops[j].key })
+	return ops, unchanged
+}
+
+// resolveConflict returns the change for an object changed on both sides.
+func resolveConflict(key string, a, b *models.Object, policy models.ConflictPolicy) syncOp {
+	op := syncOp{key: key, conflict: true, action: models.SyncCopyToTarget, size: a.Size}
+	switch policy {
+	case models.ConflictSource:
+	case models.ConflictKeepBoth:
+		op.action = models.SyncKeepBoth
+		op.size = a.Size + 2*b.Size
+	default:
+		if b.LastModified.After(a.LastModified) {
+			op.action = models.SyncCopyToSource
+			op.size = b.Size
+		}
+	}
+	return op
+}
+
+// runSync applies ops and returns the keys whose change failed.
+func (src *OSController) runSync(dst *OSController, ops []syncOp, report *models.SyncReport) map[string]bool {
+	jobs := make(chan syncOp, len(ops))
+	resultChan := make(chan models.SyncItem, len(ops))
+
+	var srcDeletes, dstDeletes []string
+	for _, op := range ops {
+		switch op.action {
+		case models.SyncDeleteOnSource:
+			srcDeletes = append(srcDeletes, op.key)
+		case models.SyncDeleteOnTarget:
+			dstDeletes = append(dstDeletes, op.key)
+		default:
+			jobs <- op
+		}
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < src.threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for op := range jobs {
+				item := models.SyncItem{Key: op.key, Action: op.action, Conflict: op.conflict}
+				if err := src.applySync(dst, op); err != nil {
+					item.Error = err.Error()
+				}
+				resultChan <- item
+			}
+		}()
+	}
+
+	for _, del := range []struct {
+		c      *OSController
+		keys   []string
+		action models.SyncAction
+	}{{src, srcDeletes, models.SyncDeleteOnSource}, {dst, dstDeletes, models.SyncDeleteOnTarget}} {
+		if len(del.keys) == 0 {
+			continue
+		}
+		err := src.ctx.Err()
+		if err == nil {
+			err = del.c.osfs.(DeletableOSFS).DeleteObjects(del.keys)
+		}
+		for _, key := range del.keys {
+			item := models.SyncItem{Key: key, Action: del.action}
+			if err != nil {
+				item.Error = err.Error()
+			}
+			resultChan <- item
+		}
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	failed := map[string]bool{}
+	for item := range resultChan {
+		report.Items = append(report.Items, item)
+		if item.Conflict {
+			report.Summary.Conflicts++
+		}
+		if item.Error != "" {
+			failed[item.Key] = true
+			report.Summary.Failed++
+			if src.ctx.Err() == nil {
+				src.logWrite("Error", fmt.Sprintf("Sync failed: %s (%s)", item.Key, item.Action), errors.New(item.Error))
+				src.progress.Fail(item.Key, errors.New(item.Error))
+			}
+			continue
+		}
+
+		switch item.Action {
+		case models.SyncCopyToTarget, models.SyncKeepBoth:
+			report.Summary.CopiedToTarget++
+		case models.SyncCopyToSource:
+			report.Summary.CopiedToSource++
+		case models.SyncDeleteOnTarget:
+			report.Summary.DeletedOnTarget++
+		case models.SyncDeleteOnSource:
+			report.Summary.DeletedOnSource++
+		}
+		src.logWrite("Info", fmt.Sprintf("Sync success: %s (%s)", item.Key, item.Action), nil)
+		src.progress.ObjectDone(item.Key)
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool { return report.Items[i].Key < report.Items[j].Key })
+	return failed
+}
+
+// applySync copies one object for op.
+func (src *OSController) applySync(dst *OSController, op syncOp) error {
+	if err := src.ctx.Err(); err != nil {
+		return err
+	}
+
+	switch op.action {
+	case models.SyncCopyToTarget:
+		return src.transfer(src, dst, op.key, op.key)
+	case models.SyncCopyToSource:
+		return src.transfer(dst, src, op.key, op.key)
+	case models.SyncKeepBoth:
+		// the target version is kept on both sides under a conflict name,
+		// then the source version wins the original key
+		ck := conflictKey(op.key, time.Now())
+		if err := src.transfer(dst, src, op.key, ck); err != nil {
+			return err
+		}
+		if err := src.transfer(dst, dst, op.key, ck); err != nil {
+			return err
+		}
+		return src.transfer(src, dst, op.key, op.key)
+	}
+	return fmt.Errorf("unknown sync action %s", op.action)
+}
+
+// transfer copies key of from to toKey of to.
+func (src *OSController) transfer(from, to *OSController, key, toKey string) error {
+	r, err := from.osfs.Open(key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := to.osfs.Create(toKey)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src.reader(r)); err != nil {
+		closeWithError(w, err)
+		return err
+	}
+	return w.Close()
+}
+
+// conflictKey returns the key the losing version of a conflict is kept under.
+func conflictKey(key string, now time.Time) string {
+	ext := path.Ext(key)
+	if strings.Contains(ext, "/") {
+		ext = ""
+	}
+	return fmt.Sprintf("%s.conflict-%s%s", strings.TrimSuffix(key, ext), now.UTC().Format("20060102T150405Z"), ext)
+}
+
+// syncedState returns the objects that are on both sides after a run.
+// Objects whose change failed keep their previous entry, so the next run sees the same change again.
+func syncedState(srcObjs, dstObjs map[string]*models.Object, prev map[string]models.SyncEntry, failed map[string]bool) map[string]models.SyncEntry {
+	state := map[string]models.SyncEntry{}
+	for key, a := range srcObjs {
+		if failed[key] {
+			if entry, ok := prev[key]; ok {
+				state[key] = entry
+			}
+			continue
+		}
+		b, ok := dstObjs[key]
+		if !ok {
+			continue
+		}
+		state[key] = models.SyncEntry{Source: syncVersion(a), Target: syncVersion(b)}
+	}
+	for key, entry := range prev {
+		if failed[key] {
+			state[key] = entry
+		}
+	}
+	return state
+}
+
+// objectMap indexes objList by key and leaves out directory markers.
+func objectMap(objList []*models.Object) map[string]*models.Object {
+	objs := make(map[string]*models.Object, len(objList))
+	for _, obj := range objList {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		objs[obj.Key] = obj
+	}
+	return objs
+}
+
+func syncVersion(obj *models.Object) models.SyncVersion {
+	return models.SyncVersion{Size: obj.Size, ETag: obj.ETag, LastModified: obj.LastModified}
+}
+
+// sameVersion reports whether obj is still the version recorded in v.
+func sameVersion(v models.SyncVersion, obj *models.Object) bool {
+	if v.Size != obj.Size || !v.LastModified.Equal(obj.LastModified) {
+		return false
+	}
+	oldSum, oldOk := comparableETag(v.ETag)
+	newSum, newOk := comparableETag(obj.ETag)
+	return !oldOk || !newOk || oldSum == newSum
+}
+
+// sameContent reports whether a and b are known to hold the same data.
+func sameContent(a, b *models.Object) bool {
+	if a.Size != b.Size {
+		return false
+	}
+	aSum, aOk := comparableETag(a.ETag)
+	bSum, bOk := comparableETag(b.ETag)
+	return aOk && bOk && aSum == bSum
+}
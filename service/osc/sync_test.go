@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestSync(t *testing.T) {
+	now := time.Now()
+	src := newMemFS()
+	dst := newMemFS()
+	src.put("a.txt", "a1", now.Add(-time.Hour))
+	src.put("b.txt", "b1", now.Add(-time.Hour))
+	dst.put("c.txt", "c1", now.Add(-time.Hour))
+	srcCtrl, _ := osc.New(src)
+	dstCtrl, _ := osc.New(dst)
+
+	state := &models.SyncState{Objects: map[string]models.SyncEntry{}}
+	report, err := srcCtrl.Sync(dstCtrl, state, models.ConflictNewest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Summary.CopiedToTarget != 2 || report.Summary.CopiedToSource != 1 || len(state.Objects) != 3 {
+		t.Fatalf("first run = %+v, %d in sync", report.Summary, len(state.Objects))
+	}
+
+	src.put("a.txt", "a2", now.Add(time.Hour))
+	dst.DeleteObjects([]string{"b.txt"})
+	src.put("c.txt", "c-source", now.Add(time.Hour))
+	dst.put("c.txt", "c-target", now.Add(2*time.Hour))
+
+	report, err = srcCtrl.Sync(dstCtrl, state, models.ConflictNewest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.SyncSummary{CopiedToTarget: 1, CopiedToSource: 1, DeletedOnSource: 1, Conflicts: 1}
+	if report.Summary != want {
+		t.Fatalf("second run = %+v, want %+v", report.Summary, want)
+	}
+	if data, _ := dst.get("a.txt"); data != "a2" {
+		t.Errorf("target a.txt = %q", data)
+	}
+	if _, ok := src.get("b.txt"); ok {
+		t.Error("b.txt deleted on the target must be deleted on the source")
+	}
+	if data, _ := src.get("c.txt"); data != "c-target" {
+		t.Errorf("newest version must win, source c.txt = %q", data)
+	}
+
+	src.put("c.txt", "c-mine", now.Add(3*time.Hour))
+	dst.put("c.txt", "c-theirs", now.Add(3*time.Hour))
+	if _, err := srcCtrl.Sync(dstCtrl, state, models.ConflictKeepBoth, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, fs := range []*memFS{src, dst} {
+		objList, _ := fs.ObjectList()
+		kept := false
+		for _, obj := range objList {
+			if strings.HasPrefix(obj.Key, "c.conflict-") && strings.HasSuffix(obj.Key, ".txt") {
+				data, _ := fs.get(obj.Key)
+				kept = data == "c-theirs"
+			}
+		}
+		if data, _ := fs.get("c.txt"); data != "c-mine" || !kept {
+			t.Errorf("keep-both must keep the source version and a conflict copy, c.txt = %q", data)
+		}
+	}
+}
+
+func TestSyncSizeFilterDoesNotDelete(t *testing.T) {
+	now := time.Now()
+	src := newMemFS()
+	dst := newMemFS()
+	src.put("a.txt", "small", now.Add(-time.Hour))
+	srcCtrl, _ := osc.New(src)
+	dstCtrl, _ := osc.New(dst)
+
+	maxSize := 10.0
+	flt := &filtering.ObjectFilter{MaxSize: &maxSize}
+	state := &models.SyncState{Objects: map[string]models.SyncEntry{}}
+	if _, err := srcCtrl.Sync(dstCtrl, state, models.ConflictNewest, flt); err != nil {
+		t.Fatal(err)
+	}
+
+	// the source version grows out of the filter, the target copy is unchanged
+	src.put("a.txt", "grown beyond the size filter", now)
+	report, err := srcCtrl.Sync(dstCtrl, state, models.ConflictNewest, flt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Summary.DeletedOnTarget != 0 || report.Summary.DeletedOnSource != 0 {
+		t.Fatalf("an object filtered out by size must not be deleted: %+v", report.Summary)
+	}
+	if _, ok := dst.get("a.txt"); !ok {
+		t.Error("target a.txt was deleted")
+	}
+	if _, ok := src.get("a.txt"); !ok {
+		t.Error("source a.txt was deleted")
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/transform"
+)
+
+// transformLog collects the transformed objects of a controller.
+type transformLog struct {
+	mu    sync.Mutex
+	items []models.TransformItem
+}
+
+// WithTransforms transforms the objects matching rules while they are copied.
+func WithTransforms(rules *transform.Rules) Option {
+	return func(o *OSController) {
+		o.transforms = rules
+	}
+}
+
+// Transformed returns the objects transformed by Copy.
+func (osc *OSController) Transformed() []models.TransformItem {
+	osc.transformed.mu.Lock()
+	defer osc.transformed.mu.Unlock()
+
+	items := make([]models.TransformItem, len(osc.transformed.items))
+	copy(items, osc.transformed.items)
+	return items
+}
+
+// transformObject copies obj through pipeline. The size of the source cannot
+// be compared with the results, so the written sizes and checksums are recorded instead.
+func (src *OSController) transformObject(dst *OSController, obj models.Object, pipeline *transform.Pipeline) error {
+	item := models.TransformItem{
+		SourceKey:  obj.Key,
+		SourceSize: obj.Size,
+		Rule:       pipeline.Rule,
+	}
+
+	outputs, err := func() ([]models.TransformOutput, error) {
+		srcFile, err := src.osfs.Open(obj.Key)
+		if err != nil {
+			return nil, err
+		}
+		defer srcFile.Close()
+		return pipeline.Run(src.TargetKey(obj.Key), src.reader(srcFile), dst.osfs.Create)
+	}()
+	if err != nil {
+		err = fmt.Errorf("transform %s: %w", obj.Key, err)
+		item.Error = err.Error()
+	}
+	item.Outputs = outputs
+
+	src.transformed.mu.Lock()
+	src.transformed.items = append(src.transformed.items, item)
+	src.transformed.mu.Unlock()
+	return err
+}
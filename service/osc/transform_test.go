@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/transform"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestCopyTransforms(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := newMemFS()
+	src.put("data/users.csv", "id,name\n1,\"Kim, Ana\"\n2,Lee\n", t0)
+	src.put("logs/app.log", "first\nsecond\nthird line\n", t0)
+	src.put("plain.txt", "untouched", t0)
+	dst := newMemFS()
+
+	rules, err := transform.Compile([]models.TransformRule{
+		{Match: "*.csv", Steps: []models.TransformStep{{Type: models.TransformCSVToJSONL}, {Type: models.TransformGzip}}},
+		{Match: "logs/*", Steps: []models.TransformStep{{Type: models.TransformSplit, PartSize: 14, Lines: true}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srcCtrl, _ := osc.New(src, osc.WithTransforms(rules))
+	dstCtrl, _ := osc.New(dst)
+	if err := srcCtrl.Copy(dstCtrl, &filtering.ObjectFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	gz, _ := dst.get("data/users.jsonl.gz")
+	zr, err := gzip.NewReader(strings.NewReader(gz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonl, _ := io.ReadAll(zr)
+	if want := "{\"id\":\"1\",\"name\":\"Kim, Ana\"}\n{\"id\":\"2\",\"name\":\"Lee\"}\n"; string(jsonl) != want {
+		t.Errorf("users.jsonl = %q, want %q", jsonl, want)
+	}
+	for key, want := range map[string]string{
+		"logs/app.log.part-0001": "first\nsecond\n",
+		"logs/app.log.part-0002": "third line\n",
+		"plain.txt":              "untouched",
+	} {
+		if data, _ := dst.get(key); data != want {
+			t.Errorf("%s = %q, want %q", key, data, want)
+		}
+	}
+
+	items := srcCtrl.Transformed()
+	sort.Slice(items, func(i, j int) bool { return items[i].SourceKey < items[j].SourceKey })
+	if len(items) != 2 || items[0].SourceKey != "data/users.csv" || len(items[1].Outputs) != 2 {
+		t.Fatalf("transformed = %+v", items)
+	}
+	for _, item := range items {
+		for _, out := range item.Outputs {
+			data, _ := dst.get(out.Key)
+			sum := sha256.Sum256([]byte(data))
+			if out.Size != int64(len(data)) || out.SHA256 != hex.EncodeToString(sum[:]) {
+				t.Errorf("output %+v does not match the written object", out)
+			}
+		}
+	}
+}
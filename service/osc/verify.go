@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+)
+
+// Verify compares the objects of src and dst and reports the differences.
+//
+// Both sides are listed with the key conditions of the filter. The size and
+// modification time conditions select source objects only, and the target
+// objects of the source objects they leave out are left out too: copies are
+// not guaranteed to keep the size or modification time the filter saw.
+func (src *OSController) Verify(dst *OSController, flt *filtering.ObjectFilter) (*models.VerifyReport, error) {
+	report := &models.VerifyReport{
+		StartedAt: time.Now(),
+		Items:     []models.VerifyItem{},
+	}
+
+	srcAll, err := src.ObjectListWithFilter(keyFilter(flt))
+	if err != nil {
+		src.logWrite("Error", "source objectList error", err)
+		return nil, err
+	}
+
+	dstAll, err := dst.ObjectListWithFilter(keyFilter(flt))
+	if err != nil {
+		src.logWrite("Error", "target objectList error", err)
+		return nil, err
+	}
+
+	srcObjList := make([]*models.Object, 0, len(srcAll))
+	leftOut := map[string]bool{}
+	for _, obj := range srcAll {
+		if filtering.MatchCandidate(flt, filtering.Candidate{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified}) {
+			srcObjList = append(srcObjList, obj)
+		} else {
+			leftOut[obj.Key] = true
+		}
+	}
+	dstObjList := make([]*models.Object, 0, len(dstAll))
+	for _, obj := range dstAll {
+		if !leftOut[obj.Key] {
+			dstObjList = append(dstObjList, obj)
+		}
+	}
+
+	CompareObjectList(report, srcObjList, dstObjList)
+	report.FinishedAt = time.Now()
+
+	src.logWrite("Info", fmt.Sprintf("Verify done: matched %d, missing on target %d, missing on source %d, size mismatch %d, checksum mismatch %d",
+		report.Summary.Matched, report.Summary.MissingOnTarget, report.Summary.MissingOnSource,
+		report.Summary.SizeMismatch, report.Summary.ChecksumMismatch), nil)
+	return report, nil
+}
+
+// CompareObjectList fills the summary and items of report by comparing two object lists by key.
+func CompareObjectList(report *models.VerifyReport, srcObjList, dstObjList []*models.Object) {
+	dstMap := make(map[string]*models.Object, len(dstObjList))
+	for _, obj := range dstObjList {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		dstMap[obj.Key] = obj
+		report.Summary.TargetObjects++
+		report.Summary.TargetBytes += obj.Size
+	}
+
+	seen := make(map[string]bool, len(srcObjList))
+	for _, obj := range srcObjList {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		seen[obj.Key] = true
+		report.Summary.SourceObjects++
+		report.Summary.SourceBytes += obj.Size
+
+		dstObj, ok := dstMap[obj.Key]
+		if !ok {
+			report.Summary.MissingOnTarget++
+			report.Items = append(report.Items, models.VerifyItem{
+				Key:        obj.Key,
+				Result:     models.VerifyMissingOnTarget,
+				SourceSize: obj.Size,
+				SourceETag: obj.ETag,
+			})
+			continue
+		}
+
+		item := models.VerifyItem{
+			Key:        obj.Key,
+			SourceSize: obj.Size,
+			TargetSize: dstObj.Size,
+			SourceETag: obj.ETag,
+			TargetETag: dstObj.ETag,
+		}
+
+		if obj.Size != dstObj.Size {
+			report.Summary.SizeMismatch++
+			item.Result = models.VerifySizeMismatch
+			report.Items = append(report.Items, item)
+			continue
+		}
+
+		srcSum, srcOk := comparableETag(obj.ETag)
+		dstSum, dstOk := comparableETag(dstObj.ETag)
+		if !srcOk || !dstOk {
+			report.Summary.ChecksumSkipped++
+			report.Summary.Matched++
+			continue
+		}
+
+		if srcSum != dstSum {
+			report.Summary.ChecksumMismatch++
+			item.Result = models.VerifyChecksumMismatch
+			report.Items = append(report.Items, item)
+			continue
+		}
+		report.Summary.Matched++
+	}
+
+	for key, obj := range dstMap {
+		if seen[key] {
+			continue
+		}
+		report.Summary.MissingOnSource++
+		report.Items = append(report.Items, models.VerifyItem{
+			Key:        key,
+			Result:     models.VerifyMissingOnSource,
+			TargetSize: obj.Size,
+			TargetETag: obj.ETag,
+		})
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		return report.Items[i].Key < report.Items[j].Key
+	})
+}
+
+// comparableETag returns the normalized ETag when it is a plain MD5 hash.
+//
+// Multipart ETags ("<hash>-<parts>") and empty values depend on how the object
+// was uploaded, so they cannot be compared across providers.
+func comparableETag(etag string) (string, bool) {
+	etag = strings.ToLower(strings.Trim(strings.TrimSpace(etag), `"`))
+	if len(etag) != 32 {
+		return "", false
+	}
+	for _, c := range etag {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return "", false
+		}
+	}
+	return etag, true
+}
+
+// keyFilter keeps only the key conditions of flt.
+func keyFilter(flt *filtering.ObjectFilter) *filtering.ObjectFilter {
+	if flt == nil {
+		return nil
+	}
+	return &filtering.ObjectFilter{
+		Path:             flt.Path,
+		PathExcludeYn:    flt.PathExcludeYn,
+		Contains:         flt.Contains,
+		ContainExcludeYn: flt.ContainExcludeYn,
+		Suffixes:         flt.Suffixes,
+		Exact:            flt.Exact,
+		Regex:            flt.Regex,
+	}
+}
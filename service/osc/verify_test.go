@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package osc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+)
+
+func TestCompareObjectList(t *testing.T) {
+	src := []*models.Object{
+		{Key: "dir/", Size: 0},
+		{Key: "dir/same.txt", Size: 10, ETag: `"0cc175b9c0f1b6a831c399e269772661"`},
+		{Key: "dir/size.txt", Size: 10},
+		{Key: "dir/hash.txt", Size: 5, ETag: "0cc175b9c0f1b6a831c399e269772661"},
+		{Key: "dir/multipart.bin", Size: 7, ETag: "0cc175b9c0f1b6a831c399e269772661-2"},
+		{Key: "dir/only-source.txt", Size: 3},
+	}
+	dst := []*models.Object{
+		{Key: "dir/same.txt", Size: 10, ETag: "0CC175B9C0F1B6A831C399E269772661"},
+		{Key: "dir/size.txt", Size: 11},
+		{Key: "dir/hash.txt", Size: 5, ETag: "92eb5ffee6ae2fec3ad71c777531578f"},
+		{Key: "dir/multipart.bin", Size: 7, ETag: "0cc175b9c0f1b6a831c399e269772661"},
+		{Key: "dir/only-target.txt", Size: 4},
+	}
+
+	report := &models.VerifyReport{}
+	osc.CompareObjectList(report, src, dst)
+
+	want := models.VerifySummary{
+		SourceObjects:    5,
+		TargetObjects:    5,
+		SourceBytes:      35,
+		TargetBytes:      37,
+		Matched:          2,
+		MissingOnSource:  1,
+		MissingOnTarget:  1,
+		SizeMismatch:     1,
+		ChecksumMismatch: 1,
+		ChecksumSkipped:  1,
+	}
+	if report.Summary != want {
+		t.Fatalf("summary = %+v, want %+v", report.Summary, want)
+	}
+
+	results := map[string]models.VerifyResult{}
+	for _, item := range report.Items {
+		results[item.Key] = item.Result
+	}
+	expected := map[string]models.VerifyResult{
+		"dir/size.txt":        models.VerifySizeMismatch,
+		"dir/hash.txt":        models.VerifyChecksumMismatch,
+		"dir/only-source.txt": models.VerifyMissingOnTarget,
+		"dir/only-target.txt": models.VerifyMissingOnSource,
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("items = %+v", report.Items)
+	}
+	for key, result := range expected {
+		if results[key] != result {
+			t.Errorf("%s: got %q, want %q", key, results[key], result)
+		}
+	}
+
+	if report.Passed() {
+		t.Error("report with differences must not pass")
+	}
+}
+
+func TestVerifyFilterSelectsBothSides(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	src := newMemFS()
+	dst := newMemFS()
+	src.put("old.txt", "old", old)
+	src.put("new.txt", "new", time.Now())
+	// copies get the time they were written
+	dst.put("old.txt", "old", time.Now())
+	dst.put("new.txt", "new", time.Now())
+	srcCtrl, _ := osc.New(src)
+	dstCtrl, _ := osc.New(dst)
+
+	after := time.Now().Add(-time.Hour)
+	report, err := srcCtrl.Verify(dstCtrl, &filtering.ObjectFilter{ModifiedAfter: &after})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Passed() || report.Summary.Matched != 1 || report.Summary.MissingOnSource != 0 {
+		t.Fatalf("a target object whose source is filtered out must not be missing on source: %+v", report.Summary)
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rdbc
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// tableJob is the rows of a table, or of a primary key range of it, exported by a worker.
+type tableJob struct {
+	table string
+	chunk int
+	// where selects the rows of a chunk, empty for the whole table
+	where string
+	// spool is the file the rows were exported into
+	spool string
+	err   error
+}
+
+// orderTables orders tables so that the tables referenced by a table come first.
+// Tables in a reference cycle keep their order at the end.
+func orderTables(tables []string, deps map[string][]string) []string {
+	if len(deps) == 0 {
+		return tables
+	}
+
+	ordered := make([]string, 0, len(tables))
+	placed := map[string]bool{}
+	pending := slices.Clone(tables)
+	for len(pending) > 0 {
+		progressed := false
+		for i := 0; i < len(pending); {
+			if !referencedDone(pending[i], deps, tables, placed) {
+				i++
+				continue
+			}
+			placed[pending[i]] = true
+			ordered = append(ordered, pending[i])
+			pending = slices.Delete(pending, i, i+1)
+			progressed = true
+		}
+		if !progressed {
+			log.Warn().Msgf("tables %v reference each other, their foreign keys may fail", pending)
+			return append(ordered, pending...)
+		}
+	}
+	return ordered
+}
+
+// referencedDone reports whether the tables referenced by table are done,
+// ignoring references to itself and to tables outside of tables.
+func referencedDone(table string, deps map[string][]string, tables []string, done map[string]bool) bool {
+	for _, ref := range deps[table] {
+		if ref != table && !done[ref] && slices.Contains(tables, ref) {
+			return false
+		}
+	}
+	return true
+}
+
+// tableChunks returns the conditions of the chunks of a table, with the rows
+// the selection takes, or a single condition when the table is exported whole.
+func (rdb *RDBController) tableChunks(dbName, table string) ([]string, error) {
+	where, err := rdb.rowCondition(dbName, table)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := rdb.Client.(ChunkRDBMS)
+	// a number of rows is drawn once from the whole table
+	if !ok || rdb.chunkRows <= 0 || rdb.sampleRows() {
+		return []string{where}, nil
+	}
+	chunks, err := c.TableChunks(dbName, table, rdb.chunkRows)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return []string{where}, nil
+	}
+	for i := range chunks {
+		chunks[i] = andWhere(chunks[i], where)
+	}
+	log.Info().Msgf("table %s is exported in %d chunks", table, len(chunks))
+	return chunks, nil
+}
+
+// dispatch runs the jobs of tables on workers goroutines of work. With deps,
+// a table starts once the tables it references are done. finished, when set,
+// is called in the calling goroutine for each job done, also after a failure
+// so that it can clean up.
+func (rdb *RDBController) dispatch(dbName string, tables []string, deps map[string][]string, workers int,
+	work func(jobs <-chan tableJob, done chan<- tableJob), finished func(job tableJob, tableDone bool) error) error {
+	rdb.progress.AddTotal(int64(len(tables)), 0)
+
+	jobs := make(chan tableJob)
+	done := make(chan tableJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work(jobs, done)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	pending := slices.Clone(tables)
+	remaining := map[string]int{}
+	completed := map[string]bool{}
+	var queue []tableJob
+	running := 0
+	var firstErr error
+
+	// release queues the jobs of the tables whose references are done
+	release := func() {
+		for i := 0; i < len(pending) && firstErr == nil; {
+			table := pending[i]
+			// a reference cycle is broken once nothing else can run
+			stuck := running == 0 && len(queue) == 0 && len(remaining) == len(completed)
+			if !referencedDone(table, deps, tables, completed) && !stuck {
+				i++
+				continue
+			}
+			pending = slices.Delete(pending, i, i+1)
+
+			chunks, err := rdb.tableChunks(dbName, table)
+			if err != nil {
+				firstErr = err
+				rdb.progress.Fail(table, err)
+				return
+			}
+			remaining[table] = len(chunks)
+			for n, where := range chunks {
+				queue = append(queue, tableJob{table: table, chunk: n, where: where})
+			}
+			i = 0
+		}
+	}
+
+	release()
+	for {
+		if firstErr == nil {
+			firstErr = rdb.ctx.Err()
+		}
+		var send chan<- tableJob
+		var next tableJob
+		if firstErr == nil && len(queue) > 0 {
+			send = jobs
+			next = queue[0]
+		}
+		if send == nil && running == 0 {
+			break
+		}
+
+		select {
+		case send <- next:
+			queue = queue[1:]
+			running++
+		case job := <-done:
+			running--
+			if job.err != nil {
+				if firstErr == nil {
+					firstErr = job.err
+				}
+				rdb.progress.Fail(job.table, job.err)
+				continue
+			}
+			remaining[job.table]--
+			tableDone := remaining[job.table] == 0
+			if tableDone {
+				completed[job.table] = true
+				rdb.progress.ObjectDone(job.table)
+			}
+			if finished != nil {
+				if err := finished(job, tableDone); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+			release()
+		}
+	}
+
+	close(jobs)
+	for range done {
+	}
+	return firstErr
+}
+
+// spoolInserts exports the rows of tables on the workers into spool files and
+// appends them to w in table order as the tables are done.
+func (rdb *RDBController) spoolInserts(dbName string, tables []string, workers int, w io.Writer) error {
+	spools := map[string][]string{}
+	defer func() {
+		for _, files := range spools {
+			for _, file := range files {
+				if file != "" {
+					os.Remove(file)
+				}
+			}
+		}
+	}()
+
+	next := 0
+	completed := map[string]bool{}
+	var failed error
+	finished := func(job tableJob, tableDone bool) error {
+		files := spools[job.table]
+		if len(files) <= job.chunk {
+			files = append(files, make([]string, job.chunk+1-len(files))...)
+		}
+		files[job.chunk] = job.spool
+		spools[job.table] = files
+		if tableDone {
+			completed[job.table] = true
+		}
+
+		for ; failed == nil && next < len(tables) && completed[tables[next]]; next++ {
+			for i, file := range spools[tables[next]] {
+				if failed = appendFile(w, file); failed != nil {
+					return failed
+				}
+				os.Remove(file)
+				spools[tables[next]][i] = ""
+			}
+		}
+		return nil
+	}
+
+	return rdb.dispatch(dbName, tables, nil, workers, func(jobs <-chan tableJob, done chan<- tableJob) {
+		for job := range jobs {
+			rdb.progress.SetCurrent(job.table)
+			job.spool, job.err = rdb.spoolJob(dbName, job)
+			done <- job
+		}
+	}, finished)
+}
+
+// spoolJob exports the rows of a job into a new spool file and returns its name.
+func (rdb *RDBController) spoolJob(dbName string, job tableJob) (string, error) {
+	file, err := os.CreateTemp(rdb.spoolDir, "rdbc-*.sql.spool")
+	if err != nil {
+		return "", err
+	}
+
+	bw := bufio.NewWriter(file)
+	err = rdb.insert(dbName, job, func(stmt string) error {
+		return sqlWrite(bw, stmt)
+	})
+	if err == nil {
+		err = bw.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// appendFile copies the content of the file name to w.
+func appendFile(w io.Writer, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
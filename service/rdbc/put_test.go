@@ -0,0 +1,39 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rdbc
+
+import "testing"
+
+func TestStatementEnds(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want bool
+	}{
+		{"CREATE TABLE `t` (`id` int);\n\n", true},
+		{"-- snapshot\n\n", false},
+		{"CREATE PROCEDURE `p`()\nBEGIN\n  SELECT 1;\n\n", true},
+		{"CREATE PROCEDURE `p`()\nBEGIN\n\n", false},
+		{"COPY public.t (id, name) FROM stdin;\n1\ta;\n\n", false},
+		{"COPY public.t (id, name) FROM stdin;\n1\ta\n\n2\t\n\\.\n\n", true},
+		{"\\connect app\n\nCOPY public.t (id) FROM stdin;\n1\n\\.\n\n", true},
+		{"\\connect app\n\nCOPY public.t (id) FROM stdin;\n1;\n\n", false},
+	}
+	for _, tt := range tests {
+		if got := statementEnds(tt.stmt); got != tt.want {
+			t.Errorf("statementEnds(%q) = %v, want %v", tt.stmt, got, tt.want)
+		}
+	}
+}
@@ -16,10 +16,17 @@ limitations under the License.
 package rdbc
 
 import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"regexp"
 	"strings"
 
 	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/progress"
 	"github.com/cloud-barista/mc-data-manager/pkg/rdbms/mysql/diagnostics"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -28,7 +35,8 @@ import (
 type EngineType string
 
 const (
-	Mysql EngineType = "mysql"
+	Mysql    EngineType = "mysql"
+	Postgres EngineType = "postgres"
 )
 
 // rdbms interface
@@ -45,14 +53,150 @@ type RDBMS interface {
 	ListTable(dbName string, dst *[]string) error
 	ShowCreateDBSql(dbName string, dbCreateSql *string) error
 	ShowCreateTableSql(dbName, tableName string, tableCreateSql *string) error
-	GetInsert(dbName, tableName string, insertSql *[]string) error
+	ListSchemaObjects(dbName string) ([]models.SchemaObject, error)
+	GetInsert(dbName, tableName string, emit func(insertSql string) error) error
 	Diagnose(schema string, time int64) (diagnostics.TimedResult, error)
 }
 
+// ContextRDBMS is implemented by databases whose queries can be cancelled.
+type ContextRDBMS interface {
+	SetContext(ctx context.Context)
+}
+
+// SessionRDBMS is implemented by databases that can run statements on a single connection.
+type SessionRDBMS interface {
+	Session(fn func(exec func(query string) error) error) error
+}
+
+// BatchRDBMS is implemented by databases that export rows as multi-row INSERTs.
+type BatchRDBMS interface {
+	SetMaxPacketSize(size int)
+}
+
+// ChecksRDBMS is implemented by databases that can turn off unique and foreign key checks in a session.
+type ChecksRDBMS interface {
+	SetDisableChecks(disable bool)
+}
+
+// RowRDBMS is implemented by databases that can read the rows of a table.
+type RowRDBMS interface {
+	ListColumn(dbName, tableName string) ([]models.Column, error)
+	GetRows(dbName, tableName string, columns []models.Column, where string, emit func(row []sql.NullString) error) error
+}
+
+// ForeignKeyRDBMS is implemented by databases that report the tables each table references.
+type ForeignKeyRDBMS interface {
+	TableDependencies(dbName string) (map[string][]string, error)
+}
+
+// WhereRDBMS is implemented by databases that can export the rows of a table matching a condition.
+type WhereRDBMS interface {
+	GetInsertWhere(dbName, tableName, where string, emit func(insertSql string) error) error
+}
+
+// ChunkRDBMS is implemented by databases that can split a table into primary key ranges.
+type ChunkRDBMS interface {
+	WhereRDBMS
+	TableChunks(dbName, tableName string, rows int64) ([]string, error)
+}
+
+// SampleRDBMS is implemented by databases that can select a random sample of the rows of a table.
+type SampleRDBMS interface {
+	// SampleCondition returns the condition that selects the sample among the rows matching where
+	SampleCondition(dbName, tableName, where string, sample models.RowSample) (string, error)
+}
+
+// BulkRDBMS is implemented by databases that can load rows in bulk.
+type BulkRDBMS interface {
+	LocalInfile() (bool, error)
+	LoadRows(exec func(query string) error, tableName string, columns []models.Column, rows func(emit func(row []sql.NullString) error) error) error
+}
+
+// DefinerRDBMS is implemented by databases whose objects run as a definer that can be replaced.
+type DefinerRDBMS interface {
+	SetDefiner(definer string)
+}
+
+// CharsetRDBMS is implemented by databases that can convert the character sets of their export.
+type CharsetRDBMS interface {
+	SetCharsetPolicy(policy models.CharsetPolicy)
+	CharsetIssues(dbName string) ([]models.CharsetIssue, error)
+}
+
+// UserRDBMS is implemented by databases that can export their users and grants.
+type UserRDBMS interface {
+	UserStatements(dbName string, users models.UserMigration) ([]string, error)
+}
+
+// SnapshotRDBMS is implemented by databases that can export from a consistent snapshot.
+type SnapshotRDBMS interface {
+	// Snapshot pins up to readers connections to one consistent view, used by the reads until Release
+	Snapshot(lock bool, readers int) (models.BinlogPosition, int, error)
+	Release() error
+}
+
+// EngineRDBMS is implemented by databases that report their engine; the others are MySQL.
+type EngineRDBMS interface {
+	Engine() string
+}
+
+// SchemaRDBMS is implemented by databases that can describe their tables for another engine.
+type SchemaRDBMS interface {
+	TableSchemas(dbName string) ([]models.TableSchema, error)
+}
+
+// TranslatorRDBMS is implemented by databases that can take the tables and rows of another engine.
+type TranslatorRDBMS interface {
+	TranslateSchema(engine, dbName string, tables []models.TableSchema, objects []models.SchemaObject) (*models.TranslationReport, error)
+	LoadTranslated(exec func(query string) error, table models.TableTranslation, rows func(emit func(row []sql.NullString) error) error) error
+}
+
+// DialectRDBMS is implemented by databases whose scripts select a database
+// or drop a table differently from MySQL.
+type DialectRDBMS interface {
+	UseStatement(dbName string) string
+	DropTableStatement(tableName string) string
+}
+
+// useStatement returns the statement that makes dbName current on client.
+func useStatement(client RDBMS, dbName string) string {
+	if d, ok := client.(DialectRDBMS); ok {
+		return d.UseStatement(dbName)
+	}
+	return fmt.Sprintf("USE %s;", dbName)
+}
+
+// dropTableStatement returns the statement that drops tableName on client.
+func dropTableStatement(client RDBMS, tableName string) string {
+	if d, ok := client.(DialectRDBMS); ok {
+		return d.DropTableStatement(tableName)
+	}
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;", tableName)
+}
+
+// SizedRDBMS is implemented by databases that can report the size of their data.
+type SizedRDBMS interface {
+	DatabaseSize(dbName string) (int64, error)
+}
+
 type RDBController struct {
 	Client RDBMS
 
-	logger *zerolog.Logger
+	ctx      context.Context
+	logger   *zerolog.Logger
+	progress *progress.Tracker
+
+	maxPacketSize int
+	bulkLoad      bool
+	disableChecks bool
+	workers       int
+	chunkRows     int64
+	spoolDir      string
+	consistency   models.ConsistencyMode
+	definer       string
+	userMigration *models.UserMigration
+	charset       *models.CharsetPolicy
+	tables        *models.TableSelection
 }
 
 type Option func(*RDBController)
@@ -63,20 +207,152 @@ func WithLogger(logger *zerolog.Logger) Option {
 	}
 }
 
+func WithProgress(tracker *progress.Tracker) Option {
+	return func(r *RDBController) {
+		r.progress = tracker
+	}
+}
+
+// WithMaxPacketSize caps the size in bytes of the multi-row INSERTs of an export.
+func WithMaxPacketSize(size int) Option {
+	return func(r *RDBController) {
+		r.maxPacketSize = size
+	}
+}
+
+// WithBulkLoad loads the rows copied into the controller with LOAD DATA LOCAL INFILE
+// when the database allows it, instead of executing INSERTs.
+func WithBulkLoad(enabled bool) Option {
+	return func(r *RDBController) {
+		r.bulkLoad = enabled
+	}
+}
+
+// WithDisableChecks turns off unique and foreign key checks while statements are imported.
+func WithDisableChecks(disable bool) Option {
+	return func(r *RDBController) {
+		r.disableChecks = disable
+	}
+}
+
+// WithWorkers sets the number of tables, or primary key ranges of large tables,
+// whose rows are exported in parallel.
+func WithWorkers(count int) Option {
+	return func(r *RDBController) {
+		if count >= 1 {
+			r.workers = count
+		}
+	}
+}
+
+// WithChunkRows splits tables with more rows into primary key ranges that are exported in parallel.
+func WithChunkRows(rows int64) Option {
+	return func(r *RDBController) {
+		r.chunkRows = rows
+	}
+}
+
+// WithSpoolDir sets the directory of the spool files of a parallel export, the system temporary directory by default.
+func WithSpoolDir(dir string) Option {
+	return func(r *RDBController) {
+		r.spoolDir = dir
+	}
+}
+
+// WithConsistency decides how an export reads a live database: from one
+// consistent snapshot (default), under a global read lock, or table by table.
+func WithConsistency(mode models.ConsistencyMode) Option {
+	return func(r *RDBController) {
+		r.consistency = mode
+	}
+}
+
+// WithDefiner replaces the definer of the exported views, routines, triggers
+// and events, given as user@host or CURRENT_USER.
+func WithDefiner(definer string) Option {
+	return func(r *RDBController) {
+		r.definer = definer
+	}
+}
+
+// WithUsers exports the users and grants selected by users after the database.
+func WithUsers(users *models.UserMigration) Option {
+	return func(r *RDBController) {
+		r.userMigration = users
+	}
+}
+
+// WithCharset converts the character sets and collations of an export by policy,
+// instead of converting them to utf8mb4.
+func WithCharset(policy *models.CharsetPolicy) Option {
+	return func(r *RDBController) {
+		r.charset = policy
+	}
+}
+
+// WithContext sets the context that cancels the queries of the controller.
+func WithContext(ctx context.Context) Option {
+	return func(r *RDBController) {
+		if ctx != nil {
+			r.ctx = ctx
+		}
+	}
+}
+
 func New(rdb RDBMS, opts ...Option) (*RDBController, error) {
 
 	rdbc := &RDBController{
-		Client: rdb,
-		logger: nil,
+		Client:  rdb,
+		ctx:     context.Background(),
+		logger:  nil,
+		workers: 1,
 	}
 
 	for _, opt := range opts {
 		opt(rdbc)
 	}
+	if err := validateSelection(rdbc.tables); err != nil {
+		return nil, err
+	}
+
+	if c, ok := rdb.(ContextRDBMS); ok {
+		c.SetContext(rdbc.ctx)
+	}
+	if b, ok := rdb.(BatchRDBMS); ok && rdbc.maxPacketSize > 0 {
+		b.SetMaxPacketSize(rdbc.maxPacketSize)
+	}
+	if c, ok := rdb.(ChecksRDBMS); ok {
+		c.SetDisableChecks(rdbc.disableChecks)
+	}
+	if d, ok := rdb.(DefinerRDBMS); ok {
+		d.SetDefiner(rdbc.definer)
+	}
+	if c, ok := rdb.(CharsetRDBMS); ok && rdbc.charset != nil {
+		c.SetCharsetPolicy(*rdbc.charset)
+	}
 
 	return rdbc, nil
 }
 
+// CharsetIssues returns the indexes of dbName whose keys would be too long
+// after the charset conversion of an export, or none when the database does not convert.
+func (rdb *RDBController) CharsetIssues(dbName string) ([]models.CharsetIssue, error) {
+	c, ok := rdb.Client.(CharsetRDBMS)
+	if !ok {
+		return nil, nil
+	}
+	return c.CharsetIssues(dbName)
+}
+
+// DatabaseSize returns the size in bytes of the table data of dbName.
+func (rdb *RDBController) DatabaseSize(dbName string) (int64, error) {
+	sized, ok := rdb.Client.(SizedRDBMS)
+	if !ok {
+		return 0, errors.New("database size is not supported")
+	}
+	return sized.DatabaseSize(dbName)
+}
+
 // Return db list
 func (rdb *RDBController) ListDB(dst *[]string) error {
 	err := rdb.Client.ListDB(dst)
@@ -116,7 +392,7 @@ func (rdb *RDBController) ListDB(dst *[]string) error {
 
 // sql import all at once (drives session-scope statements like USE on a single connection)
 func (rdb *RDBController) Put(sql string) error {
-	if err := rdb.Client.Exec(sql); err != nil {
+	if err := rdb.session(func(exec func(query string) error) error { return exec(sql) }); err != nil {
 		// log.Error().Msgf("err SQL : %+v", sql)
 		rdb.logWrite("Error", "sql exec error", err)
 		return err
@@ -124,6 +400,85 @@ func (rdb *RDBController) Put(sql string) error {
 	return nil
 }
 
+// defaultPutBatchSize is the size in bytes of the batches of PutReader when
+// no max packet size is set.
+const defaultPutBatchSize = 1 << 20
+
+// PutReader imports a dump read from r on a single session, without holding
+// it in memory. The statements, separated by a blank line as Get writes them,
+// are executed in batches of about the max packet size. A batch only ends
+// after a statement that ends with a semicolon, or after the rows of a COPY
+// ... FROM stdin, so a blank line inside a statement does not split it.
+func (rdb *RDBController) PutReader(r io.Reader) error {
+	batchSize := rdb.maxPacketSize
+	if batchSize <= 0 {
+		batchSize = defaultPutBatchSize
+	}
+
+	err := rdb.session(func(exec func(query string) error) error {
+		br := bufio.NewReader(r)
+		var batch, stmt strings.Builder
+		flush := func() error {
+			if batch.Len() == 0 {
+				return nil
+			}
+			if err := rdb.ctx.Err(); err != nil {
+				return err
+			}
+			if err := exec(batch.String()); err != nil {
+				return err
+			}
+			rdb.progress.AddBytes(int64(batch.Len()))
+			batch.Reset()
+			return nil
+		}
+
+		for {
+			line, readErr := br.ReadString('\n')
+			if readErr != nil && readErr != io.EOF {
+				return readErr
+			}
+			stmt.WriteString(line)
+			if line == "\n" || readErr == io.EOF {
+				if statementEnds(stmt.String()) {
+					batch.WriteString(stmt.String())
+					stmt.Reset()
+					if batch.Len() >= batchSize {
+						if err := flush(); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			if readErr == io.EOF {
+				batch.WriteString(stmt.String())
+				return flush()
+			}
+		}
+	})
+	if err != nil {
+		rdb.logWrite("Error", "sql exec error", err)
+		return err
+	}
+	return nil
+}
+
+// copyFromStdin matches the line of a COPY ... FROM stdin statement that heads its rows.
+var copyFromStdin = regexp.MustCompile(`(?im)^COPY\s.*\sFROM\s+stdin\b[^;\n]*;\r?$`)
+
+// statementEnds reports whether the text read up to a blank line ends a
+// statement: the rows of a COPY ... FROM stdin end with the line \., any
+// other statement with a semicolon.
+func statementEnds(stmt string) bool {
+	text := strings.TrimSpace(stmt)
+	if loc := copyFromStdin.FindAllStringIndex(text, -1); loc != nil {
+		if !strings.Contains(text[loc[len(loc)-1][1]:], "\n\\.") {
+			return false
+		}
+	}
+	return strings.HasSuffix(text, ";") || strings.HasSuffix(text, "\n\\.")
+}
+
 // sql import by .sql
 func (rdb *RDBController) PutDoc(sql string) error {
 	err := rdb.Client.Exec(sql)
@@ -136,67 +491,441 @@ func (rdb *RDBController) PutDoc(sql string) error {
 }
 
 // Migration using put and get for a specific database
+//
+// The statements of the source are executed on the target as they are read,
+// without building the dump in memory. The tables are created first, then
+// their rows are copied by the workers of the source, each on its own target
+// session, respecting foreign keys unless the target disables checks.
 func (rdb *RDBController) Copy(dst *RDBController, srcDbName string) error {
-	var sql string
 	rdb.Client.SetTargetProvdier(dst.Client.GetProvdier())
-	if err := rdb.Get(srcDbName, &sql); err != nil {
-		rdb.logWrite("Error", "Get error", err)
+	if rdb.Engine() != dst.Engine() {
+		report, err := rdb.Translate(dst, srcDbName)
+		if err != nil {
+			rdb.logWrite("Error", "translation error", err)
+			return err
+		}
+		return rdb.CopyTranslated(dst, report)
+	}
+	pos, workers, release, err := rdb.snapshot()
+	if err != nil {
+		rdb.logWrite("Error", "snapshot error", err)
 		return err
 	}
+	defer release()
+	if pos != nil {
+		log.Info().Msgf("copying %s from the snapshot at %s", srcDbName, positionString(*pos))
+	}
+
+	bulk := rdb.canBulkLoad(dst)
+	err = dst.session(func(exec func(query string) error) error {
+		tables, deps, err := rdb.schema(srcDbName, dst.logExec(exec))
+		if err != nil {
+			return err
+		}
+		if dst.disableChecks {
+			deps = nil
+		}
 
-	if err := dst.Put(sql); err != nil {
-		rdb.logWrite("Error", "Put error", err)
+		if workers <= 1 {
+			err = rdb.dispatch(srcDbName, tables, deps, 1, func(jobs <-chan tableJob, done chan<- tableJob) {
+				rdb.copyJobs(dst, exec, srcDbName, bulk, jobs, done)
+			}, nil)
+		} else {
+			err = rdb.dispatch(srcDbName, tables, deps, workers, func(jobs <-chan tableJob, done chan<- tableJob) {
+				err := dst.session(func(exec func(query string) error) error {
+					if err := exec(useStatement(dst.Client, srcDbName)); err != nil {
+						return err
+					}
+					rdb.copyJobs(dst, exec, srcDbName, bulk, jobs, done)
+					return nil
+				})
+				if err != nil {
+					for job := range jobs {
+						job.err = err
+						done <- job
+					}
+				}
+			}, nil)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := rdb.objects(srcDbName, dst.logExec(exec)); err != nil {
+			return err
+		}
+		users, err := rdb.users(srcDbName)
+		if err != nil {
+			return err
+		}
+		rdb.createUsers(exec, users)
+		return nil
+	})
+	if err != nil {
+		rdb.logWrite("Error", "Copy error", err)
 		return err
 	}
 	rdb.logWrite("Info", fmt.Sprintf("Migration success: src:/%s -> dst:/%s", srcDbName, srcDbName), nil)
 	return nil
 }
 
+// copyJobs copies the rows of jobs into dst through exec.
+func (rdb *RDBController) copyJobs(dst *RDBController, exec func(query string) error, dbName string, bulk bool, jobs <-chan tableJob, done chan<- tableJob) {
+	for job := range jobs {
+		rdb.progress.SetCurrent(job.table)
+		if bulk {
+			job.err = rdb.loadRows(dst, exec, dbName, job)
+		} else {
+			job.err = rdb.insert(dbName, job, dst.logExec(exec))
+		}
+		done <- job
+	}
+}
+
+// logExec logs the statements that fail on exec.
+func (rdb *RDBController) logExec(exec func(query string) error) func(query string) error {
+	return func(query string) error {
+		if err := exec(query); err != nil {
+			rdb.logWrite("Error", "sql exec error", err)
+			return err
+		}
+		return nil
+	}
+}
+
+// session runs fn on a single connection of the database when it supports
+// it, so that a USE statement applies to the statements after it.
+func (rdb *RDBController) session(fn func(exec func(query string) error) error) error {
+	if s, ok := rdb.Client.(SessionRDBMS); ok {
+		return s.Session(fn)
+	}
+	return fn(rdb.Client.Exec)
+}
+
+// canBulkLoad reports whether the rows copied into dst can be loaded with
+// LOAD DATA LOCAL INFILE, logging why not when dst asks for it.
+func (rdb *RDBController) canBulkLoad(dst *RDBController) bool {
+	if !dst.bulkLoad {
+		return false
+	}
+	_, ok := rdb.Client.(RowRDBMS)
+	bulk, bulkOk := dst.Client.(BulkRDBMS)
+	if !ok || !bulkOk {
+		log.Warn().Msg("bulk load is not supported, rows are copied as INSERTs")
+		return false
+	}
+	if enabled, err := bulk.LocalInfile(); err != nil || !enabled {
+		log.Warn().Err(err).Msg("local_infile is disabled on the target, rows are copied as INSERTs")
+		return false
+	}
+	return true
+}
+
+// loadRows loads the rows of a job into the target with LOAD DATA LOCAL INFILE through exec.
+func (rdb *RDBController) loadRows(dst *RDBController, exec func(query string) error, dbName string, job tableJob) error {
+	src := rdb.Client.(RowRDBMS)
+	bulk := dst.Client.(BulkRDBMS)
+	columns, err := src.ListColumn(dbName, job.table)
+	if err != nil {
+		return err
+	}
+	return bulk.LoadRows(exec, job.table, columns, rdb.readRows(src, dbName, columns, job))
+}
+
+// readRows returns the function that passes the rows of a job to emit as
+// they are read, counting their bytes.
+func (rdb *RDBController) readRows(src RowRDBMS, dbName string, columns []models.Column, job tableJob) func(emit func(row []sql.NullString) error) error {
+	return func(emit func(row []sql.NullString) error) error {
+		return src.GetRows(dbName, job.table, columns, job.where, func(row []sql.NullString) error {
+			if err := rdb.ctx.Err(); err != nil {
+				return err
+			}
+			if err := emit(row); err != nil {
+				return err
+			}
+			for _, val := range row {
+				rdb.progress.AddBytes(int64(len(val.String)))
+			}
+			return nil
+		})
+	}
+}
+
 // Export all data in database
-func (rdb *RDBController) Get(dbName string, sql *string) error {
+//
+// The dump is written to w statement by statement. With several workers, the
+// rows of the tables are exported in parallel into spool files that are
+// appended to w in table order. The binary log position of the snapshot the
+// tables were read from heads the dump.
+func (rdb *RDBController) Get(dbName string, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	write := func(stmt string) error {
+		return sqlWrite(bw, stmt)
+	}
+
+	pos, workers, release, err := rdb.snapshot()
+	if err != nil {
+		return err
+	}
+	defer release()
+	if pos != nil {
+		if err := write(snapshotHeader(*pos)); err != nil {
+			return err
+		}
+	}
+
+	tables, _, err := rdb.schema(dbName, write)
+	if err != nil {
+		return err
+	}
+
+	if workers <= 1 {
+		err = rdb.dispatch(dbName, tables, nil, 1, func(jobs <-chan tableJob, done chan<- tableJob) {
+			for job := range jobs {
+				rdb.progress.SetCurrent(job.table)
+				job.err = rdb.insert(dbName, job, write)
+				done <- job
+			}
+		}, nil)
+	} else {
+		err = rdb.spoolInserts(dbName, tables, workers, bw)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := rdb.objects(dbName, write); err != nil {
+		return err
+	}
+	users, err := rdb.users(dbName)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range users {
+		if err := write(stmt); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// snapshot pins the reads of an export to a consistent snapshot unless the
+// consistency is none. It returns the position of the snapshot, the number of
+// workers that can read from it and the function that releases it.
+func (rdb *RDBController) snapshot() (*models.BinlogPosition, int, func(), error) {
+	s, ok := rdb.Client.(SnapshotRDBMS)
+	if rdb.consistency == models.ConsistencyNone || !ok {
+		if rdb.consistency == models.ConsistencyLock {
+			return nil, 0, nil, errors.New("consistent snapshot is not supported")
+		}
+		return nil, rdb.workers, func() {}, nil
+	}
+
+	pos, readers, err := s.Snapshot(rdb.consistency == models.ConsistencyLock, rdb.workers)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if readers < rdb.workers {
+		log.Warn().Msgf("exporting with %d workers instead of %d to keep a consistent snapshot", readers, rdb.workers)
+	}
+	return &pos, readers, func() {
+		if err := s.Release(); err != nil {
+			log.Error().Err(err).Msg("snapshot release error")
+		}
+	}, nil
+}
+
+// snapshotHeader returns the comment that records pos at the head of a dump.
+func snapshotHeader(pos models.BinlogPosition) string {
+	return fmt.Sprintf("-- Consistent snapshot at %s", positionString(pos))
+}
+
+// positionString describes a binary log position.
+func positionString(pos models.BinlogPosition) string {
+	if pos.File == "" {
+		return "an unknown binlog position"
+	}
+	desc := fmt.Sprintf("binlog file %s, position %d", pos.File, pos.Position)
+	if pos.GTIDSet != "" {
+		desc += fmt.Sprintf(", GTID set %s", pos.GTIDSet)
+	}
+	if !pos.Exact {
+		desc += " (the position may be behind the snapshot)"
+	}
+	return desc
+}
+
+// schema passes the statements that create dbName and its tables to emit. It
+// returns the tables with referenced tables first and their foreign keys.
+func (rdb *RDBController) schema(dbName string, emit func(stmt string) error) ([]string, map[string][]string, error) {
 	var sqlTemp string
 	if err := rdb.Client.ShowCreateDBSql(dbName, &sqlTemp); err != nil {
 		log.Error().Msgf("ERR DB")
-		return err
+		return nil, nil, err
+	}
+	if err := emit(sqlTemp); err != nil {
+		return nil, nil, err
+	}
+	if err := emit(useStatement(rdb.Client, dbName)); err != nil {
+		return nil, nil, err
 	}
-	sqlWrite(sql, sqlTemp)
-	sqlWrite(sql, fmt.Sprintf("USE %s;", dbName))
 
 	var tableList []string
 	if err := rdb.Client.ListTable(dbName, &tableList); err != nil {
 		log.Error().Msgf("ERR List TB")
 
-		return err
+		return nil, nil, err
 	}
 
+	tableList = rdb.selectTables(tableList)
+
+	var deps map[string][]string
+	if fk, ok := rdb.Client.(ForeignKeyRDBMS); ok {
+		var err error
+		if deps, err = fk.TableDependencies(dbName); err != nil {
+			return nil, nil, err
+		}
+	}
 	for _, table := range tableList {
-		sqlWrite(sql, fmt.Sprintf("DROP TABLE IF EXISTS %s;", table))
+		for _, ref := range deps[table] {
+			if !rdb.selected(ref) {
+				log.Warn().Msgf("table %s references %s, which is left out; its foreign key may fail on the target", table, ref)
+			}
+		}
+	}
+	if rdb.filtersRows() && len(deps) > 0 {
+		log.Warn().Msg("filtered or sampled rows may reference rows that are left out; disable the checks of the target if their foreign keys fail")
+	}
+	tableList = orderTables(tableList, deps)
 
+	// referencing tables are dropped before the tables they reference
+	for i := len(tableList) - 1; i >= 0; i-- {
+		if err := emit(dropTableStatement(rdb.Client, tableList[i])); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, table := range tableList {
 		if err := rdb.Client.ShowCreateTableSql(dbName, table, &sqlTemp); err != nil {
 			log.Error().Msgf("ERR Creatte TB")
 
-			return err
+			return nil, nil, err
+		}
+		if err := emit(sqlTemp); err != nil {
+			return nil, nil, err
 		}
-		sqlWrite(sql, sqlTemp)
 	}
+	return tableList, deps, nil
+}
 
-	for _, table := range tableList {
-		var insertData []string
-		if err := rdb.Client.GetInsert(dbName, table, &insertData); err != nil {
-			log.Error().Msgf("Insert quer err")
+// objects passes the statements that create the views, routines, triggers and
+// events of dbName to emit. They come after the rows, so that the triggers do
+// not fire on them.
+func (rdb *RDBController) objects(dbName string, emit func(stmt string) error) error {
+	objects, err := rdb.Client.ListSchemaObjects(dbName)
+	if err != nil {
+		log.Error().Msgf("ERR List objects")
+		return err
+	}
+	var leftOut []string
+	if rdb.tables != nil {
+		var tables []string
+		if err := rdb.Client.ListTable(dbName, &tables); err != nil {
 			return err
 		}
-
-		for _, data := range insertData {
-			sqlWrite(sql, data)
+		for _, table := range tables {
+			if !rdb.selected(table) {
+				leftOut = append(leftOut, table)
+			}
+		}
+	}
+	for _, object := range objects {
+		if table, ok := leftOutTable(object, leftOut); ok {
+			log.Warn().Msgf("%s %s is left out, it refers to table %s, which is not selected", object.Type, object.Name, table)
+			continue
+		}
+		if object.Drop != "" {
+			if err := emit(object.Drop); err != nil {
+				return err
+			}
+		}
+		if err := emit(object.Create); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// users returns the statements that create the users and grants selected by
+// WithUsers, or none when users are not migrated.
+func (rdb *RDBController) users(dbName string) ([]string, error) {
+	if rdb.userMigration == nil {
+		return nil, nil
+	}
+	u, ok := rdb.Client.(UserRDBMS)
+	if !ok {
+		return nil, errors.New("user migration is not supported")
+	}
+	return u.UserStatements(dbName, *rdb.userMigration)
+}
+
+// createUsers runs the user statements through exec. Managed databases refuse
+// some grants, which does not fail the migration; the refused statements are
+// counted as failed in the progress of the task and logged.
+func (rdb *RDBController) createUsers(exec func(query string) error, users []string) {
+	failed, accounts := 0, 0
+	for _, stmt := range users {
+		if err := exec(stmt); err != nil {
+			name := userStatementName(stmt)
+			failed++
+			if strings.HasPrefix(stmt, "CREATE USER") {
+				accounts++
+			}
+			rdb.progress.Fail(name, err)
+			log.Warn().Err(err).Msgf("user statement failed: %s", name)
+		}
+	}
+	if failed > 0 {
+		log.Warn().Msgf("%d of %d user statements failed, %d accounts were not created", failed, len(users), accounts)
+	}
+}
+
+// userStatementName returns a user statement without the credentials of a CREATE USER.
+func userStatementName(stmt string) string {
+	if !strings.HasPrefix(stmt, "CREATE USER") {
+		return stmt
+	}
+	if i := strings.Index(stmt, " IDENTIFIED"); i >= 0 {
+		return stmt[:i]
+	}
+	return strings.TrimSuffix(stmt, ";")
+}
+
+// insert passes the INSERTs of the rows of a job to emit.
+func (rdb *RDBController) insert(dbName string, job tableJob, emit func(stmt string) error) error {
+	emitInsert := func(data string) error {
+		if err := rdb.ctx.Err(); err != nil {
+			return err
+		}
+		if err := emit(data); err != nil {
+			return err
+		}
+		rdb.progress.AddBytes(int64(len(data)))
+		return nil
+	}
+	if job.where == "" {
+		return rdb.Client.GetInsert(dbName, job.table, emitInsert)
+	}
+	w, ok := rdb.Client.(WhereRDBMS)
+	if !ok {
+		return errors.New("row selection is not supported")
+	}
+	return w.GetInsertWhere(dbName, job.table, job.where, emitInsert)
+}
+
 // Function to create a dividing line
-func sqlWrite(sql *string, data string) {
-	*sql += fmt.Sprintf("%s\n\n", data)
+func sqlWrite(w io.Writer, data string) error {
+	_, err := fmt.Fprintf(w, "%s\n\n", data)
+	return err
 }
 
 // Split by line
@@ -17,7 +17,8 @@ package rdbc_test
 
 import (
 	"database/sql"
-	"fmt"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/cloud-barista/mc-data-manager/models"
@@ -25,46 +26,50 @@ import (
 	"github.com/cloud-barista/mc-data-manager/service/rdbc"
 )
 
-func TestMain(m *testing.M) {
-	// aws example
-	// 	RDBCInfo(models.AWS,"admin","datamoldPassword","127.0.0.1","3306")
-	// gcp example
-	// 	RDBCInfo(models.GCP,"root","datamoldPassword","127.0.0.1","3306")
-	// ncp example
-	// 	RDBCInfo(models.NCP,"root","datamoldPassword","127.0.0.1","3306")
-	Srdbc := RDBCInfo("your-source-provider", "your-source-mysql-username", "your-source-mysql-password", "your-source-mysql-host", "your-source-mysql-port")
-	Drdbc := RDBCInfo("your-target-provider", "your-target-mysql-username", "your-target-mysql-password", "your-target-mysql-host", "your-target-mysql-port")
+// TestCopy imports LibraryManagement into the MySQL server of
+// MC_DATA_MANAGER_TEST_MYSQL_DSN, exports it and migrates it to the server of
+// MC_DATA_MANAGER_TEST_MYSQL_TARGET_DSN, like user:password@tcp(host:3306)/
+func TestCopy(t *testing.T) {
+	srcDSN := os.Getenv("MC_DATA_MANAGER_TEST_MYSQL_DSN")
+	dstDSN := os.Getenv("MC_DATA_MANAGER_TEST_MYSQL_TARGET_DSN")
+	if srcDSN == "" || dstDSN == "" {
+		t.Skip("MC_DATA_MANAGER_TEST_MYSQL_DSN or MC_DATA_MANAGER_TEST_MYSQL_TARGET_DSN is not set")
+	}
+	// the provider is aws, gcp or ncp for a managed database
+	Srdbc := RDBCInfo(t, "mysql", srcDSN)
+	Drdbc := RDBCInfo(t, "mysql", dstDSN)
 
 	// Srdbc에 LibraryManagement.sql import
-	if err := Srdbc.Put(testSQL); err != nil {
-		panic(err)
+	if err := Srdbc.PutReader(strings.NewReader(testSQL)); err != nil {
+		t.Fatal(err)
 	}
 
 	// Srdbc의 LibraryManagement Export
-	var dstSQL string
+	var dstSQL strings.Builder
 	if err := Srdbc.Get("LibraryManagement", &dstSQL); err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
-	// Srdbc db들을 Drdbc로 이전
-	if err := Srdbc.Copy(Drdbc); err != nil {
-		panic(err)
+	// Srdbc의 LibraryManagement를 Drdbc로 이전
+	if err := Srdbc.Copy(Drdbc, "LibraryManagement"); err != nil {
+		t.Fatal(err)
 	}
 }
 
-func RDBCInfo(providerType models.Provider, username, password, host, port string) *rdbc.RDBController {
-	sqlDB, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/", username, password, host, port))
+func RDBCInfo(t *testing.T, providerType models.Provider, dsn string) *rdbc.RDBController {
+	sqlDB, err := sql.Open("mysql", dsn)
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
+	t.Cleanup(func() { sqlDB.Close() })
 
 	if err := sqlDB.Ping(); err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	rdbController, err := rdbc.New(mysql.New(providerType, sqlDB))
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
 	return rdbController
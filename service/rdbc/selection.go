@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rdbc
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+// WithTables selects the tables and rows an export takes. A nil selection takes everything.
+func WithTables(selection *models.TableSelection) Option {
+	return func(r *RDBController) {
+		r.tables = selection
+	}
+}
+
+// validateSelection checks the table patterns, filters and sample of a selection.
+func validateSelection(s *models.TableSelection) error {
+	if s == nil {
+		return nil
+	}
+	patterns := append(append([]string{}, s.Include...), s.Exclude...)
+	for _, filter := range s.Filters {
+		if strings.TrimSpace(filter.Where) == "" {
+			return fmt.Errorf("filter of tables %q has no where", filter.Table)
+		}
+		if err := checkWhere(filter.Where); err != nil {
+			return fmt.Errorf("filter of tables %q: %w", filter.Table, err)
+		}
+		patterns = append(patterns, filter.Table)
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid table pattern %q: %w", pattern, err)
+		}
+	}
+
+	if sample := s.Sample; sample != nil {
+		switch {
+		case sample.Rows > 0 && sample.Percent > 0:
+			return errors.New("sample takes either rows or percent")
+		case sample.Rows < 0, sample.Percent < 0, sample.Percent > 100:
+			return errors.New("sample rows must be positive and percent above 0 and up to 100")
+		case sample.Rows == 0 && sample.Percent == 0:
+			return errors.New("sample needs rows or percent")
+		}
+	}
+	return nil
+}
+
+// checkWhere rejects a where that could end the SELECT it is pasted into:
+// a statement separator or a comment outside of quotes, or an open quote.
+// The source connection runs several statements per query, so a where like
+// "1=1; DROP TABLE orders" would otherwise change the source. Backslashes are
+// rejected too, as MySQL and PostgreSQL disagree on whether they escape a quote.
+func checkWhere(where string) error {
+	var quote byte
+	for i := 0; i < len(where); i++ {
+		c := where[i]
+		if c == '\\' {
+			return errors.New("where must not contain backslashes")
+		}
+		if quote != 0 {
+			switch {
+			case c == quote && i+1 < len(where) && where[i+1] == quote:
+				i++
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ';':
+			return errors.New("where must not contain ';'")
+		case c == '#', c == '-' && strings.HasPrefix(where[i:], "--"), c == '/' && strings.HasPrefix(where[i:], "/*"):
+			return errors.New("where must not contain comments")
+		}
+	}
+	if quote != 0 {
+		return fmt.Errorf("where has an unterminated %c quote", quote)
+	}
+	return nil
+}
+
+// matchTable reports whether table matches one of patterns.
+func matchTable(patterns []string, table string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, table); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// selected reports whether the selection takes table.
+func (rdb *RDBController) selected(table string) bool {
+	if rdb.tables == nil {
+		return true
+	}
+	if len(rdb.tables.Include) > 0 && !matchTable(rdb.tables.Include, table) {
+		return false
+	}
+	return !matchTable(rdb.tables.Exclude, table)
+}
+
+// selectTables returns the tables the selection takes, in order.
+func (rdb *RDBController) selectTables(tables []string) []string {
+	selected := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if rdb.selected(table) {
+			selected = append(selected, table)
+		}
+	}
+	if left := len(tables) - len(selected); left > 0 {
+		log.Info().Msgf("%d of %d tables are selected, %d are left out", len(selected), len(tables), left)
+	}
+	return selected
+}
+
+// filtersRows reports whether the selection leaves out rows of the tables it takes.
+func (rdb *RDBController) filtersRows() bool {
+	return rdb.tables != nil && (len(rdb.tables.Filters) > 0 || rdb.tables.Sample != nil)
+}
+
+// sampleRows reports whether the selection draws a number of rows from every table.
+func (rdb *RDBController) sampleRows() bool {
+	return rdb.tables != nil && rdb.tables.Sample != nil && rdb.tables.Sample.Rows > 0
+}
+
+// rowCondition returns the condition the rows of a table must match: the
+// first filter of the table and the sample. It is empty for all rows.
+func (rdb *RDBController) rowCondition(dbName, table string) (string, error) {
+	if rdb.tables == nil {
+		return "", nil
+	}
+	var where string
+	for _, filter := range rdb.tables.Filters {
+		if ok, _ := path.Match(filter.Table, table); ok {
+			where = filter.Where
+			break
+		}
+	}
+	if rdb.tables.Sample != nil {
+		s, ok := rdb.Client.(SampleRDBMS)
+		if !ok {
+			return "", errors.New("row sampling is not supported")
+		}
+		sample, err := s.SampleCondition(dbName, table, where, *rdb.tables.Sample)
+		if err != nil {
+			return "", err
+		}
+		where = andWhere(where, sample)
+	}
+	return where, nil
+}
+
+// andWhere returns the condition matching both a and b, either of which may be empty.
+func andWhere(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return "(" + a + ") AND (" + b + ")"
+}
+
+// leftOutTable returns the table left out by the selection that a view or
+// trigger names, as such an object cannot be created without it.
+func leftOutTable(object models.SchemaObject, leftOut []string) (string, bool) {
+	if object.Type != models.SchemaView && object.Type != models.SchemaTrigger {
+		return "", false
+	}
+	for _, table := range leftOut {
+		if regexp.MustCompile(`(^|[^\w$])` + regexp.QuoteMeta(table) + `($|[^\w$])`).MatchString(object.Create) {
+			return table, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rdbc
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Engine returns the engine of the database.
+func (rdb *RDBController) Engine() EngineType {
+	if e, ok := rdb.Client.(EngineRDBMS); ok {
+		return EngineType(e.Engine())
+	}
+	return Mysql
+}
+
+// Translate plans the migration of dbName into dst when dst has another
+// engine, without writing anything to dst. It returns nil for the same engine.
+func (rdb *RDBController) Translate(dst *RDBController, dbName string) (*models.TranslationReport, error) {
+	if rdb.Engine() == dst.Engine() {
+		return nil, nil
+	}
+	s, ok := rdb.Client.(SchemaRDBMS)
+	_, rowOk := rdb.Client.(RowRDBMS)
+	t, tOk := dst.Client.(TranslatorRDBMS)
+	if !ok || !rowOk || !tOk {
+		return nil, fmt.Errorf("migration from %s to %s is not supported", rdb.Engine(), dst.Engine())
+	}
+
+	tables, err := s.TableSchemas(dbName)
+	if err != nil {
+		return nil, err
+	}
+	if rdb.tables != nil {
+		names := make([]string, len(tables))
+		for i, table := range tables {
+			names[i] = table.Name
+		}
+		names = rdb.selectTables(names)
+		tables = slices.DeleteFunc(tables, func(table models.TableSchema) bool {
+			return !slices.Contains(names, table.Name)
+		})
+	}
+	objects, err := rdb.Client.ListSchemaObjects(dbName)
+	if err != nil {
+		return nil, err
+	}
+	report, err := t.TranslateSchema(string(rdb.Engine()), dbName, tables, objects)
+	if err != nil {
+		return nil, err
+	}
+	if rdb.userMigration != nil {
+		report.Issues = append(report.Issues, models.TranslationIssue{
+			Level:     models.TranslationUnsupported,
+			Object:    dbName,
+			Construct: "users",
+			Detail:    "users and grants are not migrated between engines",
+		})
+	}
+	return report, nil
+}
+
+// CopyTranslated migrates the database of report into dst of another engine.
+// The tables are created, their rows are converted and copied by the workers
+// of the source, then the indexes, foreign keys and identities are added and
+// the foreign keys validated unless the rows are filtered or sampled.
+func (rdb *RDBController) CopyTranslated(dst *RDBController, report *models.TranslationReport) error {
+	t, ok := dst.Client.(TranslatorRDBMS)
+	src, rowOk := rdb.Client.(RowRDBMS)
+	if !ok || !rowOk {
+		return fmt.Errorf("migration from %s to %s is not supported", rdb.Engine(), dst.Engine())
+	}
+	dbName := report.Database
+
+	pos, workers, release, err := rdb.snapshot()
+	if err != nil {
+		rdb.logWrite("Error", "snapshot error", err)
+		return err
+	}
+	defer release()
+	if pos != nil {
+		log.Info().Msgf("copying %s from the snapshot at %s", dbName, positionString(*pos))
+	}
+
+	tables := make([]string, len(report.Tables))
+	translations := map[string]models.TableTranslation{}
+	for i, table := range report.Tables {
+		tables[i] = table.Table
+		translations[table.Table] = table
+	}
+	copyJobs := func(exec func(query string) error, jobs <-chan tableJob, done chan<- tableJob) {
+		for job := range jobs {
+			rdb.progress.SetCurrent(job.table)
+			table := translations[job.table]
+			columns := make([]models.Column, len(table.Columns))
+			for i, column := range table.Columns {
+				columns[i] = column.Column
+			}
+			job.err = t.LoadTranslated(dst.logExec(exec), table, rdb.readRows(src, dbName, columns, job))
+			done <- job
+		}
+	}
+
+	err = dst.session(func(exec func(query string) error) error {
+		for _, stmt := range report.Schema {
+			if err := dst.logExec(exec)(stmt); err != nil {
+				return err
+			}
+		}
+
+		// the foreign keys are added after the rows, so the tables are copied in any order
+		var err error
+		if workers <= 1 {
+			err = rdb.dispatch(dbName, tables, nil, 1, func(jobs <-chan tableJob, done chan<- tableJob) {
+				copyJobs(exec, jobs, done)
+			}, nil)
+		} else {
+			err = rdb.dispatch(dbName, tables, nil, workers, func(jobs <-chan tableJob, done chan<- tableJob) {
+				err := dst.session(func(exec func(query string) error) error {
+					if err := exec(useStatement(dst.Client, dbName)); err != nil {
+						return err
+					}
+					copyJobs(exec, jobs, done)
+					return nil
+				})
+				if err != nil {
+					for job := range jobs {
+						job.err = err
+						done <- job
+					}
+				}
+			}, nil)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range report.Finish {
+			if err := dst.logExec(exec)(stmt); err != nil {
+				return err
+			}
+		}
+		if rdb.filtersRows() {
+			if len(report.Validate) > 0 {
+				log.Warn().Msg("the rows are filtered or sampled, the foreign keys are not validated against them")
+			}
+			return nil
+		}
+		for _, stmt := range report.Validate {
+			if err := dst.logExec(exec)(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		rdb.logWrite("Error", "Copy error", err)
+		return err
+	}
+	rdb.logWrite("Info", fmt.Sprintf("Migration success: src:/%s (%s) -> dst:/%s (%s)", dbName, rdb.Engine(), dbName, dst.Engine()), nil)
+	return nil
+}
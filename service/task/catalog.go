@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/backup"
+	"github.com/rs/zerolog/log"
+)
+
+// catalogued reports whether a backup task records its runs as generations in a catalog.
+func catalogued(params models.BasicDataTask) bool {
+	return params.Incremental || params.Retention != nil
+}
+
+// newGeneration returns the generation metadata of a backup run.
+func newGeneration(params models.BasicDataTask) models.BackupGeneration {
+	return models.BackupGeneration{
+		TaskID:      params.TaskID,
+		ServiceType: params.ServiceType,
+		Source:      pointName(params.SourcePoint),
+		Incremental: params.Incremental && params.ServiceType == models.ObejectStorage,
+	}
+}
+
+// beginGeneration creates the directory of a new generation for a file based backup.
+func beginGeneration(params models.BasicDataTask) (string, models.BackupGeneration, error) {
+	catalog, err := backup.LoadCatalog(params.TargetPoint.Path)
+	if err != nil {
+		return "", models.BackupGeneration{}, err
+	}
+
+	gen := newGeneration(params)
+	gen.Incremental = false
+	gen.StartedAt = time.Now().UTC()
+	gen.ID = backup.NewGenerationID(catalog, gen.StartedAt)
+
+	genDir := filepath.Join(params.TargetPoint.Path, gen.ID)
+	if err := os.MkdirAll(genDir, 0755); err != nil {
+		return "", models.BackupGeneration{}, err
+	}
+	return genDir, gen, nil
+}
+
+// runGenerationBackup runs a file based backup into a new generation directory
+// and records the generation when the backup completes.
+func runGenerationBackup(params models.BasicDataTask, run func(outDir string) models.Status) models.Status {
+	if err := os.MkdirAll(params.TargetPoint.Path, 0755); err != nil {
+		log.Error().Err(err).Msg("MkdirAll error ")
+		return models.StatusFailed
+	}
+
+	genDir, gen, err := beginGeneration(params)
+	if err != nil {
+		log.Error().Err(err).Msg("backup generation error")
+		return models.StatusFailed
+	}
+
+	status := run(genDir)
+	if status != models.StatusCompleted {
+		_ = os.RemoveAll(genDir)
+		return status
+	}
+
+	if err := commitGeneration(params, genDir, gen); err != nil {
+		log.Error().Err(err).Msg("backup catalog error")
+		_ = os.RemoveAll(genDir)
+		return models.StatusFailed
+	}
+	log.Info().Msgf("successfully recorded backup generation %s", gen.ID)
+	return status
+}
+
+// commitGeneration records the files written to genDir and applies the retention policy.
+func commitGeneration(params models.BasicDataTask, genDir string, gen models.BackupGeneration) error {
+	entries, err := backup.FileEntries(genDir, gen.ID)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		gen.Objects++
+		gen.Bytes += entry.Size
+	}
+	gen.NewObjects = gen.Objects
+	gen.NewBytes = gen.Bytes
+	gen.FinishedAt = time.Now().UTC()
+	gen.Watermark = gen.StartedAt
+
+	if err := backup.Record(params.TargetPoint.Path, gen, &models.BackupManifest{Entries: entries}); err != nil {
+		return err
+	}
+	applyRetention(params)
+	return nil
+}
+
+// applyRetention prunes the generations not kept by the retention policy of the task.
+// A failed prune does not fail the backup that was just recorded.
+func applyRetention(params models.BasicDataTask) {
+	if params.Retention == nil {
+		return
+	}
+	pruned, err := backup.Prune(params.TargetPoint.Path, *params.Retention)
+	if err != nil {
+		log.Error().Err(err).Msg("retention prune error")
+		return
+	}
+	if len(pruned) > 0 {
+		log.Info().Msgf("retention pruned %d generations: %v", len(pruned), pruned)
+	}
+}
+
+// selectGeneration picks the generation a restore task reads from.
+// It returns nil for backup directories without a catalog, which are restored as they are.
+func selectGeneration(params models.BasicDataTask) (*models.BackupGeneration, error) {
+	if !backup.HasCatalog(params.SourcePoint.Path) {
+		if params.Generation != "" || params.PointInTime != nil {
+			return nil, fmt.Errorf("%s has no backup catalog", params.SourcePoint.Path)
+		}
+		return nil, nil
+	}
+
+	catalog, err := backup.LoadCatalog(params.SourcePoint.Path)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := backup.Select(catalog, params.Generation, params.PointInTime)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("restore from backup generation %s finished at %s", gen.ID, gen.FinishedAt.Format(time.RFC3339))
+	return &gen, nil
+}
+
+// restoreDir returns the directory holding the files of a file based restore.
+func restoreDir(params models.BasicDataTask) (string, error) {
+	gen, err := selectGeneration(params)
+	if err != nil {
+		return "", err
+	}
+	if gen == nil {
+		return params.SourcePoint.Path, nil
+	}
+	return filepath.Join(params.SourcePoint.Path, gen.ID), nil
+}
+
+// GetBackupCatalog loads the catalog of the backup directory or repository of a backup task.
+func (m *FileScheduleManager) GetBackupCatalog(taskID string) (*models.BackupCatalog, error) {
+	task, err := m.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.TaskType != models.Backup {
+		return nil, fmt.Errorf("task %s is not a backup task", taskID)
+	}
+	if task.Dedup {
+		repo, err := openRepository(context.Background(), task.TargetPoint)
+		if err != nil {
+			return nil, err
+		}
+		return repo.Catalog()
+	}
+	if !backup.HasCatalog(task.TargetPoint.Path) {
+		return nil, fmt.Errorf("backup of task %s has no catalog", taskID)
+	}
+	return backup.LoadCatalog(task.TargetPoint.Path)
+}
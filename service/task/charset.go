@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/service/rdbc"
+	"github.com/rs/zerolog/log"
+)
+
+// checkCharset reports the indexes whose keys would be too long for the target
+// after the charset conversion of an RDBMS export, before the export starts,
+// and saves them as the charset report. It returns false when the export must not start.
+func checkCharset(RDBC *rdbc.RDBController, params models.BasicDataTask) bool {
+	removeReport(ReportCharset, params.TaskID)
+	issues, err := RDBC.CharsetIssues(params.SourcePoint.DatabaseName)
+	if err != nil {
+		log.Error().Err(err).Msg("charset check error")
+		return false
+	}
+
+	report := &models.CharsetReport{
+		TaskID:    params.TaskID,
+		Database:  params.SourcePoint.DatabaseName,
+		Passed:    len(issues) == 0,
+		Issues:    issues,
+		CheckedAt: time.Now().UTC(),
+	}
+	if params.Charset != nil {
+		report.Policy = *params.Charset
+	}
+	if report.Issues == nil {
+		report.Issues = []models.CharsetIssue{}
+	}
+	if fileName, err := saveReport(ReportCharset, params.TaskID, report); err != nil {
+		log.Error().Err(err).Msg("failed to save charset report")
+	} else {
+		log.Info().Msgf("charset report saved: %s", fileName)
+	}
+
+	for _, issue := range issues {
+		log.Error().Msgf("index %s of table %s (%s) would be %d bytes after the charset conversion, the limit is %d",
+			issue.Index, issue.Table, strings.Join(issue.Columns, ", "), issue.KeyBytes, issue.MaxBytes)
+	}
+	if len(issues) > 0 {
+		log.Error().Msgf("%d indexes are too long for the charset policy, shorten them or change the policy", len(issues))
+		return false
+	}
+	return true
+}
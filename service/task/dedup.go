@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/internal/auth"
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/backup"
+	"github.com/cloud-barista/mc-data-manager/pkg/dedup"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+	"github.com/rs/zerolog/log"
+)
+
+// openRepository opens the deduplicating repository of a provider config.
+// A config with a bucket keeps the repository in object storage, otherwise in the local directory Path.
+func openRepository(ctx context.Context, point models.ProviderConfig) (*dedup.Repository, error) {
+	if point.Bucket == "" {
+		if point.Path == "" {
+			return nil, errors.New("repository needs a bucket or a path")
+		}
+		return dedup.Open(dedup.NewDirStore(point.Path))
+	}
+
+	OSC, err := auth.GetOS(&point, osc.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return OSC.Repository()
+}
+
+// runDedupBackup backs up the source bucket as a new snapshot of the repository at the target point.
+func runDedupBackup(ctx context.Context, params models.BasicDataTask, OSC *osc.OSController, flt *filtering.ObjectFilter) models.Status {
+	if params.Retention != nil {
+		log.Warn().Msg("retention is not applied to deduplicated backups")
+	}
+
+	var target *osc.OSController
+	var lock models.ObjectLock
+	if params.ObjectLock != nil {
+		var err error
+		if target, lock, err = objectLockTarget(ctx, params); err != nil {
+			log.Error().Err(err).Msg("backup refused")
+			return models.StatusFailed
+		}
+	}
+
+	repo, err := openRepository(ctx, params.TargetPoint)
+	if err != nil {
+		log.Error().Err(err).Msg("backup repository error")
+		return models.StatusFailed
+	}
+
+	log.Info().Msg("Launch OSController DedupBackup")
+	snap, err := OSC.DedupBackup(repo, newGeneration(params), flt)
+	if err != nil {
+		log.Error().Err(err).Msg("DedupBackup error exporting into objectstorage ")
+		return models.StatusFailed
+	}
+	log.Info().Msgf("successfully backup snapshot %s (%d objects, %d new chunks, %d new bytes)",
+		snap.ID, snap.Objects, snap.NewChunks, snap.NewBytes)
+
+	if target != nil {
+		// chunks reused from older snapshots are locked again, so their retention covers this snapshot too
+		keys := repo.SnapshotObjects(snap)
+		if err := target.LockObjects(keys, lock); err != nil {
+			log.Error().Err(err).Msgf("snapshot %s is not immutable", snap.ID)
+			return models.StatusFailed
+		}
+		log.Info().Msgf("locked %d objects of snapshot %s", len(keys), snap.ID)
+	}
+	return models.StatusCompleted
+}
+
+// selectSnapshot picks the snapshot a restore task reads from the repository.
+func selectSnapshot(repo *dedup.Repository, params models.BasicDataTask) (*models.DedupSnapshot, error) {
+	catalog, err := repo.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	gen, err := backup.Select(catalog, params.Generation, params.PointInTime)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("restore from backup snapshot %s finished at %s", gen.ID, gen.FinishedAt.Format(time.RFC3339))
+	return repo.LoadSnapshot(gen.ID)
+}
+
+// runDedupRestore restores a snapshot of the repository at the source point into the target bucket.
+func runDedupRestore(ctx context.Context, params models.BasicDataTask, OSC *osc.OSController, opts osc.RestoreOptions) models.Status {
+	repo, err := openRepository(ctx, params.SourcePoint)
+	if err != nil {
+		log.Error().Err(err).Msg("backup repository error")
+		return models.StatusFailed
+	}
+
+	snap, err := selectSnapshot(repo, params)
+	if err != nil {
+		log.Error().Err(err).Msg("backup snapshot error")
+		return models.StatusFailed
+	}
+
+	log.Info().Msg("Launch OSController RestoreSnapshot")
+	if err := OSC.RestoreSnapshot(repo, snap, opts); err != nil {
+		log.Error().Err(err).Msg("RestoreSnapshot error importing into objectstorage ")
+		return models.StatusFailed
+	}
+	log.Info().Msgf("successfully restore snapshot %s", snap.ID)
+	return models.StatusCompleted
+}
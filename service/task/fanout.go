@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/internal/auth"
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+	"github.com/rs/zerolog/log"
+)
+
+// fanOutPoints returns the targets of a migration: TargetPoint when it is set, then TargetPoints.
+func fanOutPoints(params models.BasicDataTask) []models.ProviderConfig {
+	points := []models.ProviderConfig{}
+	if params.TargetPoint.Provider != "" {
+		points = append(points, params.TargetPoint)
+	}
+	return append(points, params.TargetPoints...)
+}
+
+// runObjectStorageFanOut copies the source bucket to every target of the task
+// and stores the per-target results as the fan-out report of the task.
+func runObjectStorageFanOut(ctx context.Context, params models.BasicDataTask, src *osc.OSController) models.Status {
+	flt, err := filtering.FromParams(params.SourceFilter)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid sourceFilter")
+		return models.StatusFailed
+	}
+
+	report := &models.FanOutReport{
+		TaskID:      params.TaskID,
+		SourcePoint: pointName(params.SourcePoint),
+		StartedAt:   time.Now(),
+	}
+
+	points := fanOutPoints(params)
+	report.Targets = make([]models.TargetStatus, len(points))
+	targets := []osc.FanOutTarget{}
+	index := []int{}
+	for i, point := range points {
+		name := pointName(point)
+		report.Targets[i] = models.TargetStatus{Target: name, Status: models.StatusPending}
+
+		log.Info().Msgf("Target Information %s", name)
+		dst, err := auth.GetOS(&point, osc.WithContext(ctx))
+		if err != nil {
+			log.Error().Err(err).Msgf("OSController error migration into %s", name)
+			report.Targets[i].Status = models.StatusFailed
+			report.Targets[i].Error = err.Error()
+			if !params.ContinueOnError {
+				return finishFanOut(report, models.StatusFailed)
+			}
+			continue
+		}
+		targets = append(targets, osc.FanOutTarget{Name: name, OSC: dst})
+		index = append(index, i)
+	}
+
+	log.Info().Msgf("Launch OSController FanOut to %d targets", len(targets))
+	statuses, err := src.FanOut(targets, flt, params.ContinueOnError)
+	for j, st := range statuses {
+		report.Targets[index[j]] = st
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("FanOut error copying into object storage")
+	}
+
+	if params.Verify && ctx.Err() == nil {
+		for j, t := range targets {
+			st := &report.Targets[index[j]]
+			if st.Status != models.StatusCompleted {
+				continue
+			}
+			vr, err := src.Verify(t.OSC, flt)
+			if err != nil {
+				st.Status = models.StatusFailed
+				st.Error = err.Error()
+				continue
+			}
+			st.Verify = &vr.Summary
+			if !vr.Passed() {
+				st.Status = models.StatusFailed
+				st.Error = "target does not match source"
+			}
+		}
+	}
+
+	status := models.StatusCompleted
+	for _, st := range report.Targets {
+		if st.Status != models.StatusCompleted {
+			status = models.StatusFailed
+		}
+	}
+	return finishFanOut(report, status)
+}
+
+// finishFanOut saves the fan-out report and returns status.
+func finishFanOut(report *models.FanOutReport, status models.Status) models.Status {
+	report.FinishedAt = time.Now()
+	fileName, err := saveReport(ReportFanOut, report.TaskID, report)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to save fan-out report")
+		return models.StatusFailed
+	}
+	for _, st := range report.Targets {
+		log.Info().Msgf("target %s: %s (copied %d, skipped %d, failed %d)", st.Target, st.Status, st.Copied, st.Skipped, st.Failed)
+	}
+	log.Info().Msgf("fan-out report saved : %s", fileName)
+	return status
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/internal/auth"
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+	"github.com/rs/zerolog/log"
+)
+
+// resolveObjectLock validates the object lock of a task and sets the end of a
+// retention given in days, counted from now.
+func resolveObjectLock(lock models.ObjectLock, now time.Time) (models.ObjectLock, error) {
+	if lock.RetainUntil == nil && lock.RetainDays > 0 {
+		until := now.AddDate(0, 0, lock.RetainDays)
+		lock.RetainUntil = &until
+	}
+	if lock.RetainUntil == nil {
+		if !lock.LegalHold {
+			return lock, errors.New("objectLock needs retainUntil, retainDays or legalHold")
+		}
+		return lock, nil
+	}
+
+	switch lock.Mode {
+	case "":
+		lock.Mode = models.ObjectLockGovernance
+	case models.ObjectLockGovernance, models.ObjectLockCompliance:
+	default:
+		return lock, fmt.Errorf("unknown object lock mode %q", lock.Mode)
+	}
+	if !lock.RetainUntil.After(now) {
+		return lock, fmt.Errorf("retainUntil %s is not in the future", lock.RetainUntil.Format(time.RFC3339))
+	}
+	return lock, nil
+}
+
+// objectLockTarget opens the target of a backup with an object lock and checks
+// that it can make objects immutable, before anything is written.
+func objectLockTarget(ctx context.Context, params models.BasicDataTask) (*osc.OSController, models.ObjectLock, error) {
+	lock, err := resolveObjectLock(*params.ObjectLock, time.Now())
+	if err != nil {
+		return nil, lock, err
+	}
+	if !params.Dedup || params.TargetPoint.Bucket == "" {
+		return nil, lock, fmt.Errorf("%w: only deduplicated backups into an object storage bucket can be locked, the target is a local path", osc.ErrObjectLockUnsupported)
+	}
+
+	target, err := auth.GetOS(&params.TargetPoint, osc.WithContext(ctx))
+	if err != nil {
+		return nil, lock, err
+	}
+	if err := target.CheckObjectLock(lock); err != nil {
+		return nil, lock, fmt.Errorf("target %s: %w", pointName(params.TargetPoint), err)
+	}
+	return target, lock, nil
+}
+
+// refuseObjectLock refuses a backup with an object lock whose target is always a local path.
+func refuseObjectLock(params models.BasicDataTask) bool {
+	if params.ObjectLock == nil {
+		return false
+	}
+	log.Error().Err(fmt.Errorf("%w: %s backups are written to a local path", osc.ErrObjectLockUnsupported, params.ServiceType)).Msg("backup refused")
+	return true
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/pii"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultQuarantinePrefix = "quarantine/"
+
+// scanObjectStoragePII scans the source of a migration for personal data, applies
+// the policy of the task and saves the findings report.
+// It returns the number of quarantined objects and false when the migration must not go on.
+func scanObjectStoragePII(params models.BasicDataTask, src *osc.OSController) (int, bool) {
+	cfg := params.PIIScan
+	policy := cfg.Policy
+	if policy == "" {
+		policy = models.PIIBlock
+	}
+	if policy != models.PIIBlock && policy != models.PIIQuarantine && policy != models.PIIAllow {
+		log.Error().Msgf("unknown pii policy %q", policy)
+		return 0, false
+	}
+
+	scanner, err := pii.New(cfg.Types)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid piiScan")
+		return 0, false
+	}
+	flt, err := filtering.FromParams(params.SourceFilter)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid sourceFilter")
+		return 0, false
+	}
+
+	log.Info().Msgf("Scanning source objects for personal data (policy %s)", policy)
+	report, err := src.ScanPII(flt, scanner, cfg.SampleBytes)
+	if err != nil {
+		log.Error().Err(err).Msg("pii scan failed")
+		return 0, false
+	}
+	report.TaskID = params.TaskID
+	report.Policy = policy
+
+	keys := make([]string, 0, len(report.Objects))
+	for _, obj := range report.Objects {
+		keys = append(keys, obj.Key)
+	}
+
+	switch {
+	case len(keys) == 0:
+	case policy == models.PIIBlock:
+		report.Blocked = true
+	case policy == models.PIIQuarantine:
+		prefix := cfg.QuarantinePrefix
+		if prefix == "" {
+			prefix = defaultQuarantinePrefix
+		}
+		src.Quarantine(keys, prefix)
+	}
+	for i := range report.Objects {
+		obj := &report.Objects[i]
+		switch policy {
+		case models.PIIBlock:
+			obj.Action = "blocked"
+		case models.PIIQuarantine:
+			obj.Action = "quarantined"
+			obj.TargetKey = src.TargetKey(obj.Key)
+		default:
+			obj.Action = "allowed"
+			obj.TargetKey = obj.Key
+		}
+	}
+
+	if fileName, err := saveReport(ReportPII, report.TaskID, report); err != nil {
+		log.Error().Err(err).Msg("failed to save pii report")
+		if report.Blocked {
+			return 0, false
+		}
+	} else {
+		log.Info().Msgf("pii report saved: %s", fileName)
+	}
+
+	log.Info().Msgf("pii scan: %d scanned, %d skipped, %d with personal data", report.Scanned, report.Skipped, len(keys))
+	if report.Blocked {
+		log.Error().Msgf("migration blocked: personal data found in %d objects", len(keys))
+		return 0, false
+	}
+	if policy == models.PIIQuarantine {
+		return len(keys), true
+	}
+	return 0, true
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/preflight"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+	"github.com/cloud-barista/mc-data-manager/service/rdbc"
+	"github.com/rs/zerolog/log"
+)
+
+// preflightBackup checks free space and write permission on TargetPoint.Path
+// before a local backup writes to it, and saves the result as the preflight report.
+// estimate sizes the backup. It returns false when the backup must not start.
+func preflightBackup(params models.BasicDataTask, estimate func() (int64, error)) bool {
+	mode := params.Preflight
+	if mode == "" {
+		mode = models.PreflightEnforce
+	}
+	if mode == models.PreflightOff {
+		return true
+	}
+
+	estimated, estimateErr := estimate()
+	report, err := preflight.Check(params.TargetPoint.Path, estimated, estimateErr)
+	report.TaskID = params.TaskID
+	if fileName, serr := saveReport(ReportPreflight, report.TaskID, report); serr != nil {
+		log.Error().Err(serr).Msg("failed to save preflight report")
+	} else {
+		log.Info().Msgf("preflight report saved: %s", fileName)
+	}
+	for _, p := range report.Problems {
+		if !p.Fatal {
+			log.Warn().Str("code", p.Code).Msg(p.Message)
+		}
+	}
+
+	if err == nil {
+		log.Info().Msgf("preflight passed: about %d bytes needed, %d free in %s", report.RequiredBytes, report.FreeBytes, report.Path)
+		return true
+	}
+	if mode == models.PreflightWarn {
+		log.Warn().Err(err).Msg("preflight failed, backup starts anyway")
+		return true
+	}
+	log.Error().Err(err).Msg("backup refused")
+	return false
+}
+
+// objectStorageBackupSize estimates a backup by listing the objects selected by flt.
+// The backup reuses the listing.
+func objectStorageBackupSize(OSC *osc.OSController, flt *filtering.ObjectFilter) func() (int64, error) {
+	return func() (int64, error) {
+		objList, err := OSC.ObjectListWithFilter(flt)
+		if err != nil {
+			return 0, err
+		}
+		OSC.ReuseList(flt, objList)
+		var size int64
+		for _, obj := range objList {
+			size += obj.Size
+		}
+		return size, nil
+	}
+}
+
+// rdbmsBackupSize estimates a backup by the table data size of the source database.
+// The SQL dump is usually of the same order as the data, Margin covers the difference.
+// With several workers the rows are spooled next to the dump before they are
+// appended to it, so up to twice the size is needed.
+func rdbmsBackupSize(RDBC *rdbc.RDBController, params models.BasicDataTask) func() (int64, error) {
+	return func() (int64, error) {
+		size, err := RDBC.DatabaseSize(params.SourcePoint.DatabaseName)
+		if err != nil {
+			return 0, err
+		}
+		if params.Workers > 1 {
+			size *= 2
+		}
+		return size, nil
+	}
+}
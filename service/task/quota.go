@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloud-barista/mc-data-manager/config"
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/quota"
+	"github.com/rs/zerolog/log"
+)
+
+// registerQuota creates the quota guard of a task, priced with the egress price of its source provider.
+func registerQuota(params models.BasicDataTask, cancel context.CancelCauseFunc) (*quota.Guard, error) {
+	// the report of a previous run does not apply to this one
+	removeReport(ReportQuota, params.TaskID)
+
+	q := params.Quota
+	if q == nil {
+		return quota.Register(params.TaskID, nil, 0, nil), nil
+	}
+	if q.OnExceed != "" && q.OnExceed != models.QuotaFail && q.OnExceed != models.QuotaPause {
+		return nil, fmt.Errorf("unknown onExceed %q", q.OnExceed)
+	}
+	if params.ServiceType != models.ObejectStorage {
+		log.Warn().Msgf("quota is only enforced for object storage tasks, not %s", params.ServiceType)
+	}
+
+	price, ok := config.EgressPrice(params.SourcePoint.Provider)
+	if !ok && q.MaxCost > 0 {
+		return nil, fmt.Errorf("no egress price configured for provider %q", params.SourcePoint.Provider)
+	}
+	return quota.Register(params.TaskID, q, price, cancel), nil
+}
+
+// finishQuota saves the quota report of a task that exceeded its quota and returns
+// the status chosen by the quota: failed or paused.
+func finishQuota(params models.BasicDataTask, guard *quota.Guard) models.Status {
+	status := quota.ExceededStatus(params.Quota)
+	log.Error().Err(guard.Err()).Msgf("task %s %s", params.TaskID, status)
+
+	report := guard.Report()
+	report.TaskID = params.TaskID
+	report.Status = status
+	if fileName, err := saveReport(ReportQuota, report.TaskID, &report); err != nil {
+		log.Error().Err(err).Msg("failed to save quota report")
+	} else {
+		log.Info().Msgf("quota report saved: %s", fileName)
+	}
+	return status
+}
+
+// ResumeTask runs a task paused by its quota again, in the background.
+// A non-nil quota replaces the quota of the task, so that the run can go past
+// the limit it was paused at; with the same quota it is paused again.
+func (m *FileScheduleManager) ResumeTask(taskID string, q *models.TransferQuota) error {
+	m.mu.Lock()
+	idx := -1
+	for i, task := range m.tasks {
+		if task.TaskMeta.TaskID == taskID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		m.mu.Unlock()
+		return errors.New("task not found")
+	}
+	if m.tasks[idx].Status != models.StatusPaused {
+		m.mu.Unlock()
+		return fmt.Errorf("task is %s, not paused", m.tasks[idx].Status)
+	}
+	if q != nil {
+		m.tasks[idx].Quota = q
+	}
+	m.tasks[idx].Status = models.StatusActive
+	task := m.tasks[idx]
+	err := m.saveToFile()
+	m.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save tasks to file: %w", err)
+	}
+
+	log.Info().Msgf("Resuming task %s", taskID)
+	go m.RunTasks([]models.BasicDataTask{task})
+	return nil
+}
+
+// paused reports whether the stored status of a task is paused. Scheduled
+// runs get the tasks of the schedule, whose status is not kept up to date.
+func (m *FileScheduleManager) paused(taskID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, task := range m.tasks {
+		if task.TaskMeta.TaskID == taskID {
+			return task.Status == models.StatusPaused
+		}
+	}
+	return false
+}
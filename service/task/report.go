@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/rs/zerolog/log"
+)
+
+const reportDir = "./data/var/run/data-manager/report"
+
+// Kinds of the reports of a task, used in their file names.
+const (
+	ReportVerify      = "verify"
+	ReportCancel      = "cancel"
+	ReportFanOut      = "fanout"
+	ReportTransform   = "transform"
+	ReportPII         = "pii"
+	ReportTranslation = "translation"
+	ReportQuota       = "quota"
+	ReportPreflight   = "preflight"
+	ReportCharset     = "charset"
+	ReportSync        = "sync"
+)
+
+// ReportPath returns the file path of a report of a task.
+func ReportPath(kind, taskID string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(taskID)
+	return filepath.Join(reportDir, fmt.Sprintf("%s-%s.json", name, kind))
+}
+
+// saveReport writes v as a report of a task to the report directory and returns its file name.
+func saveReport(kind, taskID string, v any) (string, error) {
+	if err := os.MkdirAll(reportDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create directories %s: %w", reportDir, err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	fileName := ReportPath(kind, taskID)
+	if err := os.WriteFile(fileName, data, 0644); err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
+
+// LoadReport reads a report of a task into v.
+func LoadReport(kind, taskID string, v any) error {
+	data, err := os.ReadFile(ReportPath(kind, taskID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s report not found", kind)
+		}
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// removeReport removes the report of a previous run, so that a run that fails
+// before it writes its report does not leave the old one behind.
+func removeReport(kind, taskID string) {
+	if err := os.Remove(ReportPath(kind, taskID)); err != nil && !os.IsNotExist(err) {
+		log.Warn().Err(err).Msgf("failed to remove the previous %s report", kind)
+	}
+}
+
+// pointName returns a short description of a provider config for reports.
+func pointName(p models.ProviderConfig) string {
+	if p.Bucket != "" {
+		return fmt.Sprintf("%s/%s/%s", p.Provider, p.Region, p.Bucket)
+	}
+	if p.Path != "" {
+		return fmt.Sprintf("%s:%s", p.Provider, p.Path)
+	}
+	return fmt.Sprintf("%s/%s", p.Provider, p.Region)
+}
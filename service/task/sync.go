@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloud-barista/mc-data-manager/internal/auth"
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/progress"
+	"github.com/cloud-barista/mc-data-manager/pkg/quota"
+	"github.com/cloud-barista/mc-data-manager/service/osc"
+	"github.com/rs/zerolog/log"
+)
+
+const syncStateDir = "./data/var/run/data-manager/sync"
+
+// syncStatePath returns the file path of the sync state of a task.
+func syncStatePath(taskID string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(taskID)
+	return filepath.Join(syncStateDir, name+".json")
+}
+
+// loadSyncState loads the state of the last run of a sync task between source and target.
+// A task that never ran starts with an empty state. A state recorded for another
+// source or target is refused, as its entries would turn into deletions.
+func loadSyncState(taskID, source, target string) (*models.SyncState, error) {
+	if taskID == "" {
+		return nil, errors.New("a sync task needs an operationId to keep its state")
+	}
+	state := &models.SyncState{TaskID: taskID, SourcePoint: source, TargetPoint: target, Objects: map[string]models.SyncEntry{}}
+	data, err := os.ReadFile(syncStatePath(taskID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.SourcePoint != source || state.TargetPoint != target {
+		return nil, fmt.Errorf("sync state of task %s belongs to %s -> %s, not %s -> %s",
+			taskID, state.SourcePoint, state.TargetPoint, source, target)
+	}
+	if state.Objects == nil {
+		state.Objects = map[string]models.SyncEntry{}
+	}
+	return state, nil
+}
+
+// saveSyncState writes the state of a sync task through a temporary file.
+func saveSyncState(state *models.SyncState) error {
+	if err := os.MkdirAll(syncStateDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directories %s: %w", syncStateDir, err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	fileName := syncStatePath(state.TaskID)
+	if err := os.WriteFile(fileName+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(fileName+".tmp", fileName)
+}
+
+func handleObjectStorageSyncTask(ctx context.Context, params models.BasicDataTask) models.Status {
+	log.Info().Msg("Handling object storage sync task")
+
+	log.Info().Msg("Source Information")
+	src, err := auth.GetOS(&params.SourcePoint, osc.WithContext(ctx), osc.WithProgress(progress.Lookup(params.TaskID)), osc.WithQuota(quota.Lookup(params.TaskID)))
+	if err != nil {
+		log.Error().Err(err).Msg("OSController error sync object storage")
+		return models.StatusFailed
+	}
+	log.Info().Msg("Target Information")
+	dst, err := auth.GetOS(&params.TargetPoint, osc.WithContext(ctx))
+	if err != nil {
+		log.Error().Err(err).Msg("OSController error sync object storage")
+		return models.StatusFailed
+	}
+
+	flt, err := filtering.FromParams(params.SourceFilter)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid sourceFilter")
+		return models.StatusFailed
+	}
+
+	state, err := loadSyncState(params.TaskID, pointName(params.SourcePoint), pointName(params.TargetPoint))
+	if err != nil {
+		log.Error().Err(err).Msg("sync state error")
+		return models.StatusFailed
+	}
+
+	policy := params.Conflict
+	if policy == "" {
+		policy = models.ConflictNewest
+	}
+
+	log.Info().Msgf("Launch OSController Sync (conflict policy %s)", policy)
+	report, syncErr := src.Sync(dst, state, policy, flt)
+	if report == nil {
+		log.Error().Err(syncErr).Msg("Sync error synchronising object storage")
+		return models.StatusFailed
+	}
+
+	// the state is saved after a partly failed run too, failed objects keep their previous entry
+	if err := saveSyncState(state); err != nil {
+		log.Error().Err(err).Msg("failed to save sync state")
+		return models.StatusFailed
+	}
+
+	report.TaskID = params.TaskID
+	report.SourcePoint = pointName(params.SourcePoint)
+	report.TargetPoint = pointName(params.TargetPoint)
+	fileName, err := saveReport(ReportSync, report.TaskID, report)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to save sync report")
+		return models.StatusFailed
+	}
+	log.Info().Interface("summary", report.Summary).Msgf("sync report saved : %s", fileName)
+
+	if syncErr != nil {
+		log.Error().Err(syncErr).Msg("Sync error synchronising object storage")
+		return models.StatusFailed
+	}
+	log.Info().Msg("Successfully synchronised")
+	return models.StatusCompleted
+}
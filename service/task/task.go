@@ -1,6 +1,7 @@
 package task
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,6 +18,9 @@ import (
 	"github.com/cloud-barista/mc-data-manager/internal/auth"
 	"github.com/cloud-barista/mc-data-manager/models"
 	"github.com/cloud-barista/mc-data-manager/pkg/objectstorage/filtering"
+	"github.com/cloud-barista/mc-data-manager/pkg/progress"
+	"github.com/cloud-barista/mc-data-manager/pkg/quota"
+	"github.com/cloud-barista/mc-data-manager/pkg/transform"
 	"github.com/cloud-barista/mc-data-manager/pkg/utils"
 	"github.com/cloud-barista/mc-data-manager/service/nrdbc"
 	"github.com/cloud-barista/mc-data-manager/service/osc"
@@ -38,6 +42,14 @@ type FileScheduleManager struct {
 	mu         sync.Mutex
 	filename   string
 	schedulers map[string]*gocron.Scheduler // Map of time zone to its scheduler
+
+	running   map[string]*taskRun // Map of task ID to its running execution
+	runningMu sync.Mutex
+}
+
+// taskRun holds the cancel function of a running task.
+type taskRun struct {
+	cancel context.CancelFunc
 }
 
 // InitFileScheduleManager initializes the singleton instance of FileScheduleManager.
@@ -51,6 +63,7 @@ func InitFileScheduleManager() *FileScheduleManager {
 			schedules:  make([]models.Schedule, 0),
 			filename:   filename,
 			schedulers: make(map[string]*gocron.Scheduler),
+			running:    make(map[string]*taskRun),
 		}
 
 		if err := managerInstance.loadFromFile(); err != nil {
@@ -526,6 +539,7 @@ func (m *FileScheduleManager) DeleteTask(taskID string) error {
 		if task.TaskMeta.TaskID == taskID {
 			// Remove the task from the tasks slice
 			m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+			progress.Remove(taskID)
 			// Save the updated tasks to file
 			if err := m.saveToFile(); err != nil {
 				return fmt.Errorf("failed to save tasks to file: %w", err)
@@ -609,6 +623,7 @@ func (m *FileScheduleManager) DeleteTasksByType(taskType models.TaskType, taskID
 		if task.TaskType == taskType && task.TaskMeta.TaskID == taskID {
 			// Remove the task from the tasks slice
 			m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+			progress.Remove(taskID)
 			deleted = true
 			break
 		}
@@ -631,11 +646,17 @@ func (m *FileScheduleManager) RunTasks(tasks []models.BasicDataTask) {
 	for idx, task := range tasks {
 		log.Debug().Msgf("(%v/%v)", idx, len(tasks))
 		// Call the handleTask function to process the task
-		if (task.Status == models.StatusInactive) || (task.Status == models.StatusFailed) {
+		if (task.Status == models.StatusInactive) || (task.Status == models.StatusFailed) || (task.Status == models.StatusPaused) {
 			log.Warn().Msgf(" task status : %v", task.Status)
 			continue
 		}
-		task.Status = handleTask(task.ServiceType, task.TaskType, task)
+		if m.paused(task.TaskID) {
+			log.Warn().Msgf(" task %s is paused by its quota until it is resumed", task.TaskID)
+			continue
+		}
+		ctx, done := m.startRun(task.TaskID)
+		task.Status = handleTask(ctx, task.ServiceType, task.TaskType, task)
+		done()
 		log.Debug().Msgf("status : %v", task.Status)
 		m.updateTaskStatus(task)
 	}
@@ -648,16 +669,26 @@ func (m *FileScheduleManager) RunTasks(tasks []models.BasicDataTask) {
 func (m *FileScheduleManager) RunTaskOnce(task models.DataTask) bool {
 	// CreateTask
 	m.CreateTask(task)
+	return m.RunTask(task)
+}
+
+// RunTask runs a task created with CreateTask once.
+func (m *FileScheduleManager) RunTask(task models.DataTask) bool {
 	// Call the handleTask function to process the task
-	if (task.Status == models.StatusInactive) || (task.Status == models.StatusFailed) {
+	if (task.Status == models.StatusInactive) || (task.Status == models.StatusFailed) || (task.Status == models.StatusPaused) {
 		log.Warn().Msgf(" task status : %v", task.Status)
 		return false
 	}
-	task.Status = handleTask(task.ServiceType, task.TaskType, task.BasicDataTask)
+	ctx, done := m.startRun(task.TaskID)
+	task.Status = handleTask(ctx, task.ServiceType, task.TaskType, task.BasicDataTask)
+	done()
 	m.updateTaskStatus(task.BasicDataTask)
 
-	if task.Status == models.StatusFailed {
-		log.Error().Msg("task Failed")
+	if task.Status == models.StatusFailed || task.Status == models.StatusCancelled || task.Status == models.StatusPaused {
+		log.Error().Msgf("task %s", task.Status)
+		if err := m.saveToFile(); err != nil {
+			log.Error().Err(err).Msg("Error saving tasks to file")
+		}
 		return false
 	}
 
@@ -668,6 +699,41 @@ func (m *FileScheduleManager) RunTaskOnce(task models.DataTask) bool {
 	return true
 }
 
+// startRun registers a cancellable execution of a task.
+// The returned function must be called when the execution ends.
+func (m *FileScheduleManager) startRun(taskID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &taskRun{cancel: cancel}
+
+	m.runningMu.Lock()
+	m.running[taskID] = run
+	m.runningMu.Unlock()
+
+	return ctx, func() {
+		m.runningMu.Lock()
+		if m.running[taskID] == run {
+			delete(m.running, taskID)
+		}
+		m.runningMu.Unlock()
+		cancel()
+	}
+}
+
+// CancelTask cancels a running task.
+// The task stops at the next object or table and ends in the cancelled status.
+func (m *FileScheduleManager) CancelTask(taskID string) error {
+	m.runningMu.Lock()
+	defer m.runningMu.Unlock()
+
+	run, ok := m.running[taskID]
+	if !ok {
+		return errors.New("task is not running")
+	}
+	run.cancel()
+	log.Info().Msgf("Cancel requested for task %s", taskID)
+	return nil
+}
+
 // handler
 
 // hasDuplicateOperationID checks if a schedule with the given OperationId already exists.
@@ -681,24 +747,41 @@ func (m *FileScheduleManager) hasDuplicateOperationID(operationID string) bool {
 }
 
 // handleTask is a function that processes a task based on its ServiceType and TaskType.
-func handleTask(serviceType models.CloudServiceType, taskType models.TaskType, params models.BasicDataTask) models.Status {
+func handleTask(ctx context.Context, serviceType models.CloudServiceType, taskType models.TaskType, params models.BasicDataTask) models.Status {
 
 	var taskStatus models.Status
+	tracker := progress.Register(params.TaskID)
+	tracker.SetStatus(models.StatusRunning)
+	defer func() { tracker.Finish(taskStatus) }()
+
+	// an exceeded quota stops the task like a cancellation
+	ctx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+	guard, err := registerQuota(params, stop)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid quota")
+		taskStatus = models.StatusFailed
+		return taskStatus
+	}
 
 	switch serviceType {
 
 	case "objectstorage":
 		switch taskType {
 		case "generate":
-			taskStatus = handleObjectStorageGenerateTask(params)
+			taskStatus = handleObjectStorageGenerateTask(ctx, params)
 		case "migrate":
-			taskStatus = handleObjectStorageMigrateTask(params)
+			taskStatus = handleObjectStorageMigrateTask(ctx, params)
 		case "backup":
-			taskStatus = handleObjectStorageBackupTask(params)
+			taskStatus = handleObjectStorageBackupTask(ctx, params)
 		case "restore":
-			taskStatus = handleObjectStorageRestoreTask(params)
+			taskStatus = handleObjectStorageRestoreTask(ctx, params)
 		case "delete":
-			taskStatus = handleObjectStorageDeleteTask(params)
+			taskStatus = handleObjectStorageDeleteTask(ctx, params)
+		case "verify":
+			taskStatus = handleObjectStorageVerifyTask(ctx, params)
+		case "sync":
+			taskStatus = handleObjectStorageSyncTask(ctx, params)
 		default:
 			log.Error().Msgf("Error: Unknown TaskType: %s for ServiceType: %s\n", taskType, serviceType)
 			taskStatus = models.StatusFailed
@@ -706,15 +789,15 @@ func handleTask(serviceType models.CloudServiceType, taskType models.TaskType, p
 	case "rdbms":
 		switch taskType {
 		case "generate":
-			taskStatus = handleRDBMSGenerateTask(params)
+			taskStatus = handleRDBMSGenerateTask(ctx, params)
 		case "migrate":
-			taskStatus = handleRDBMSMigrateTask(params)
+			taskStatus = handleRDBMSMigrateTask(ctx, params)
 		case "backup":
-			taskStatus = handleRDBMSBackupTask(params)
+			taskStatus = handleRDBMSBackupTask(ctx, params)
 		case "restore":
-			taskStatus = handleRDBMSRestoreTask(params)
+			taskStatus = handleRDBMSRestoreTask(ctx, params)
 		case "delete":
-			taskStatus = handleRDBMSDeleteTask(params)
+			taskStatus = handleRDBMSDeleteTask(ctx, params)
 		default:
 			log.Error().Msgf("Error: Unknown TaskType: %s for ServiceType: %s\n", taskType, serviceType)
 			taskStatus = models.StatusFailed
@@ -722,15 +805,15 @@ func handleTask(serviceType models.CloudServiceType, taskType models.TaskType, p
 	case "nrdbms":
 		switch taskType {
 		case "generate":
-			taskStatus = handleNRDBMSGenerateTask(params)
+			taskStatus = handleNRDBMSGenerateTask(ctx, params)
 		case "migrate":
-			taskStatus = handleNRDBMSMigrateTask(params)
+			taskStatus = handleNRDBMSMigrateTask(ctx, params)
 		case "backup":
-			taskStatus = handleNRDBMSBackupTask(params)
+			taskStatus = handleNRDBMSBackupTask(ctx, params)
 		case "restore":
-			taskStatus = handleNRDBMSRestoreTask(params)
+			taskStatus = handleNRDBMSRestoreTask(ctx, params)
 		case "delete":
-			taskStatus = handleNRDBMSDeleteTask(params)
+			taskStatus = handleNRDBMSDeleteTask(ctx, params)
 		default:
 			log.Error().Msgf("Error: Unknown TaskType: %s for ServiceType: %s\n", taskType, serviceType)
 			taskStatus = models.StatusFailed
@@ -741,15 +824,27 @@ func handleTask(serviceType models.CloudServiceType, taskType models.TaskType, p
 
 	}
 
+	if guard.Err() != nil {
+		taskStatus = finishQuota(params, guard)
+	} else if ctx.Err() != nil {
+		log.Warn().Msgf("task %s cancelled", params.TaskID)
+		taskStatus = models.StatusCancelled
+		if fileName, err := saveReport(ReportCancel, params.TaskID, tracker.Snapshot()); err != nil {
+			log.Error().Err(err).Msg("failed to save cancel journal")
+		} else {
+			log.Info().Msgf("cancel journal saved: %s", fileName)
+		}
+	}
+
 	return taskStatus
 }
 
-func handleObjectStorageGenerateTask(params models.BasicDataTask) models.Status {
+func handleObjectStorageGenerateTask(ctx context.Context, params models.BasicDataTask) models.Status {
 
 	var OSC *osc.OSController
 	var err error
 	log.Info().Msgf("User Information")
-	OSC, err = auth.GetOS(&params.TargetPoint)
+	OSC, err = auth.GetOS(&params.TargetPoint, osc.WithContext(ctx), osc.WithProgress(progress.Lookup(params.TaskID)), osc.WithQuota(quota.Lookup(params.TaskID)))
 	if err != nil {
 		log.Error().Msgf("OSController error importing into objectstorage : %v", err)
 		return models.StatusFailed
@@ -766,12 +861,12 @@ func handleObjectStorageGenerateTask(params models.BasicDataTask) models.Status
 	return models.StatusCompleted
 }
 
-func handleObjectStorageDeleteTask(params models.BasicDataTask) models.Status {
+func handleObjectStorageDeleteTask(ctx context.Context, params models.BasicDataTask) models.Status {
 
 	var OSC *osc.OSController
 	var err error
 	log.Info().Msgf("User Information")
-	OSC, err = auth.GetOS(&params.TargetPoint)
+	OSC, err = auth.GetOS(&params.TargetPoint, osc.WithContext(ctx))
 	if err != nil {
 		log.Error().Msgf("OSController error importing into objectstorage : %v", err)
 		return models.StatusFailed
@@ -787,22 +882,46 @@ func handleObjectStorageDeleteTask(params models.BasicDataTask) models.Status {
 	return models.StatusCompleted
 }
 
-func handleObjectStorageMigrateTask(params models.BasicDataTask) models.Status {
+func handleObjectStorageMigrateTask(ctx context.Context, params models.BasicDataTask) models.Status {
 	log.Info().Msg("Handling object storage migrate task")
+	removeReport(ReportVerify, params.TaskID)
 
 	var src *osc.OSController
 	var srcErr error
 	var dst *osc.OSController
 	var dstErr error
 
+	rules, err := transform.Compile(params.Transforms)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid transforms")
+		return models.StatusFailed
+	}
+	if rules != nil && len(params.TargetPoints) > 0 {
+		log.Error().Msg("transforms are not supported with targetPoints")
+		return models.StatusFailed
+	}
+
 	log.Info().Msg("Source Information")
-	src, srcErr = auth.GetOS(&params.SourcePoint)
+	src, srcErr = auth.GetOS(&params.SourcePoint, osc.WithContext(ctx), osc.WithProgress(progress.Lookup(params.TaskID)), osc.WithQuota(quota.Lookup(params.TaskID)), osc.WithTransforms(rules))
 	if srcErr != nil {
 		log.Error().Err(srcErr).Msg("OSController error migration into object storage")
 		return models.StatusFailed
 	}
+	if params.PIIScan != nil {
+		quarantined, ok := scanObjectStoragePII(params, src)
+		if !ok {
+			return models.StatusFailed
+		}
+		if quarantined > 0 && params.Verify {
+			log.Warn().Msgf("verify is skipped: %d objects were quarantined under another key", quarantined)
+			params.Verify = false
+		}
+	}
+	if len(params.TargetPoints) > 0 {
+		return runObjectStorageFanOut(ctx, params, src)
+	}
 	log.Info().Msg("Target Information")
-	dst, dstErr = auth.GetOS(&params.TargetPoint)
+	dst, dstErr = auth.GetOS(&params.TargetPoint, osc.WithContext(ctx))
 	if dstErr != nil {
 		log.Error().Err(dstErr).Msg("OSController error migration into object storage")
 		return models.StatusFailed
@@ -848,20 +967,91 @@ func handleObjectStorageMigrateTask(params models.BasicDataTask) models.Status {
 	}
 
 	log.Info().Msg("Launch OSController Copy")
-	if err := src.Copy(dst, flt); err != nil {
-		log.Error().Err(err).Msg("Copy error copying into object storage")
+	copyErr := src.Copy(dst, flt)
+	if rules != nil {
+		report := &models.TransformReport{TaskID: params.TaskID, Items: src.Transformed()}
+		if fileName, err := saveReport(ReportTransform, report.TaskID, report); err != nil {
+			log.Error().Err(err).Msg("failed to save transform report")
+		} else {
+			log.Info().Msgf("transform report saved: %s", fileName)
+		}
+	}
+	if copyErr != nil {
+		log.Error().Err(copyErr).Msg("Copy error copying into object storage")
 		return models.StatusFailed
 	}
 	log.Info().Msg("Successfully migrated")
+
+	if params.Verify && rules != nil {
+		// transformed objects differ from their source by design; the transform report records their checksums
+		log.Warn().Msg("verify is skipped for migrations with transforms")
+		return models.StatusCompleted
+	}
+	if params.Verify {
+		return runObjectStorageVerify(params, src, dst, flt)
+	}
+	return models.StatusCompleted
+}
+
+func handleObjectStorageVerifyTask(ctx context.Context, params models.BasicDataTask) models.Status {
+	log.Info().Msg("Handling object storage verify task")
+	removeReport(ReportVerify, params.TaskID)
+
+	log.Info().Msg("Source Information")
+	src, err := auth.GetOS(&params.SourcePoint, osc.WithContext(ctx))
+	if err != nil {
+		log.Error().Err(err).Msg("OSController error verify object storage")
+		return models.StatusFailed
+	}
+	log.Info().Msg("Target Information")
+	dst, err := auth.GetOS(&params.TargetPoint, osc.WithContext(ctx))
+	if err != nil {
+		log.Error().Err(err).Msg("OSController error verify object storage")
+		return models.StatusFailed
+	}
+
+	flt, err := filtering.FromParams(params.SourceFilter)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid sourceFilter")
+		return models.StatusFailed
+	}
+
+	return runObjectStorageVerify(params, src, dst, flt)
+}
+
+// runObjectStorageVerify compares src and dst and stores the report under the task ID.
+func runObjectStorageVerify(params models.BasicDataTask, src, dst *osc.OSController, flt *filtering.ObjectFilter) models.Status {
+	log.Info().Msg("Launch OSController Verify")
+	report, err := src.Verify(dst, flt)
+	if err != nil {
+		log.Error().Err(err).Msg("Verify error comparing object storage")
+		return models.StatusFailed
+	}
+	report.TaskID = params.TaskID
+	report.SourcePoint = pointName(params.SourcePoint)
+	report.TargetPoint = pointName(params.TargetPoint)
+
+	fileName, err := saveReport(ReportVerify, params.TaskID, report)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to save verify report")
+		return models.StatusFailed
+	}
+	log.Info().Interface("summary", report.Summary).Msgf("verify report saved : %s", fileName)
+
+	if !report.Passed() {
+		log.Error().Msg("target does not match source")
+		return models.StatusFailed
+	}
+	log.Info().Msg("Successfully verified")
 	return models.StatusCompleted
 }
 
-func handleObjectStorageBackupTask(params models.BasicDataTask) models.Status {
+func handleObjectStorageBackupTask(ctx context.Context, params models.BasicDataTask) models.Status {
 	log.Info().Msg("Handling object storage backup task")
 	var OSC *osc.OSController
 	var err error
 	log.Info().Msg("User Information")
-	OSC, err = auth.GetOS(&params.SourcePoint)
+	OSC, err = auth.GetOS(&params.SourcePoint, osc.WithContext(ctx), osc.WithProgress(progress.Lookup(params.TaskID)), osc.WithQuota(quota.Lookup(params.TaskID)), osc.WithMetadata(params.PreserveMetadata))
 	if err != nil {
 		log.Error().Err(err).Msg("OSController error importing into objectstorage ")
 		return models.StatusFailed
@@ -873,6 +1063,33 @@ func handleObjectStorageBackupTask(params models.BasicDataTask) models.Status {
 		return models.StatusFailed
 	}
 
+	// a deduplicating backup into a bucket writes nothing locally
+	if !params.Dedup || params.TargetPoint.Bucket == "" {
+		if !preflightBackup(params, objectStorageBackupSize(OSC, flt)) {
+			return models.StatusFailed
+		}
+	}
+
+	if params.Dedup {
+		return runDedupBackup(ctx, params, OSC, flt)
+	}
+	if refuseObjectLock(params) {
+		return models.StatusFailed
+	}
+
+	if catalogued(params) {
+		log.Info().Msg("Launch OSController GetGeneration")
+		gen, err := OSC.GetGeneration(params.TargetPoint.Path, newGeneration(params), flt)
+		if err != nil {
+			log.Error().Err(err).Msg("GetGeneration error exporting into objectstorage ")
+			return models.StatusFailed
+		}
+		log.Info().Msgf("successfully backup generation %s (%d of %d objects fetched) : %s",
+			gen.ID, gen.NewObjects, gen.Objects, params.TargetPoint.Path)
+		applyRetention(params)
+		return models.StatusCompleted
+	}
+
 	log.Info().Msg("Launch OSController MGet")
 	if err := OSC.MGet(params.TargetPoint.Path, flt); err != nil {
 		log.Error().Err(err).Msg("MGet error exporting into objectstorage ")
@@ -882,31 +1099,60 @@ func handleObjectStorageBackupTask(params models.BasicDataTask) models.Status {
 	return models.StatusCompleted
 }
 
-func handleObjectStorageRestoreTask(params models.BasicDataTask) models.Status {
+func handleObjectStorageRestoreTask(ctx context.Context, params models.BasicDataTask) models.Status {
 	log.Info().Msg("Handling object storage restore task")
 	var OSC *osc.OSController
 	var err error
 	log.Info().Msg("User Information")
-	OSC, err = auth.GetOS(&params.TargetPoint)
+	OSC, err = auth.GetOS(&params.TargetPoint, osc.WithContext(ctx), osc.WithProgress(progress.Lookup(params.TaskID)), osc.WithQuota(quota.Lookup(params.TaskID)), osc.WithMetadata(params.PreserveMetadata))
 	if err != nil {
 		log.Error().Err(err).Msg("OSController error importing into objectstorage ")
 		return models.StatusFailed
 	}
 
-	log.Info().Msg("Launch OSController MGet")
-	if err := OSC.MPut(params.SourcePoint.Path); err != nil {
-		log.Error().Err(err).Msg("MPut error importing into objectstorage ")
+	flt, err := filtering.FromParams(params.SourceFilter)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid sourceFilter")
+		return models.StatusFailed
+	}
+
+	opts := osc.RestoreOptions{Filter: flt, TargetPrefix: params.TargetPrefix, Overwrite: params.Overwrite}
+	if params.Dedup {
+		return runDedupRestore(ctx, params, OSC, opts)
+	}
+
+	gen, err := selectGeneration(params)
+	if err != nil {
+		log.Error().Err(err).Msg("backup generation error")
+		return models.StatusFailed
+	}
+
+	var objList []osc.LocalObject
+	if gen != nil {
+		log.Info().Msgf("restore backup generation %s", gen.ID)
+		objList, err = osc.BackupObjects(params.SourcePoint.Path, gen.ID)
+	} else {
+		objList, err = osc.DirObjects(params.SourcePoint.Path)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("restore source error")
+		return models.StatusFailed
+	}
+
+	log.Info().Msg("Launch OSController Restore")
+	if err := OSC.Restore(objList, opts); err != nil {
+		log.Error().Err(err).Msg("Restore error importing into objectstorage ")
 		return models.StatusFailed
 	}
 	log.Info().Msgf("successfully restore : %s", params.SourcePoint.Path)
 	return models.StatusCompleted
 }
 
-func handleRDBMSGenerateTask(params models.BasicDataTask) models.Status {
+func handleRDBMSGenerateTask(ctx context.Context, params models.BasicDataTask) models.Status {
 	var RDBC *rdbc.RDBController
 	var err error
 	log.Info().Msgf("User Information")
-	RDBC, err = auth.GetRDMS(&params.TargetPoint)
+	RDBC, err = auth.GetRDMS(&params.TargetPoint, rdbc.WithContext(ctx))
 	if err != nil {
 		log.Error().Msgf("RDBController error importing into rdbms : %v", err)
 		return models.StatusFailed
@@ -928,14 +1174,9 @@ func handleRDBMSGenerateTask(params models.BasicDataTask) models.Status {
 	}
 
 	for _, sqlPath := range sqlList {
-		data, err := os.ReadFile(sqlPath)
-		if err != nil {
-			log.Error().Msgf("ReadFile error : %v", err)
-			return models.StatusFailed
-		}
 		log.Info().Msgf("Import start: %s", sqlPath)
-		if err := RDBC.Put(string(data)); err != nil {
-			log.Error().Msgf("Put error importing into rdbms")
+		if err := putFile(RDBC, sqlPath); err != nil {
+			log.Error().Msgf("Put error importing into rdbms : %v", err)
 			return models.StatusFailed
 		}
 		log.Info().Msgf("Import success: %s", sqlPath)
@@ -944,10 +1185,10 @@ func handleRDBMSGenerateTask(params models.BasicDataTask) models.Status {
 	return models.StatusCompleted
 }
 
-func handleRDBMSDeleteTask(params models.BasicDataTask) models.Status {
+func handleRDBMSDeleteTask(ctx context.Context, params models.BasicDataTask) models.Status {
 	var RDBC *rdbc.RDBController
 	var err error
-	RDBC, err = auth.GetRDMS(&params.TargetPoint)
+	RDBC, err = auth.GetRDMS(&params.TargetPoint, rdbc.WithContext(ctx))
 
 	if err != nil {
 		log.Error().Msgf("RDBController error deleting into rdbms : %v", err)
@@ -969,27 +1210,46 @@ func handleRDBMSDeleteTask(params models.BasicDataTask) models.Status {
 	return models.StatusCompleted
 }
 
-func handleRDBMSMigrateTask(params models.BasicDataTask) models.Status {
+func handleRDBMSMigrateTask(ctx context.Context, params models.BasicDataTask) models.Status {
 	log.Info().Msg("Handling RDBMS migrate task")
 	var srcRDBC *rdbc.RDBController
 	var srcErr error
 	var dstRDBC *rdbc.RDBController
 	var dstErr error
 	log.Info().Msg("Source Information")
-	srcRDBC, srcErr = auth.GetRDMS(&params.SourcePoint)
+	srcRDBC, srcErr = auth.GetRDMS(&params.SourcePoint, rdbc.WithContext(ctx), rdbc.WithProgress(progress.Lookup(params.TaskID)), rdbc.WithMaxPacketSize(params.MaxPacketSize),
+		rdbc.WithWorkers(params.Workers), rdbc.WithChunkRows(params.ChunkRows), rdbc.WithConsistency(params.Consistency),
+		rdbc.WithDefiner(params.Definer), rdbc.WithUsers(params.Users), rdbc.WithCharset(params.Charset), rdbc.WithTables(params.Tables))
 	if srcErr != nil {
 		log.Error().Err(srcErr).Msg("RDBController error migration into rdbms ")
 		return models.StatusFailed
 	}
+	if !checkCharset(srcRDBC, params) {
+		return models.StatusFailed
+	}
 	log.Info().Msg("Target Information")
-	dstRDBC, dstErr = auth.GetRDMS(&params.TargetPoint)
+	dstRDBC, dstErr = auth.GetRDMS(&params.TargetPoint, rdbc.WithContext(ctx), rdbc.WithBulkLoad(params.BulkLoad), rdbc.WithDisableChecks(params.DisableChecks))
 	if dstErr != nil {
 		log.Error().Err(dstErr).Msg("RDBController error migration into rdbms ")
 		return models.StatusFailed
 	}
 
+	report, ok := translateRDBMS(srcRDBC, dstRDBC, params)
+	if !ok {
+		return models.StatusFailed
+	}
+	if params.TranslateOnly {
+		log.Info().Msg("translateOnly is set, nothing is written")
+		return models.StatusCompleted
+	}
+
 	log.Info().Msg("Launch RDBController Copy")
-	if err := srcRDBC.Copy(dstRDBC, params.SourcePoint.DatabaseName); err != nil {
+	if report != nil {
+		if err := srcRDBC.CopyTranslated(dstRDBC, report); err != nil {
+			log.Error().Err(err).Msg("Copy error copying into rdbms ")
+			return models.StatusFailed
+		}
+	} else if err := srcRDBC.Copy(dstRDBC, params.SourcePoint.DatabaseName); err != nil {
 		log.Error().Err(err).Msg("Copy error copying into rdbms ")
 		return models.StatusFailed
 	}
@@ -998,18 +1258,40 @@ func handleRDBMSMigrateTask(params models.BasicDataTask) models.Status {
 
 }
 
-func handleRDBMSBackupTask(params models.BasicDataTask) models.Status {
+func handleRDBMSBackupTask(ctx context.Context, params models.BasicDataTask) models.Status {
+	if refuseObjectLock(params) {
+		return models.StatusFailed
+	}
 	log.Info().Msg("Handling RDBMS backup task")
 	var RDBC *rdbc.RDBController
 	var err error
 	log.Info().Msg("User Information")
-	RDBC, err = auth.GetRDMS(&params.SourcePoint)
+	// spool files go to the target path, whose free space the pre-flight check counts them against
+	RDBC, err = auth.GetRDMS(&params.SourcePoint, rdbc.WithContext(ctx), rdbc.WithProgress(progress.Lookup(params.TaskID)), rdbc.WithMaxPacketSize(params.MaxPacketSize),
+		rdbc.WithWorkers(params.Workers), rdbc.WithChunkRows(params.ChunkRows), rdbc.WithSpoolDir(params.TargetPoint.Path), rdbc.WithConsistency(params.Consistency),
+		rdbc.WithDefiner(params.Definer), rdbc.WithUsers(params.Users), rdbc.WithCharset(params.Charset), rdbc.WithTables(params.Tables))
 	if err != nil {
 		log.Error().Err(err).Msg("RDBController error importing into rdbms ")
 		return models.StatusFailed
 	}
+	if !preflightBackup(params, rdbmsBackupSize(RDBC, params)) {
+		return models.StatusFailed
+	}
+	if !catalogued(params) {
+		return runRDBMSBackup(RDBC, params, params.TargetPoint.Path)
+	}
+	return runGenerationBackup(params, func(outDir string) models.Status {
+		return runRDBMSBackup(RDBC, params, outDir)
+	})
+}
+
+// runRDBMSBackup exports the source database as SQL files into outDir.
+func runRDBMSBackup(RDBC *rdbc.RDBController, params models.BasicDataTask, outDir string) models.Status {
+	if !checkCharset(RDBC, params) {
+		return models.StatusFailed
+	}
 
-	err = os.MkdirAll(params.TargetPoint.Path, 0755)
+	err := os.MkdirAll(outDir, 0755)
 	if err != nil {
 		log.Error().Err(err).Msg("MkdirAll error ")
 		return models.StatusFailed
@@ -1028,48 +1310,53 @@ func handleRDBMSBackupTask(params models.BasicDataTask) models.Status {
 	}
 	dbList = []string{sourceDB}
 
-	var sqlData string
 	for _, db := range dbList {
-		sqlData = ""
 		log.Info().Msgf("Export start: %s", db)
-		if err := RDBC.Get(db, &sqlData); err != nil {
-			log.Error().Err(err).Msg("Get error ")
+		file, err := os.Create(filepath.Join(outDir, fmt.Sprintf("%s.sql", db)))
+		if err != nil {
+			log.Error().Err(err).Msg("File create error ")
 			return models.StatusFailed
 		}
 
-		file, err := os.Create(filepath.Join(params.TargetPoint.Path, fmt.Sprintf("%s.sql", db)))
-		if err != nil {
-			log.Error().Err(err).Msg("File create error ")
+		// the dump is streamed into the file, a partial dump is removed
+		if err := RDBC.Get(db, file); err != nil {
+			log.Error().Err(err).Msg("Get error ")
+			file.Close()
+			os.Remove(file.Name())
 			return models.StatusFailed
 		}
-		defer file.Close()
 
-		_, err = file.WriteString(sqlData)
-		if err != nil {
+		if err := file.Close(); err != nil {
 			log.Error().Err(err).Msg("File write error ")
 			return models.StatusFailed
 		}
 		log.Info().Msgf("successfully exported : %s", file.Name())
-		file.Close()
 	}
-	log.Info().Msgf("successfully backup : %s", params.TargetPoint.Path)
+	log.Info().Msgf("successfully backup : %s", outDir)
 	return models.StatusCompleted
 
 }
 
-func handleRDBMSRestoreTask(params models.BasicDataTask) models.Status {
+func handleRDBMSRestoreTask(ctx context.Context, params models.BasicDataTask) models.Status {
 	log.Info().Msg("Handling RDBMS restore task")
 	var RDBC *rdbc.RDBController
 	var err error
 	log.Info().Msg("User Information")
-	RDBC, err = auth.GetRDMS(&params.TargetPoint)
+	tracker := progress.Lookup(params.TaskID)
+	RDBC, err = auth.GetRDMS(&params.TargetPoint, rdbc.WithContext(ctx), rdbc.WithDisableChecks(params.DisableChecks), rdbc.WithProgress(tracker))
 	if err != nil {
 		log.Error().Err(err).Msg("RDBController error importing into rdbms ")
 		return models.StatusFailed
 	}
 
+	srcDir, err := restoreDir(params)
+	if err != nil {
+		log.Error().Err(err).Msg("backup generation error")
+		return models.StatusFailed
+	}
+
 	sqlList := []string{}
-	err = filepath.Walk(params.SourcePoint.Path, func(path string, info fs.FileInfo, err error) error {
+	err = filepath.Walk(srcDir, func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -1083,17 +1370,19 @@ func handleRDBMSRestoreTask(params models.BasicDataTask) models.Status {
 		return models.StatusFailed
 	}
 
+	tracker.AddTotal(int64(len(sqlList)), 0)
 	for _, sqlPath := range sqlList {
-		data, err := os.ReadFile(sqlPath)
-		if err != nil {
-			log.Error().Err(err).Msg("ReadFile error ")
-			return models.StatusFailed
+		if ctx.Err() != nil {
+			return models.StatusCancelled
 		}
+		tracker.SetCurrent(sqlPath)
 		log.Info().Msgf("Import start: %s", sqlPath)
-		if err := RDBC.Put(string(data)); err != nil {
-			log.Error().Msg("Put error importing into rdbms")
+		if err := putFile(RDBC, sqlPath); err != nil {
+			log.Error().Err(err).Msg("Put error importing into rdbms")
+			tracker.Fail(sqlPath, err)
 			return models.StatusFailed
 		}
+		tracker.ObjectDone(sqlPath)
 		log.Info().Msgf("Import success: %s", sqlPath)
 	}
 	log.Info().Msgf("successfully restore : %s", params.SourcePoint.Path)
@@ -1101,11 +1390,21 @@ func handleRDBMSRestoreTask(params models.BasicDataTask) models.Status {
 
 }
 
-func handleNRDBMSGenerateTask(params models.BasicDataTask) models.Status {
+// putFile imports the .sql file at path, streaming it in statement batches.
+func putFile(RDBC *rdbc.RDBController, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return RDBC.PutReader(f)
+}
+
+func handleNRDBMSGenerateTask(ctx context.Context, params models.BasicDataTask) models.Status {
 
 	var NRDBC *nrdbc.NRDBController
 	var err error
-	NRDBC, err = auth.GetNRDMS(&params.TargetPoint)
+	NRDBC, err = auth.GetNRDMS(&params.TargetPoint, nrdbc.WithContext(ctx))
 	if err != nil {
 		log.Error().Msgf("NRDBController error importing into nrdbms : %v", err)
 		return models.StatusFailed
@@ -1156,11 +1455,11 @@ func handleNRDBMSGenerateTask(params models.BasicDataTask) models.Status {
 	return models.StatusCompleted
 }
 
-func handleNRDBMSDeleteTask(params models.BasicDataTask) models.Status {
+func handleNRDBMSDeleteTask(ctx context.Context, params models.BasicDataTask) models.Status {
 
 	var NRDBC *nrdbc.NRDBController
 	var err error
-	NRDBC, err = auth.GetNRDMS(&params.TargetPoint)
+	NRDBC, err = auth.GetNRDMS(&params.TargetPoint, nrdbc.WithContext(ctx))
 
 	if err != nil {
 		log.Error().Msgf("NRDBController error deleting into nrdbms : %v", err)
@@ -1183,20 +1482,20 @@ func handleNRDBMSDeleteTask(params models.BasicDataTask) models.Status {
 	return models.StatusCompleted
 }
 
-func handleNRDBMSMigrateTask(params models.BasicDataTask) models.Status {
+func handleNRDBMSMigrateTask(ctx context.Context, params models.BasicDataTask) models.Status {
 	log.Info().Msg("Handling NRDBMS migrate task")
 	var srcNRDBC *nrdbc.NRDBController
 	var srcErr error
 	var dstNRDBC *nrdbc.NRDBController
 	var dstErr error
 	log.Info().Msg("Source Information")
-	srcNRDBC, srcErr = auth.GetNRDMS(&params.SourcePoint)
+	srcNRDBC, srcErr = auth.GetNRDMS(&params.SourcePoint, nrdbc.WithContext(ctx), nrdbc.WithProgress(progress.Lookup(params.TaskID)))
 	if srcErr != nil {
 		log.Error().Err(srcErr).Msg("NRDBController error migration into nrdbms ")
 		return models.StatusFailed
 	}
 	log.Info().Msg("Target Information")
-	dstNRDBC, dstErr = auth.GetNRDMS(&params.TargetPoint)
+	dstNRDBC, dstErr = auth.GetNRDMS(&params.TargetPoint, nrdbc.WithContext(ctx))
 	if dstErr != nil {
 		log.Error().Err(dstErr).Msg("NRDBController error migration into nrdbms ")
 		return models.StatusFailed
@@ -1213,11 +1512,24 @@ func handleNRDBMSMigrateTask(params models.BasicDataTask) models.Status {
 }
 
 // S -> T
-func handleNRDBMSBackupTask(params models.BasicDataTask) models.Status {
+func handleNRDBMSBackupTask(ctx context.Context, params models.BasicDataTask) models.Status {
+	if refuseObjectLock(params) {
+		return models.StatusFailed
+	}
+	if !catalogued(params) {
+		return runNRDBMSBackup(ctx, params, params.TargetPoint.Path)
+	}
+	return runGenerationBackup(params, func(outDir string) models.Status {
+		return runNRDBMSBackup(ctx, params, outDir)
+	})
+}
+
+// runNRDBMSBackup exports the source tables as JSON files into outDir.
+func runNRDBMSBackup(ctx context.Context, params models.BasicDataTask, outDir string) models.Status {
 	log.Info().Msg("Handling NRDBMS backup task")
 	var NRDBC *nrdbc.NRDBController
 	var err error
-	NRDBC, err = auth.GetNRDMS(&params.SourcePoint)
+	NRDBC, err = auth.GetNRDMS(&params.SourcePoint, nrdbc.WithContext(ctx))
 	if err != nil {
 		log.Error().Err(err).Msg("NRDBController error importing into nrdbms ")
 		return models.StatusFailed
@@ -1229,27 +1541,34 @@ func handleNRDBMSBackupTask(params models.BasicDataTask) models.Status {
 		return models.StatusFailed
 	}
 
-	if !utils.DirExists(params.TargetPoint.Path) {
+	if !utils.DirExists(outDir) {
 		log.Info().Msg("directory does not exist")
 		log.Info().Msg("Make Directory")
-		err = os.MkdirAll(params.TargetPoint.Path, 0755)
+		err = os.MkdirAll(outDir, 0755)
 		if err != nil {
-			log.Info().Msgf("Make Failed 0755 : %s", params.TargetPoint.Path)
+			log.Info().Msgf("Make Failed 0755 : %s", outDir)
 			return models.StatusFailed
 		}
 	}
 
+	tracker := progress.Lookup(params.TaskID)
+	tracker.AddTotal(int64(len(tableList)), 0)
 	var dstData []map[string]interface{}
 	for _, table := range tableList {
+		if ctx.Err() != nil {
+			return models.StatusCancelled
+		}
 		log.Info().Msgf("Export start: %s", table)
+		tracker.SetCurrent(table)
 		dstData = []map[string]interface{}{}
 
 		if err := NRDBC.Get(table, &dstData); err != nil {
 			log.Error().Err(err).Msg("Get error ")
+			tracker.Fail(table, err)
 			return models.StatusFailed
 		}
 
-		file, err := os.Create(filepath.Join(params.TargetPoint.Path, fmt.Sprintf("%s.json", table)))
+		file, err := os.Create(filepath.Join(outDir, fmt.Sprintf("%s.json", table)))
 		if err != nil {
 			log.Error().Err(err).Msg("File create error ")
 			return models.StatusFailed
@@ -1260,28 +1579,36 @@ func handleNRDBMSBackupTask(params models.BasicDataTask) models.Status {
 		encoder.SetIndent("", "    ")
 		if err := encoder.Encode(dstData); err != nil {
 			log.Error().Err(err).Msg("data encoding error ")
+			tracker.Fail(table, err)
 			return models.StatusFailed
 		}
+		tracker.ObjectDone(table)
 		log.Info().Msgf("successfully create File : %s", file.Name())
 	}
-	log.Info().Msgf("successfully backup to : %s", params.TargetPoint.Path)
+	log.Info().Msgf("successfully backup to : %s", outDir)
 	return models.StatusCompleted
 
 }
 
 // Restore S -> T
-func handleNRDBMSRestoreTask(params models.BasicDataTask) models.Status {
+func handleNRDBMSRestoreTask(ctx context.Context, params models.BasicDataTask) models.Status {
 	log.Info().Msg("Handling NRDBMS restore task")
 	var NRDBC *nrdbc.NRDBController
 	var err error
-	NRDBC, err = auth.GetNRDMS(&params.TargetPoint)
+	NRDBC, err = auth.GetNRDMS(&params.TargetPoint, nrdbc.WithContext(ctx))
 	if err != nil {
 		log.Error().Err(err).Msg("NRDBController error importing into nrdbms ")
 		return models.StatusFailed
 	}
 
+	srcDir, err := restoreDir(params)
+	if err != nil {
+		log.Error().Err(err).Msg("backup generation error")
+		return models.StatusFailed
+	}
+
 	jsonList := []string{}
-	err = filepath.Walk(params.SourcePoint.Path, func(path string, info fs.FileInfo, err error) error {
+	err = filepath.Walk(srcDir, func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -1296,13 +1623,20 @@ func handleNRDBMSRestoreTask(params models.BasicDataTask) models.Status {
 		return models.StatusFailed
 	}
 
+	tracker := progress.Lookup(params.TaskID)
+	tracker.AddTotal(int64(len(jsonList)), 0)
 	var srcData []map[string]interface{}
 	for _, jsonFile := range jsonList {
+		if ctx.Err() != nil {
+			return models.StatusCancelled
+		}
+		tracker.SetCurrent(jsonFile)
 		srcData = []map[string]interface{}{}
 
 		file, err := os.Open(jsonFile)
 		if err != nil {
 			log.Error().Err(err).Msg("file open error ")
+			tracker.Fail(jsonFile, err)
 			return models.StatusFailed
 		}
 		defer file.Close()
@@ -1318,8 +1652,10 @@ func handleNRDBMSRestoreTask(params models.BasicDataTask) models.Status {
 		log.Info().Msgf("Import start: %s", fileName)
 		if err := NRDBC.Put(tableName, &srcData); err != nil {
 			log.Error().Msg("Put error importing into nrdbms")
+			tracker.Fail(jsonFile, err)
 			return models.StatusFailed
 		}
+		tracker.ObjectDone(jsonFile)
 		log.Info().Msgf("successfully Restore : %s", params.SourcePoint.Path)
 	}
 	return models.StatusCompleted
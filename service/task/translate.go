@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package task
+
+import (
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/service/rdbc"
+	"github.com/rs/zerolog/log"
+)
+
+// translateRDBMS plans a migration between database engines before anything
+// is written and saves its report. It returns nil for the same engine, and
+// false when the migration must not start.
+func translateRDBMS(src, dst *rdbc.RDBController, params models.BasicDataTask) (*models.TranslationReport, bool) {
+	report, err := src.Translate(dst, params.SourcePoint.DatabaseName)
+	if err != nil {
+		log.Error().Err(err).Msg("translation error")
+		return nil, false
+	}
+	if report == nil {
+		return nil, true
+	}
+
+	report.TaskID = params.TaskID
+	for _, issue := range report.Issues {
+		log.Warn().Msgf("%s %s: %s %s", issue.Level, issue.Object, issue.Construct, issue.Detail)
+	}
+	log.Info().Msgf("translation from %s to %s: %d tables, %d lossy and %d unsupported constructs",
+		report.Source, report.Target, len(report.Tables), report.Count(models.TranslationLossy), report.Count(models.TranslationUnsupported))
+
+	fileName, err := saveReport(ReportTranslation, report.TaskID, report)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to save translation report")
+		return nil, false
+	}
+	log.Info().Msgf("translation report saved: %s", fileName)
+	return report, true
+}
@@ -28,12 +28,13 @@ import (
 //
 //	@ID 			BackupOSPostHandler
 //	@Summary		Export data from objectstorage
-//	@Description	Export data from a objectstorage  to files.
+//	@Description	Export data from a objectstorage  to files, or to a deduplicated repository with dedup. The options are described on the fields of the request body.
 //	@Tags			[Backup]
 //	@Accept			json
 //	@Produce		json
 //	@Param			RequestBody		body	models.BackupTask	true	"Parameters required for backup"
 //	@Success		200			{object}	models.BasicResponse	"Successfully backup data"
+//	@Failure		400			{object}	models.BasicResponse	"Invalid Request"
 //	@Failure		500			{object}	models.BasicResponse	"Internal Server Error"
 //	@Router			/backup/objectstorage [post]
 func BackupOSPostHandler(ctx echo.Context) error {
@@ -48,6 +49,16 @@ func BackupOSPostHandler(ctx echo.Context) error {
 			Error:  nil,
 		})
 	}
+
+	if err := validateTask(params.BasicDataTask); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
 	params.TaskMeta.TaskID = params.OperationId
 	params.TaskMeta.TaskType = models.Backup
 	params.TaskMeta.ServiceType = models.ObejectStorage
@@ -71,12 +82,13 @@ func BackupOSPostHandler(ctx echo.Context) error {
 //
 //	@ID 			BackupRDBPostHandler
 //	@Summary		Export data from MySQL
-//	@Description	Export data from a MySQL database to SQL files.
+//	@Description	Export data from a MySQL database, or a PostgreSQL database with engine postgres on sourcePoint, to SQL files; PostgreSQL rows are written as COPY blocks. The options are described on the fields of the request body.
 //	@Tags			[Backup]
 //	@Accept			json
 //	@Produce		json
 //	@Param			RequestBody		body	models.BackupTask	true	"Parameters required for backup"
 //	@Success		200			{object}	models.BasicResponse	"Successfully backup data"
+//	@Failure		400			{object}	models.BasicResponse	"Invalid Request"
 //	@Failure		500			{object}	models.BasicResponse	"Internal Server Error"
 //	@Router			/backup/rdbms [post]
 func BackupRDBPostHandler(ctx echo.Context) error {
@@ -91,6 +103,16 @@ func BackupRDBPostHandler(ctx echo.Context) error {
 			Error:  nil,
 		})
 	}
+
+	if err := validateTask(params.BasicDataTask); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
 	params.TaskMeta.TaskID = params.OperationId
 	params.TaskMeta.TaskType = models.Backup
 	params.TaskMeta.ServiceType = models.RDBMS
@@ -210,6 +232,50 @@ func GetBackupHandler(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, task)
 }
 
+// GetBackupCatalogHandler godoc
+//
+//	@ID 			GetBackupCatalogHandler
+//	@Summary		Get the catalog of a backup
+//	@Description	Get the generations recorded by a backup task run with incremental mode, a retention policy or a deduplicating repository.
+//	@Tags			[Backup]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.BackupCatalog	"Successfully retrieved the backup catalog"
+//	@Failure		404		{object}	models.BasicResponse	"Catalog not found"
+//	@Router			/backup/{id}/catalog [get]
+func GetBackupCatalogHandler(ctx echo.Context) error {
+	start := time.Now()
+	logger, logstrings := pageLogInit(ctx, "Get-backup-catalog", "Get the catalog of a backup", start)
+	id := ctx.Param("id")
+	manager := task.GetFileScheduleManager()
+	catalog, err := manager.GetBackupCatalog(id)
+	if err != nil {
+		errStr := err.Error()
+		logger.Error().Err(err).Msg(errStr)
+		return ctx.JSON(http.StatusNotFound, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, catalog)
+}
+
+// GetBackupPreflightHandler godoc
+//
+//	@ID 			GetBackupPreflightHandler
+//	@Summary		Get the pre-flight check of a backup
+//	@Description	Get the estimated size, free space, write permission and problems found by the pre-flight check of the last run of a local backup.
+//	@Tags			[Backup]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.PreflightReport	"Successfully retrieved the pre-flight check"
+//	@Failure		404		{object}	models.BasicResponse	"Report not found"
+//	@Router			/backup/{id}/preflight [get]
+func GetBackupPreflightHandler(ctx echo.Context) error {
+	return getReport(ctx, "Get-backup-preflight", "Get the pre-flight check of a backup", task.ReportPreflight, &models.PreflightReport{})
+}
+
 // UpdateBackupHandler godoc
 //
 //	@ID 			UpdateBackupHandler
@@ -221,6 +287,7 @@ func GetBackupHandler(ctx echo.Context) error {
 //	@Param			id			path	string	true	"Task ID"
 //	@Param			RequestBody	body	models.Schedule	true	"Parameters required for updating a Task"
 //	@Success		200			{object}	models.BasicResponse	"Successfully updated the Task"
+//	@Failure		400			{object}	models.BasicResponse	"Invalid Request"
 //	@Failure		404			{object}	models.BasicResponse	"Task not found"
 //	@Failure		500			{object}	models.BasicResponse	"Internal Server Error"
 //	@Router			/backup/{id} [put]
@@ -237,6 +304,16 @@ func UpdateBackupHandler(ctx echo.Context) error {
 			Error:  &errStr,
 		})
 	}
+
+	if err := validateTask(params.BasicDataTask); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
 	manager := task.GetFileScheduleManager()
 	if err := manager.UpdateTasksByType(models.Backup, id, params.BasicDataTask); err != nil {
 		errStr := err.Error()
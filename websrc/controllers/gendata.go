@@ -38,7 +38,11 @@ func genData(params models.GenFileParams, logger *zerolog.Logger) error {
 	if cast.ToBool(params.CheckSQL) {
 		logger.Info().Msg("Start creating SQL dummy")
 		sql, _ := strconv.Atoi(params.SizeSQL)
-		if err := structured.GenerateRandomSQL(params.DummyPath, sql); err != nil {
+		generate := structured.GenerateRandomSQL
+		if params.SQLEngine == "postgres" {
+			generate = structured.GenerateRandomPostgresSQL
+		}
+		if err := generate(params.DummyPath, sql); err != nil {
 			logger.Error().Err(err).Msg("Failed to create SQL dummy")
 			return err
 		}
@@ -128,7 +132,11 @@ func genData(params models.GenFileParams, logger *zerolog.Logger) error {
 	if cast.ToBool(params.CheckServerSQL) {
 		logger.Info().Msg("Start creating SQL dummy")
 		sql, _ := strconv.Atoi(params.SizeServerSQL)
-		if err := structured.GenerateRandomSQLWithServer(params.DummyPath, sql); err != nil {
+		generate := structured.GenerateRandomSQLWithServer
+		if params.SQLEngine == "postgres" {
+			generate = structured.GenerateRandomPostgresSQLWithServer
+		}
+		if err := generate(params.DummyPath, sql); err != nil {
 			logger.Error().Err(err).Msg("Failed to create SQL dummy")
 			return err
 		}
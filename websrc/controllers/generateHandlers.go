@@ -97,7 +97,7 @@ func GenerateObjectStoragePostHandler(ctx echo.Context) error {
 //
 //	@ID 			GenerateRDBMSPostHandler
 //	@Summary		Generate test data on RDBMS
-//	@Description	Generate test data on RDBMS
+//	@Description	Generate test data on RDBMS. engine postgres on targetPoint generates it in a PostgreSQL database.
 //	@Tags			[Generate]
 //	@Accept			json
 //	@Produce		json
@@ -131,6 +131,7 @@ func GenerateRDBMSPostHandler(ctx echo.Context) error {
 	params.Dummy.DummyPath = tmpDir
 	params.Dummy.CheckServerSQL = true
 	params.Dummy.SizeServerSQL = "1"
+	params.Dummy.SQLEngine = params.TargetPoint.Engine
 
 	if !dummyCreate(logger, start, params.Dummy) {
 		return ctx.JSON(http.StatusInternalServerError, models.BasicResponse{
@@ -28,7 +28,7 @@ import (
 //
 //	@ID 			MigrationObjectstoragePostHandler
 //	@Summary		Migrate data from ObjectStorage to ObjectStorage
-//	@Description	Migrate data from ObjectStorage to ObjectStorage.
+//	@Description	Migrate data from ObjectStorage to ObjectStorage, to one target or to several with targetPoints. The options are described on the fields of the request body.
 //	@Tags			[Migrate]
 //	@Accept			json
 //	@Produce		json
@@ -117,7 +117,7 @@ func MigrationNRDBMSPostHandler(ctx echo.Context) error {
 //
 //	@ID 			MigrationRDBMSPostHandler
 //	@Summary		Migrate data from RDBMS to RDBMS
-//	@Description	Migrate data from RDBMS to RDBMS.
+//	@Description	Migrate data from RDBMS to RDBMS. engine on sourcePoint and targetPoint selects mysql (default) or postgres; a MySQL source is translated for a PostgreSQL target. The options are described on the fields of the request body.
 //	@Tags			[Migrate]
 //	@Accept			json
 //	@Produce		json
@@ -138,6 +138,16 @@ func MigrationRDBMSPostHandler(ctx echo.Context) error {
 			Error:  nil,
 		})
 	}
+
+	if err := validateTask(params.BasicDataTask); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
 	params.TaskMeta.TaskID = params.OperationId
 	params.TaskMeta.TaskType = models.Migrate
 	params.TaskMeta.ServiceType = models.RDBMS
@@ -214,6 +224,66 @@ func GetMigrateHandler(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, task)
 }
 
+// GetMigrateTargetsHandler godoc
+//
+//	@ID 			GetMigrateTargetsHandler
+//	@Summary		Get the per-target results of a migration
+//	@Description	Get the status of every target of an object storage migration run with targetPoints.
+//	@Tags			[Migrate]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.FanOutReport		"Per-target results"
+//	@Failure		404		{object}	models.BasicResponse	"Report not found"
+//	@Router			/migrate/{id}/targets [get]
+func GetMigrateTargetsHandler(ctx echo.Context) error {
+	return getReport(ctx, "Get-migrate-targets", "Get the targets of a migration", task.ReportFanOut, &models.FanOutReport{})
+}
+
+// GetMigrateTransformsHandler godoc
+//
+//	@ID 			GetMigrateTransformsHandler
+//	@Summary		Get the transformed objects of a migration
+//	@Description	Get the source and the written objects, with their size and SHA-256, of an object storage migration run with transforms.
+//	@Tags			[Migrate]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.TransformReport	"Transformed objects"
+//	@Failure		404		{object}	models.BasicResponse	"Report not found"
+//	@Router			/migrate/{id}/transforms [get]
+func GetMigrateTransformsHandler(ctx echo.Context) error {
+	return getReport(ctx, "Get-migrate-transforms", "Get the transformed objects of a migration", task.ReportTransform, &models.TransformReport{})
+}
+
+// GetMigratePIIHandler godoc
+//
+//	@ID 			GetMigratePIIHandler
+//	@Summary		Get the personal data found by a migration
+//	@Description	Get the objects in which the piiScan of an object storage migration found personal data, with masked samples and the action of the policy.
+//	@Tags			[Migrate]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.PIIReport		"Personal data findings"
+//	@Failure		404		{object}	models.BasicResponse	"Report not found"
+//	@Router			/migrate/{id}/pii [get]
+func GetMigratePIIHandler(ctx echo.Context) error {
+	return getReport(ctx, "Get-migrate-pii", "Get the personal data found by a migration", task.ReportPII, &models.PIIReport{})
+}
+
+// GetMigrateTranslationHandler godoc
+//
+//	@ID 			GetMigrateTranslationHandler
+//	@Summary		Get the translation report of a migration
+//	@Description	Get the plan of an RDBMS migration between engines, made before anything is written: the target type and conversion of every column, the statements run before and after the rows are copied, and the lossy or unsupported constructs.
+//	@Tags			[Migrate]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.TranslationReport	"Translation report"
+//	@Failure		404		{object}	models.BasicResponse		"Report not found"
+//	@Router			/migrate/{id}/translation [get]
+func GetMigrateTranslationHandler(ctx echo.Context) error {
+	return getReport(ctx, "Get-migrate-translation", "Get the translation report of a migration", task.ReportTranslation, &models.TranslationReport{})
+}
+
 // UpdateMigrateHandler godoc
 //
 //	@ID 			UpdateMigrateHandler
@@ -225,6 +295,7 @@ func GetMigrateHandler(ctx echo.Context) error {
 //	@Param			id			path	string	true	"Task ID"
 //	@Param			RequestBody	body	models.Schedule	true	"Parameters required for updating a Task"
 //	@Success		200			{object}	models.BasicResponse	"Successfully updated the Task"
+//	@Failure		400			{object}	models.BasicResponse	"Invalid Request"
 //	@Failure		404			{object}	models.BasicResponse	"Task not found"
 //	@Failure		500			{object}	models.BasicResponse	"Internal Server Error"
 //	@Router			/migrate/{id} [put]
@@ -241,6 +312,16 @@ func UpdateMigrateHandler(ctx echo.Context) error {
 			Error:  &errStr,
 		})
 	}
+
+	if err := validateTask(params.BasicDataTask); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
 	manager := task.GetFileScheduleManager()
 	if err := manager.UpdateTasksByType(models.Migrate, id, params.BasicDataTask); err != nil {
 		errStr := err.Error()
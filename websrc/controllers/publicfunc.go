@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -45,6 +46,7 @@ import (
 	"github.com/cloud-barista/mc-data-manager/service/nrdbc"
 	"github.com/cloud-barista/mc-data-manager/service/osc"
 	"github.com/cloud-barista/mc-data-manager/service/rdbc"
+	"github.com/cloud-barista/mc-data-manager/service/task"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cast"
@@ -140,6 +142,40 @@ func jobEnd(logger *zerolog.Logger, endInfo string, startTime time.Time) {
 	logger.Info().Str("Elapsed time", end.Sub(startTime).String())
 }
 
+// getReport responds with the report of kind of the task in the id path parameter, loaded into report.
+func getReport(ctx echo.Context, pageName, pageInfo, kind string, report any) error {
+	start := time.Now()
+	logger, logstrings := pageLogInit(ctx, pageName, pageInfo, start)
+
+	if err := task.LoadReport(kind, ctx.Param("id"), report); err != nil {
+		errStr := err.Error()
+		logger.Error().Err(err).Msg(errStr)
+		return ctx.JSON(http.StatusNotFound, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// validateTask returns an error for the policies and modes of params that are not known.
+func validateTask(params models.BasicDataTask) error {
+	if !params.Overwrite.Valid() {
+		return fmt.Errorf("unknown overwrite policy %q, want overwrite, skip or newer", params.Overwrite)
+	}
+	if !params.Consistency.Valid() {
+		return fmt.Errorf("unknown consistency mode %q, want snapshot, lock or none", params.Consistency)
+	}
+	if !params.Conflict.Valid() {
+		return fmt.Errorf("unknown conflict policy %q, want newest, source or keep-both", params.Conflict)
+	}
+	if !params.Preflight.Valid() {
+		return fmt.Errorf("unknown preflight mode %q, want enforce, warn or off", params.Preflight)
+	}
+	return nil
+}
+
 func createDummyTemp(logger *zerolog.Logger, startTime time.Time) (string, bool) {
 	logger.Info().Msg("Create a temporary directory where dummy data will be created")
 	tmpDir, err := os.MkdirTemp("", "datamold-dummy")
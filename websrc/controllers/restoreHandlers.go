@@ -28,12 +28,13 @@ import (
 //
 //	@ID 			RestoreOSPostHandler
 //	@Summary		Restore data from objectstorage
-//	@Description	Restore objectstorage from files to a objectstorage
+//	@Description	Restore objectstorage from files to a objectstorage. sourceFilter selects the objects to restore, targetPrefix places them under a prefix and overwrite (overwrite, skip, newer) decides what happens to existing objects. With dedup, the source is a deduplicating repository and generation or pointInTime selects the snapshot.
 //	@Tags			[Restore]
 //	@Accept			json
 //	@Produce		json
 //	@Param			RequestBody		body	models.RestoreTask	true	"Parameters required for Restore"
 //	@Success		200			{object}	models.BasicResponse	"Successfully Restore data"
+//	@Failure		400			{object}	models.BasicResponse	"Unknown overwrite policy"
 //	@Failure		500			{object}	models.BasicResponse	"Internal Server Error"
 //	@Router			/restore/objectstorage [post]
 func RestoreOSPostHandler(ctx echo.Context) error {
@@ -48,6 +49,16 @@ func RestoreOSPostHandler(ctx echo.Context) error {
 			Error:  nil,
 		})
 	}
+
+	if err := validateTask(params.BasicDataTask); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
 	params.TaskMeta.TaskID = params.OperationId
 	params.TaskMeta.TaskType = models.Restore
 	params.TaskMeta.ServiceType = models.ObejectStorage
@@ -71,7 +82,7 @@ func RestoreOSPostHandler(ctx echo.Context) error {
 //
 //	@ID 			RestoreRDBPostHandler
 //	@Summary		Restore data from MySQL
-//	@Description	Restore MySQL from MySQL files to a MySQL database
+//	@Description	Restore MySQL from MySQL files to a MySQL database, or PostgreSQL files to a PostgreSQL database with engine postgres on targetPoint. disableChecks turns off unique and foreign key checks during the import.
 //	@Tags			[Restore]
 //	@Accept			json
 //	@Produce		json
@@ -237,6 +248,16 @@ func UpdateRestoreHandler(ctx echo.Context) error {
 			Error:  &errStr,
 		})
 	}
+
+	if err := validateTask(params.BasicDataTask); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
 	manager := task.GetFileScheduleManager()
 	if err := manager.UpdateTasksByType(models.Restore, id, params.BasicDataTask); err != nil {
 		errStr := err.Error()
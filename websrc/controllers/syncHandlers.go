@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/service/task"
+	"github.com/labstack/echo/v4"
+)
+
+// SyncOSPostHandler godoc
+//
+//	@ID 			SyncOSPostHandler
+//	@Summary		Synchronise two ObjectStorages in both directions
+//	@Description	Copy objects changed on one side to the other side and propagate deletions, using the state of the previous run with the same operation ID. Objects changed on both sides are resolved by conflict: newest (default), source or keep-both. A task with the operation ID that already exists is refused, it is deleted before the sync runs again; a sync task with taskType sync can also be registered in a schedule to run periodically.
+//	@Tags			[ObjectStorage]
+//	@Accept			json
+//	@Produce		json
+//	@Param			RequestBody		body	models.SyncTask	true	"Parameters required for synchronisation"
+//	@Success		200			{object}	models.SyncReport		"Sync report"
+//	@Failure		400			{object}	models.BasicResponse	"operationId missing"
+//	@Failure		409			{object}	models.BasicResponse	"Task with the operationId already exists"
+//	@Failure		500			{object}	models.BasicResponse	"Internal Server Error"
+//	@Router			/objectstorage/sync [post]
+func SyncOSPostHandler(ctx echo.Context) error {
+	start := time.Now()
+
+	logger, logstrings := pageLogInit(ctx, "sync", "Sync objectstorage with objectstorage", start)
+
+	params := models.DataTask{}
+	if !getDataWithReBind(logger, start, ctx, &params) {
+		return ctx.JSON(http.StatusInternalServerError, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  nil,
+		})
+	}
+
+	if err := validateTask(params.BasicDataTask); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	if params.OperationId == "" {
+		errStr := "operationId is required, the sync state is kept per operation"
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	params.TaskMeta.TaskID = params.OperationId
+	params.TaskMeta.TaskType = models.Sync
+	params.TaskMeta.ServiceType = models.ObejectStorage
+	manager := task.GetFileScheduleManager()
+	if err := manager.CreateTask(params); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusConflict, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	// A sync with failed objects still produces a report, so the result of the task is not checked here.
+	manager.RunTask(params)
+
+	report := &models.SyncReport{}
+	if err := task.LoadReport(task.ReportSync, params.TaskMeta.TaskID, report); err != nil {
+		errStr := err.Error()
+		logger.Error().Err(err).Msg("sync report load failed")
+		return ctx.JSON(http.StatusInternalServerError, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	jobEnd(logger, "Successfully synchronised data", start)
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// GetSyncReportHandler godoc
+//
+//	@ID 			GetSyncReportHandler
+//	@Summary		Get the report of the last sync run
+//	@Description	Get the report of the last run of a sync task, including scheduled runs.
+//	@Tags			[ObjectStorage]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.SyncReport		"Sync report"
+//	@Failure		404		{object}	models.BasicResponse	"Report not found"
+//	@Router			/objectstorage/sync/{id} [get]
+func GetSyncReportHandler(ctx echo.Context) error {
+	return getReport(ctx, "Get-sync-report", "Get a sync report", task.ReportSync, &models.SyncReport{})
+}
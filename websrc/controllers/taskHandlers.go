@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/pkg/progress"
+	"github.com/cloud-barista/mc-data-manager/pkg/quota"
 	"github.com/cloud-barista/mc-data-manager/service/task"
 	"github.com/labstack/echo/v4"
 )
@@ -125,6 +127,158 @@ func (tc *TaskController) GetTaskHandler(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, task)
 }
 
+// GetTaskProgressHandler godoc
+//
+//	@ID 			GetTaskProgressHandler
+//	@Summary		Get the progress of a Task
+//	@Description	Get the live progress of a running or finished Task: transferred objects and bytes, throughput, ETA, the current object and recent errors.
+//	@Tags			[Task]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.TaskProgress	"Successfully retrieved the progress of a Task"
+//	@Failure		404		{object}	models.BasicResponse	"Progress not found"
+//	@Router			/tasks/{id}/progress [get]
+func (tc *TaskController) GetTaskProgressHandler(ctx echo.Context) error {
+	start := time.Now()
+	logger, logstrings := pageLogInit(ctx, "Get-task-progress", "Get the progress of a task", start)
+	id := ctx.Param("id")
+	tracker, ok := progress.Get(id)
+	if !ok {
+		errStr := "progress not found"
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusNotFound, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, tracker.Snapshot())
+}
+
+// GetTaskQuotaHandler godoc
+//
+//	@ID 			GetTaskQuotaHandler
+//	@Summary		Get the transfer quota usage of a Task
+//	@Description	Get the planned and used objects, bytes and estimated egress cost of a Task run with a quota, and the reason it was failed or paused when it exceeded the quota.
+//	@Tags			[Task]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.QuotaReport		"Successfully retrieved the quota usage of a Task"
+//	@Failure		404		{object}	models.BasicResponse	"Quota not found"
+//	@Router			/tasks/{id}/quota [get]
+func (tc *TaskController) GetTaskQuotaHandler(ctx echo.Context) error {
+	start := time.Now()
+	logger, logstrings := pageLogInit(ctx, "Get-task-quota", "Get the quota usage of a task", start)
+	id := ctx.Param("id")
+
+	report := &models.QuotaReport{}
+	err := task.LoadReport(task.ReportQuota, id, report)
+	if err == nil {
+		return ctx.JSON(http.StatusOK, report)
+	}
+	// a task within its quota has no report; show the usage of its last run
+	if guard := quota.Lookup(id); guard != nil {
+		usage := guard.Report()
+		usage.TaskID = id
+		return ctx.JSON(http.StatusOK, usage)
+	}
+
+	errStr := err.Error()
+	logger.Error().Err(err).Msg(errStr)
+	return ctx.JSON(http.StatusNotFound, models.BasicResponse{
+		Result: logstrings.String(),
+		Error:  &errStr,
+	})
+}
+
+// GetTaskCharsetHandler godoc
+//
+//	@ID 			GetTaskCharsetHandler
+//	@Summary		Get the charset check of a Task
+//	@Description	Get the indexes of an RDBMS backup or migration whose keys would be too long for the target after the charset conversion, found before the export started.
+//	@Tags			[Task]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.CharsetReport	"Successfully retrieved the charset check of a Task"
+//	@Failure		404		{object}	models.BasicResponse	"Report not found"
+//	@Router			/tasks/{id}/charset [get]
+func (tc *TaskController) GetTaskCharsetHandler(ctx echo.Context) error {
+	return getReport(ctx, "Get-task-charset", "Get the charset check of a task", task.ReportCharset, &models.CharsetReport{})
+}
+
+// CancelTaskHandler godoc
+//
+//	@ID 			CancelTaskHandler
+//	@Summary		Cancel a running Task
+//	@Description	Cancel a running Task using its ID. The task stops at the next object or table and ends in the cancelled status.
+//	@Tags			[Task]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{object}	models.BasicResponse	"Successfully requested the cancellation of the Task"
+//	@Failure		404		{object}	models.BasicResponse	"Task is not running"
+//	@Router			/tasks/{id}/cancel [post]
+func (tc *TaskController) CancelTaskHandler(ctx echo.Context) error {
+	start := time.Now()
+	logger, logstrings := pageLogInit(ctx, "Cancel-task", "Cancel a running task", start)
+	id := ctx.Param("id")
+	if err := tc.TaskService.CancelTask(id); err != nil {
+		errStr := err.Error()
+		logger.Error().Err(err).Msg(errStr)
+		return ctx.JSON(http.StatusNotFound, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	jobEnd(logger, "Successfully requested task cancellation", start)
+	return ctx.JSON(http.StatusOK, models.BasicResponse{
+		Result: logstrings.String(),
+		Error:  nil,
+	})
+}
+
+// ResumeTaskHandler godoc
+//
+//	@ID 			ResumeTaskHandler
+//	@Summary		Resume a Task paused by its quota
+//	@Description	Run a Task paused by its transfer quota again, optionally with a raised quota. The task runs in the background.
+//	@Tags			[Task]
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path	string				true	"Task ID"
+//	@Param			RequestBody	body	models.ResumeParams	false	"Quota that replaces the quota of the Task"
+//	@Success		200			{object}	models.BasicResponse	"Successfully resumed the Task"
+//	@Failure		404			{object}	models.BasicResponse	"Task not found or not paused"
+//	@Router			/tasks/{id}/resume [post]
+func (tc *TaskController) ResumeTaskHandler(ctx echo.Context) error {
+	start := time.Now()
+	logger, logstrings := pageLogInit(ctx, "Resume-task", "Resume a paused task", start)
+	id := ctx.Param("id")
+	params := models.ResumeParams{}
+	if !getDataWithReBind(logger, start, ctx, &params) {
+		errStr := "Invalid request data"
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+	if err := tc.TaskService.ResumeTask(id, params.Quota); err != nil {
+		errStr := err.Error()
+		logger.Error().Err(err).Msg(errStr)
+		return ctx.JSON(http.StatusNotFound, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	jobEnd(logger, "Successfully resumed task", start)
+	return ctx.JSON(http.StatusOK, models.BasicResponse{
+		Result: logstrings.String(),
+		Error:  nil,
+	})
+}
+
 // UpdateTaskHandler godoc
 //
 //	@ID 			UpdateTaskHandler
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Cloud-Barista Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloud-barista/mc-data-manager/models"
+	"github.com/cloud-barista/mc-data-manager/service/task"
+	"github.com/labstack/echo/v4"
+)
+
+// VerifyOSPostHandler godoc
+//
+//	@ID 			VerifyOSPostHandler
+//	@Summary		Verify ObjectStorage against ObjectStorage
+//	@Description	Compare the objects of the source and target object storage and report objects missing on either side, size mismatches and checksum mismatches.
+//	@Tags			[ObjectStorage]
+//	@Accept			json
+//	@Produce		json
+//	@Param			RequestBody		body	models.VerifyTask	true	"Parameters required for verification"
+//	@Success		200			{object}	models.VerifyReport		"Verification report"
+//	@Failure		400			{object}	models.BasicResponse	"operationId missing"
+//	@Failure		409			{object}	models.BasicResponse	"Task with the operationId already exists"
+//	@Failure		500			{object}	models.BasicResponse	"Internal Server Error"
+//	@Router			/objectstorage/verify [post]
+func VerifyOSPostHandler(ctx echo.Context) error {
+	start := time.Now()
+
+	logger, logstrings := pageLogInit(ctx, "verify", "Verify objectstorage to objectstorage", start)
+
+	params := models.DataTask{}
+	if !getDataWithReBind(logger, start, ctx, &params) {
+		return ctx.JSON(http.StatusInternalServerError, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  nil,
+		})
+	}
+
+	if params.OperationId == "" {
+		errStr := "operationId is required, the report is kept per operation"
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusBadRequest, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	params.TaskMeta.TaskID = params.OperationId
+	params.TaskMeta.TaskType = models.Verify
+	params.TaskMeta.ServiceType = models.ObejectStorage
+	manager := task.GetFileScheduleManager()
+	if err := manager.CreateTask(params); err != nil {
+		errStr := err.Error()
+		logger.Error().Msg(errStr)
+		return ctx.JSON(http.StatusConflict, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	// A verification that finds differences fails but still produces a report;
+	// the report of a previous run is removed when the task starts.
+	ok := manager.RunTask(params)
+
+	report := &models.VerifyReport{}
+	if err := task.LoadReport(task.ReportVerify, params.TaskMeta.TaskID, report); err != nil {
+		if !ok {
+			err = fmt.Errorf("verify task failed before producing a report: %w", err)
+		}
+		errStr := err.Error()
+		logger.Error().Err(err).Msg("verify report load failed")
+		return ctx.JSON(http.StatusInternalServerError, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	jobEnd(logger, "Successfully verified data", start)
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// GetVerifyReportHandler godoc
+//
+//	@ID 			GetVerifyReportHandler
+//	@Summary		Download a verification report
+//	@Description	Download the verification report of a verify task or of a migrate task run with verify enabled.
+//	@Tags			[ObjectStorage]
+//	@Produce		json
+//	@Param			id		path	string	true	"Task ID"
+//	@Success		200		{file}		file	"Verification report"
+//	@Failure		404		{object}	models.BasicResponse	"Report not found"
+//	@Router			/objectstorage/verify/{id} [get]
+func GetVerifyReportHandler(ctx echo.Context) error {
+	start := time.Now()
+	logger, logstrings := pageLogInit(ctx, "Get-verify-report", "Download a verify report", start)
+	id := ctx.Param("id")
+
+	if err := task.LoadReport(task.ReportVerify, id, &models.VerifyReport{}); err != nil {
+		errStr := err.Error()
+		logger.Error().Err(err).Msg(errStr)
+		return ctx.JSON(http.StatusNotFound, models.BasicResponse{
+			Result: logstrings.String(),
+			Error:  &errStr,
+		})
+	}
+
+	return ctx.Attachment(task.ReportPath(task.ReportVerify, id), fmt.Sprintf("%s-verify.json", id))
+}
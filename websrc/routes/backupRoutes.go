@@ -34,10 +34,12 @@ func BackupRoutes(g *echo.Group) {
 
 func BackupRoot(g *echo.Group) {
 	g.GET("/register", controllers.BackupHandler)
-	g.GET("", controllers.GetAllBackupHandler)         // Retrieve all tasks
-	g.GET("/:id", controllers.GetBackupHandler)        // Retrieve a single task by ID
-	g.PUT("/:id", controllers.UpdateBackupHandler)     // Update an existing task by ID
-	g.DELETE("/:id", controllers.DeleteBackupkHandler) // Delete a task by ID
+	g.GET("", controllers.GetAllBackupHandler)                     // Retrieve all tasks
+	g.GET("/:id", controllers.GetBackupHandler)                    // Retrieve a single task by ID
+	g.GET("/:id/catalog", controllers.GetBackupCatalogHandler)     // Retrieve the backup catalog of a task
+	g.GET("/:id/preflight", controllers.GetBackupPreflightHandler) // Retrieve the pre-flight check of a backup
+	g.PUT("/:id", controllers.UpdateBackupHandler)                 // Update an existing task by ID
+	g.DELETE("/:id", controllers.DeleteBackupkHandler)             // Delete a task by ID
 
 }
 
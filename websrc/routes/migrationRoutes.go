@@ -43,10 +43,14 @@ func MigrationRoot(g *echo.Group) {
 	g.POST("/objectstorage", controllers.MigrationObjectstoragePostHandler)
 	g.POST("/nrdbms", controllers.MigrationNRDBMSPostHandler)
 	g.POST("/rdbms", controllers.MigrationRDBMSPostHandler)
-	g.GET("", controllers.GetAllMigrateHandler)        // Retrieve all tasks
-	g.GET("/:id", controllers.GetMigrateHandler)       // Retrieve a single task by ID
-	g.PUT("/:id", controllers.UpdateMigrateHandler)    // Update an existing task by ID
-	g.DELETE("/:id", controllers.DeleteBackupkHandler) // Delete a task by ID
+	g.GET("", controllers.GetAllMigrateHandler)                         // Retrieve all tasks
+	g.GET("/:id", controllers.GetMigrateHandler)                        // Retrieve a single task by ID
+	g.GET("/:id/targets", controllers.GetMigrateTargetsHandler)         // Retrieve the per-target results of a fan-out migration
+	g.GET("/:id/transforms", controllers.GetMigrateTransformsHandler)   // Retrieve the transformed objects of a migration
+	g.GET("/:id/pii", controllers.GetMigratePIIHandler)                 // Retrieve the personal data found by a migration
+	g.GET("/:id/translation", controllers.GetMigrateTranslationHandler) // Retrieve the translation report of a migration between engines
+	g.PUT("/:id", controllers.UpdateMigrateHandler)                     // Update an existing task by ID
+	g.DELETE("/:id", controllers.DeleteBackupkHandler)                  // Delete a task by ID
 }
 
 func MigrationFromOnpremiseToObjectStorage(g *echo.Group) {
@@ -27,4 +27,8 @@ func ObjectStorageRoutes(g *echo.Group) {
 	g.POST("/buckets", controllers.ObjectstorageBucketsHandler)
 	g.POST("/buckets/objects", controllers.ObjectstorageObjectListHandler)
 	g.DELETE("/buckets/object", controllers.ObjectstorageDeleteObjectHandler)
+	g.POST("/verify", controllers.VerifyOSPostHandler)
+	g.GET("/verify/:id", controllers.GetVerifyReportHandler)
+	g.POST("/sync", controllers.SyncOSPostHandler)
+	g.GET("/sync/:id", controllers.GetSyncReportHandler)
 }
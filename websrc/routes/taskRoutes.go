@@ -32,10 +32,15 @@ func TaskRoot(g *echo.Group, scheduleManager *task.FileScheduleManager) {
 		TaskService: scheduleManager,
 	}
 
-	g.GET("", taskController.GetAllTasksHandler)       // Retrieve all tasks
-	g.GET("/:id", taskController.GetTaskHandler)       // Retrieve a single task by ID
-	g.POST("", taskController.CreateTaskHandler)       // Create a new task
-	g.PUT("/:id", taskController.UpdateTaskHandler)    // Update an existing task by ID
-	g.DELETE("/:id", taskController.DeleteTaskHandler) // Delete a task by ID
+	g.GET("", taskController.GetAllTasksHandler)                  // Retrieve all tasks
+	g.GET("/:id", taskController.GetTaskHandler)                  // Retrieve a single task by ID
+	g.GET("/:id/progress", taskController.GetTaskProgressHandler) // Retrieve the progress of a task
+	g.GET("/:id/quota", taskController.GetTaskQuotaHandler)       // Retrieve the quota usage of a task
+	g.GET("/:id/charset", taskController.GetTaskCharsetHandler)   // Retrieve the charset check of a task
+	g.POST("/:id/cancel", taskController.CancelTaskHandler)       // Cancel a running task
+	g.POST("/:id/resume", taskController.ResumeTaskHandler)       // Resume a task paused by its quota
+	g.POST("", taskController.CreateTaskHandler)                  // Create a new task
+	g.PUT("/:id", taskController.UpdateTaskHandler)               // Update an existing task by ID
+	g.DELETE("/:id", taskController.DeleteTaskHandler)            // Delete a task by ID
 
 }